@@ -34,3 +34,31 @@ func GerarJWT(id int32) (string, error) {
 
 
 }
+
+// TipoFuncionario marca o token do portal do funcionario. Ele não vale nas rotas dos usuarios
+// do sistema, e o token de usuario não vale no portal
+const TipoFuncionario = "funcionario"
+
+// GerarJWTFuncionario leva a versão do PIN do login: trocar o PIN derruba os tokens anteriores
+func GerarJWTFuncionario(id int32, tenantId int32, pinVersao int32) (string, error) {
+
+	secret := os.Getenv("JWT_SECRET")
+
+	if secret == "" {
+		return "", errors.New("JWT nao configurado")
+	}
+
+	claim := jwt.MapClaims{
+
+		"sub":    id,       //id do funcionario
+		"tenant": tenantId, // o token só vale na empresa em que foi emitido
+		"tipo":   TipoFuncionario,
+		"pin":    pinVersao,
+		"exp":    time.Now().Add(time.Hour * 12).Unix(), // um turno de trabalho
+		"iat":    time.Now().Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
+
+	return token.SignedString([]byte(secret))
+}
@@ -0,0 +1,57 @@
+// Comando para importar a base do CAEPI. O catálogo é o mesmo para todas as empresas,
+// por isso a carga não fica exposta na API dos tenants (pela API, só com o OPERADOR_TOKEN):
+//
+//	go run ./cmd/importar-caepi -arquivo tgg_export_caepi.zip
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/service"
+)
+
+func main() {
+
+	caminho := flag.String("arquivo", "", "arquivo .txt ou .zip do CAEPI")
+	flag.Parse()
+
+	if *caminho == "" {
+		log.Fatal("informe o arquivo do CAEPI com -arquivo")
+	}
+
+	init := configs.Init{Conexao: &configs.ConexaoDbPostgres{}}
+
+	db, err := init.InitAplicattion()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	arquivo, err := os.Open(*caminho)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer arquivo.Close()
+
+	registros, ignoradas, err := helper.LerCaepi(*caminho, arquivo)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	caepi := service.NewCaepiService(repository.NewCaepiRepository(db), db)
+
+	resultado, err := caepi.Importar(context.Background(), filepath.Base(*caminho), registros, ignoradas)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("importação %d do CAEPI: %d CAs (%d novos, %d alterados), %d linhas ignoradas, %d epis sinalizados",
+		resultado.ID, resultado.Total, resultado.Novos, resultado.Alterados, resultado.Ignoradas, resultado.EpisSinalizados)
+}
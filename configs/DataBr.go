@@ -26,6 +26,12 @@ func (d *DataBr) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// UnmarshalParam é o que o gin usa para query string e form (ShouldBindQuery); sem ele a data
+// "dd/mm/aaaa" cai no json.Unmarshal e nunca é aceita
+func (d *DataBr) UnmarshalParam(param string) error {
+	return d.UnmarshalJSON([]byte(param))
+}
+
 // Opcional: Para devolver o JSON no formato brasileiro também
 func (d *DataBr) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf("\"%s\"", time.Time(*d).Format("02/01/2006"))), nil
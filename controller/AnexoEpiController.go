@@ -0,0 +1,311 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type AnexoEpiService interface {
+	Adicionar(ctx context.Context, idEpi int, input model.AnexoEpiInserir, nomeArquivo string, tamanho int64, conteudo io.Reader, idUsuario int, tenantId int32) (model.AnexoEpiDto, error)
+	Listar(ctx context.Context, idEpi int, tenantId int32) ([]model.AnexoEpiDto, error)
+	Abrir(ctx context.Context, idEpi, idAnexo int, tenantId int32) (model.AnexoEpiDto, io.ReadCloser, error)
+	Remover(ctx context.Context, idEpi, idAnexo int, tenantId int32) error
+}
+
+type AnexoEpiController struct {
+	service AnexoEpiService
+}
+
+func NewAnexoEpiController(service AnexoEpiService) *AnexoEpiController {
+
+	return &AnexoEpiController{service: service}
+}
+
+// Adicionar godoc
+// @Summary      Anexar arquivo ao epi
+// @Description  Foto do produto (jpeg, png ou webp), certificado do CA, manual ou ficha técnica (pdf, jpeg ou png), até 10MB. A primeira foto vira a principal
+// @Tags         epi
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id         path      int     true   "ID do epi"
+// @Param        arquivo    formData  file    true   "Arquivo"
+// @Param        tipo       formData  string  true   "foto, certificado_ca, manual ou ficha_tecnica"
+// @Param        principal  formData  bool    false  "Tornar esta foto a principal"
+// @Success      201  {object}  model.AnexoEpiDto
+// @Failure      400  {object}  helper.HTTPError "Arquivo inválido"
+// @Failure      404  {object}  helper.HTTPError "Epi não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /epi/{id}/anexos [post]
+// @Security     BearerAuth
+func (a *AnexoEpiController) Adicionar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		// folga para os outros campos do formulário
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, helper.TamanhoMaximoAnexo+(1<<20))
+
+		arquivo, err := ctx.FormFile("arquivo")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "envie o arquivo no campo arquivo (maximo 10MB)",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		var input model.AnexoEpiInserir
+		if err := ctx.ShouldBind(&input); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados inválidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		idUser, existe := ctx.Get("userId")
+		if !existe {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token inválido ou sem id",
+			})
+			return
+		}
+
+		conteudo, err := arquivo.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "erro ao abrir o arquivo",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+		defer conteudo.Close()
+
+		anexo, err := a.service.Adicionar(ctx, id, input, arquivo.Filename, arquivo.Size, conteudo, int(idUser.(uint)), tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) || errors.Is(err, helper.ErrAnexoInvalido) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "epi nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao salvar o anexo",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, anexo)
+	}
+}
+
+// Listar godoc
+// @Summary      Anexos do epi
+// @Description  Fotos e documentos do epi, com a foto principal primeiro
+// @Tags         epi
+// @Produce      json
+// @Param        id   path      int  true  "ID do epi"
+// @Success      200  {array}   model.AnexoEpiDto
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      404  {object}  helper.HTTPError "Epi não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /epi/{id}/anexos [get]
+// @Security     BearerAuth
+func (a *AnexoEpiController) Listar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		anexos, err := a.service.Listar(ctx, id, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "epi nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro interno ao listar anexos",
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, anexos)
+	}
+}
+
+// Baixar godoc
+// @Summary      Baixar anexo do epi
+// @Description  Devolve o arquivo com o tipo detectado no upload
+// @Tags         epi
+// @Produce      octet-stream
+// @Param        id      path      int  true  "ID do epi"
+// @Param        anexo   path      int  true  "ID do anexo"
+// @Success      200     {file}    file
+// @Failure      400     {object}  helper.HTTPError "ID inválido"
+// @Failure      404     {object}  helper.HTTPError "Não encontrado"
+// @Failure      500     {object}  helper.HTTPError "Erro interno"
+// @Router       /epi/{id}/anexos/{anexo} [get]
+// @Security     BearerAuth
+func (a *AnexoEpiController) Baixar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, errEpi := strconv.Atoi(ctx.Param("id"))
+		idAnexo, errAnexo := strconv.Atoi(ctx.Param("anexo"))
+		if errEpi != nil || errAnexo != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id do epi e do anexo devem ser numeros",
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		anexo, arquivo, err := a.service.Abrir(ctx, id, idAnexo, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "anexo nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro interno ao abrir o anexo",
+			})
+			return
+		}
+		defer arquivo.Close()
+
+		ctx.DataFromReader(http.StatusOK, anexo.Tamanho, anexo.Mime, arquivo, map[string]string{
+			"Content-Disposition":    mime.FormatMediaType("inline", map[string]string{"filename": anexo.NomeArquivo}),
+			"X-Content-Type-Options": "nosniff",
+		})
+	}
+}
+
+// Remover godoc
+// @Summary      Remover anexo do epi
+// @Description  Apaga o anexo; se era a foto principal, a foto mais recente que sobrou assume
+// @Tags         epi
+// @Produce      json
+// @Param        id      path      int  true  "ID do epi"
+// @Param        anexo   path      int  true  "ID do anexo"
+// @Success      200     {object}  map[string]string "Sucesso"
+// @Failure      400     {object}  helper.HTTPError "ID inválido"
+// @Failure      404     {object}  helper.HTTPError "Não encontrado"
+// @Failure      500     {object}  helper.HTTPError "Erro interno"
+// @Router       /epi/{id}/anexos/{anexo} [delete]
+// @Security     BearerAuth
+func (a *AnexoEpiController) Remover() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, errEpi := strconv.Atoi(ctx.Param("id"))
+		idAnexo, errAnexo := strconv.Atoi(ctx.Param("anexo"))
+		if errEpi != nil || errAnexo != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id do epi e do anexo devem ser numeros",
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		err := a.service.Remover(ctx, id, idAnexo, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "anexo nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro interno ao remover o anexo",
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "anexo removido"})
+	}
+}
@@ -0,0 +1,241 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// tamanhoMaximoCaepi: o .txt completo do CAEPI passa de 100MB (o .zip fica bem menor)
+const tamanhoMaximoCaepi = 200 << 20
+
+type CaepiService interface {
+	Importar(ctx context.Context, nomeArquivo string, registros []helper.RegistroCaepi, ignoradas int) (model.ResultadoImportacaoCaepi, error)
+	BuscarCa(ctx context.Context, ca string) (model.CatalogoCaDto, error)
+	ListarAlertas(ctx context.Context, todos bool, tenantId int32) ([]model.AlertaCaDto, error)
+	ResolverAlerta(ctx context.Context, id int, tenantId int32) error
+}
+
+// CaepiController: o catálogo é compartilhado entre as empresas, então a importação só passa pelo
+// token do operador do sistema (ou pelo comando cmd/importar-caepi); as consultas são dos tenants
+type CaepiController struct {
+	service CaepiService
+}
+
+func NewCaepiController(service CaepiService) *CaepiController {
+
+	return &CaepiController{service: service}
+}
+
+// Importar godoc
+// @Summary      Importar base do CAEPI
+// @Description  Atualiza o catálogo de CAs (compartilhado entre as empresas) com o arquivo do Ministério do Trabalho e sinaliza os epis cujo CA foi cancelado ou renovado
+// @Tags         caepi
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        arquivo  formData  file  true  "Arquivo .txt ou .zip do CAEPI"
+// @Success      201  {object}  model.ResultadoImportacaoCaepi
+// @Param        X-Operador-Token  header  string  true  "Token do operador do sistema (OPERADOR_TOKEN)"
+// @Failure      400  {object}  helper.HTTPError "Arquivo inválido"
+// @Failure      401  {object}  helper.HTTPError "Token de operador inválido"
+// @Failure      404  {object}  helper.HTTPError "Importação desativada (OPERADOR_TOKEN não definido)"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /importacao-caepi [post]
+func (c *CaepiController) Importar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, tamanhoMaximoCaepi)
+
+		arquivo, err := ctx.FormFile("arquivo")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "envie o arquivo do CAEPI no campo arquivo (maximo 200MB)",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		conteudo, err := arquivo.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "erro ao abrir o arquivo",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+		defer conteudo.Close()
+
+		registros, ignoradas, err := helper.LerCaepi(arquivo.Filename, conteudo)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		resultado, err := c.service.Importar(ctx, arquivo.Filename, registros, ignoradas)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrArquivoCaepi) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao importar o CAEPI",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, resultado)
+	}
+}
+
+// BuscarCa godoc
+// @Summary      Consultar CA no catálogo
+// @Description  Dados do CA na última importação do CAEPI (situação, validade, fabricante e descrição)
+// @Tags         caepi
+// @Produce      json
+// @Param        ca   path      string  true  "Número do CA"
+// @Success      200  {object}  model.CatalogoCaDto
+// @Failure      404  {object}  helper.HTTPError "CA não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /catalogo-ca/{ca} [get]
+// @Security     BearerAuth
+func (c *CaepiController) BuscarCa() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		ca, err := c.service.BuscarCa(ctx, ctx.Param("ca"))
+		if err != nil {
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "CA nao encontrado no catalogo do CAEPI",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, ca)
+	}
+}
+
+// ListarAlertas godoc
+// @Summary      Alertas de CA
+// @Description  Epis da empresa cujo CA foi cancelado/suspenso ou renovado nas importações do CAEPI; por padrão só os não resolvidos
+// @Tags         caepi
+// @Produce      json
+// @Param        todos  query     bool  false  "Incluir os já resolvidos"
+// @Success      200    {array}   model.AlertaCaDto
+// @Failure      400    {object}  helper.HTTPError "Filtro inválido"
+// @Failure      500    {object}  helper.HTTPError "Erro interno"
+// @Router       /alertas-ca [get]
+// @Security     BearerAuth
+func (c *CaepiController) ListarAlertas() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		todos := false
+		if t := ctx.Query("todos"); t != "" {
+
+			var err error
+			todos, err = strconv.ParseBool(t)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": "todos deve ser true ou false",
+				})
+				return
+			}
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		alertas, err := c.service.ListarAlertas(ctx, todos, tenantID)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro interno ao listar alertas de CA",
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, alertas)
+	}
+}
+
+// ResolverAlerta godoc
+// @Summary      Resolver alerta de CA
+// @Description  Marca o alerta como tratado (epi trocado, validade conferida etc.)
+// @Tags         caepi
+// @Produce      json
+// @Param        id   path      int  true  "ID do alerta"
+// @Success      200  {object}  map[string]string "Sucesso"
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      404  {object}  helper.HTTPError "Não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /alerta-ca/{id}/resolver [post]
+// @Security     BearerAuth
+func (c *CaepiController) ResolverAlerta() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		err = c.service.ResolverAlerta(ctx, id, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "alerta nao encontrado ou ja resolvido",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro interno ao resolver alerta de CA",
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "alerta resolvido"})
+	}
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/service"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type ConsumoService interface {
+	Relatorio(ctx context.Context, f service.FiltroConsumo, tenantId int32) (model.RelatorioConsumoDto, error)
+}
+
+type ConsumoController struct {
+	service ConsumoService
+}
+
+func NewConsumoController(service ConsumoService) *ConsumoController {
+
+	return &ConsumoController{service: service}
+}
+
+// Relatorio godoc
+// @Summary      Relatorio de consumo
+// @Description  Quantidade e custo dos epis entregues no período, agrupados pelas categorias da NR-6 e pelos tipos de proteção da empresa
+// @Tags         relatorios
+// @Produce      json
+// @Param        data_inicio   query     string  true   "data inicial (dd/mm/aaaa)"
+// @Param        data_fim      query     string  true   "data final (dd/mm/aaaa)"
+// @Param        departamento  query     int     false  "ID do departamento"
+// @Success      200  {object}  model.RelatorioConsumoDto
+// @Failure      400  {object}  helper.HTTPError "Período inválido"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /relatorio-consumo [get]
+// @Security     BearerAuth
+func (c *ConsumoController) Relatorio() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var filtro service.FiltroConsumo
+		if err := ctx.ShouldBindQuery(&filtro); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "parametros invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		relatorio, err := c.service.Relatorio(ctx, filtro, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrPeriodoInvalido) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, relatorio)
+	}
+}
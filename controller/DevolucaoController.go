@@ -0,0 +1,508 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/service"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type DevolucaoService interface {
+	SalvarDevolucao(ctx context.Context, modelDevolucao model.DevolucaoInserir, tenantId int32) error
+	ListarDevolucoes(ctx context.Context, f service.FiltroDevolucao, tenantId int32) (service.DevolucaoPaginada, error)
+	BuscarDevolucao(ctx context.Context, id int, tenantId int32) (model.DevolucaoDto, error)
+	CancelarDevolucao(ctx context.Context, id, iduser, tenatId int) error
+	SalvarDocumento(ctx context.Context, doc model.DevolucaoDocumentoInserir, idUsuario int, tenantId int32) (int, error)
+	BuscarDocumento(ctx context.Context, id int, tenantId int32) (model.DevolucaoDocumentoDto, error)
+	CancelarDocumento(ctx context.Context, id, idUsuario int, tenantId int32) error
+}
+
+type DevolucaoController struct {
+	service DevolucaoService
+}
+
+func NewDevolucaoController(service DevolucaoService) *DevolucaoController {
+
+	return &DevolucaoController{service: service}
+}
+
+// Adicionar godoc
+// @Summary      Registrar devolução
+// @Description  Registra a devolução de um epi. Quando for troca, já gera a entrega do epi novo
+// @Tags         devolucoes
+// @Accept       json
+// @Produce      json
+// @Param        devolucao body model.DevolucaoInserir true "Dados da devolução"
+// @Success      201  {object}  map[string]string
+// @Failure      400  {object}  helper.HTTPError "Dados inválidos"
+// @Failure      422  {object}  helper.HTTPError "funcionario ou motivo inexistente / epi não está em posse do funcionario / estoque insuficiente para a troca / sem lote ativo para receber o item"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /cadastro-devolucao [post]
+// @Security     BearerAuth
+func (d *DevolucaoController) Adicionar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var input model.DevolucaoInserir
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		err := d.service.SalvarDevolucao(ctx, input, tenantId)
+		if err != nil {
+			erroAoRegistrarDevolucao(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{"mensagem": "devolução cadastrada com sucesso"})
+	}
+}
+
+// Listar godoc
+// @Summary      Listar devoluções
+// @Description  Lista as devoluções de forma paginada, com filtros opcionais
+// @Tags         devolucoes
+// @Produce      json
+// @Param        canceladas    query     bool    false  "traz só as canceladas"
+// @Param        devolucao_id  query     int     false  "id da devolução"
+// @Param        matricula     query     string  false  "matricula do funcionario"
+// @Param        data_inicio   query     string  false  "data inicial (dd/mm/aaaa)"
+// @Param        data_fim      query     string  false  "data final (dd/mm/aaaa)"
+// @Param        pagina        query     int     false  "pagina"
+// @Param        quantidade    query     int     false  "itens por pagina"
+// @Success      200  {object}  service.DevolucaoPaginada
+// @Failure      400  {object}  helper.HTTPError "Parametros invalidos"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /devolucoes [get]
+// @Security     BearerAuth
+func (d *DevolucaoController) Listar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var filtro service.FiltroDevolucao
+
+		if err := ctx.ShouldBindQuery(&filtro); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "parametros de busca invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro ao receber tenantId",
+			})
+			return
+		}
+
+		if filtro.Pagina <= 0 {
+			filtro.Pagina = 1
+		}
+		if filtro.Quantidade <= 0 {
+			filtro.Quantidade = 10
+		}
+
+		devolucoes, err := d.service.ListarDevolucoes(ctx, filtro, tenantId)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao buscar as devoluções",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, devolucoes)
+	}
+}
+
+// Detalhe godoc
+// @Summary      Buscar devolução
+// @Description  Retorna uma devolução pelo id, ativa ou cancelada
+// @Tags         devolucoes
+// @Produce      json
+// @Param        id   path      int  true  "ID da devolução"
+// @Success      200  {object}  model.DevolucaoDto
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      404  {object}  helper.HTTPError "Não encontrada"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /devolucao/{id} [get]
+// @Security     BearerAuth
+func (d *DevolucaoController) Detalhe() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		devolucao, err := d.service.BuscarDevolucao(ctx, id, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "devolução não encontrada",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao buscar devolução",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, devolucao)
+	}
+}
+
+// Cancelar godoc
+// @Summary      Cancelar devolução
+// @Description  Cancela a devolução, tira do lote o item que tinha voltado ao estoque e, se ela foi uma troca, cancela a entrega do epi novo e repõe o estoque
+// @Tags         devolucoes
+// @Param        id   path      int  true  "ID da devolução"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      404  {object}  helper.HTTPError "Não encontrada, já cancelada ou item de documento"
+// @Failure      409  {object}  helper.HTTPError "Ordem de higienização/inspeção já concluída / item devolvido já saiu do estoque"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /devolucao/{id} [delete]
+// @Security     BearerAuth
+func (d *DevolucaoController) Cancelar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		idUser, existe := ctx.Get("userId")
+		if !existe {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token inválido ou sem id",
+			})
+			return
+		}
+
+		err = d.service.CancelarDevolucao(ctx, id, int(idUser.(uint)), int(tenantId))
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "devolução não encontrada, já cancelada ou parte de um documento (cancele o documento)",
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrOrdemConcluida) || errors.Is(err, helper.ErrEstoqueInsuficiente) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao cancelar devolução",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "devolução cancelada com sucesso"})
+	}
+}
+
+// AdicionarDocumento godoc
+// @Summary      Registrar documento de devolução
+// @Description  Devolve vários itens com uma assinatura só, cada um com seu motivo e troca opcional. As trocas saem numa única entrega
+// @Tags         devolucoes
+// @Accept       json
+// @Produce      json
+// @Param        documento body model.DevolucaoDocumentoInserir true "Itens devolvidos"
+// @Success      201  {object}  map[string]any
+// @Failure      400  {object}  helper.HTTPError "Dados inválidos"
+// @Failure      422  {object}  helper.HTTPError "funcionario ou motivo inexistente / epi não está em posse do funcionario / estoque insuficiente para a troca / sem lote ativo para receber o item"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /cadastro-devolucao-documento [post]
+// @Security     BearerAuth
+func (d *DevolucaoController) AdicionarDocumento() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var input model.DevolucaoDocumentoInserir
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		idUser, existe := ctx.Get("userId")
+		if !existe {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token inválido ou sem id",
+			})
+			return
+		}
+
+		id, err := d.service.SalvarDocumento(ctx, input, int(idUser.(uint)), tenantId)
+		if err != nil {
+			erroAoRegistrarDevolucao(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{
+			"mensagem": "documento de devolução cadastrado com sucesso",
+			"id":       id,
+		})
+	}
+}
+
+// DetalheDocumento godoc
+// @Summary      Buscar documento de devolução
+// @Description  Retorna o documento com todos os itens devolvidos e as trocas
+// @Tags         devolucoes
+// @Produce      json
+// @Param        id   path      int  true  "ID do documento"
+// @Success      200  {object}  model.DevolucaoDocumentoDto
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      404  {object}  helper.HTTPError "Não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /devolucao-documento/{id} [get]
+// @Security     BearerAuth
+func (d *DevolucaoController) DetalheDocumento() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		doc, err := d.service.BuscarDocumento(ctx, id, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "documento de devolução não encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao buscar documento de devolução",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, doc)
+	}
+}
+
+// CancelarDocumento godoc
+// @Summary      Cancelar documento de devolução
+// @Description  Cancela todos os itens do documento, tira do lote o que tinha voltado ao estoque e cancela a entrega das trocas, repondo o estoque dela
+// @Tags         devolucoes
+// @Param        id   path      int  true  "ID do documento"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      404  {object}  helper.HTTPError "Não encontrado ou já cancelado"
+// @Failure      409  {object}  helper.HTTPError "Ordem de higienização/inspeção já concluída / item devolvido já saiu do estoque"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /devolucao-documento/{id} [delete]
+// @Security     BearerAuth
+func (d *DevolucaoController) CancelarDocumento() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		idUser, existe := ctx.Get("userId")
+		if !existe {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token inválido ou sem id",
+			})
+			return
+		}
+
+		err = d.service.CancelarDocumento(ctx, id, int(idUser.(uint)), tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "documento de devolução não encontrado ou já cancelado",
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrOrdemConcluida) || errors.Is(err, helper.ErrEstoqueInsuficiente) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao cancelar documento de devolução",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "documento de devolução cancelado com sucesso"})
+	}
+}
+
+// erroAoRegistrarDevolucao traduz os erros de gravação, que são os mesmos na devolução avulsa e no documento
+func erroAoRegistrarDevolucao(ctx *gin.Context, err error) {
+
+	if errors.Is(err, helper.ErrCampoObrigatorio) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if errors.Is(err, helper.ErrNaoEncontrado) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":    "funcionario ou motivo de devolução não encontrado",
+			"detalhes": err.Error(),
+		})
+		return
+	}
+
+	if errors.Is(err, helper.ErrConflitoIntegridade) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":    "epi, tamanho ou motivo informado não existe",
+			"detalhes": err.Error(),
+		})
+		return
+	}
+
+	if errors.Is(err, helper.ErrEstoqueInsuficiente) || errors.Is(err, helper.ErrDevolucaoSemPosse) ||
+		errors.Is(err, helper.ErrReservaInvalida) || errors.Is(err, helper.ErrSemTreinamento) ||
+		errors.Is(err, helper.ErrSemLoteDevolucao) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusInternalServerError, gin.H{
+		"error":    "erro ao registrar devolução",
+		"detalhes": err.Error(),
+	})
+}
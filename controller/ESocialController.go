@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type ESocialService interface {
+	DefinirFuncao(ctx context.Context, idFuncao int, dados model.FuncaoEsocial, tenantId int32) error
+	ExportarS2240(ctx context.Context, req model.ExportacaoS2240, tenantId int32) ([]byte, []model.ErroExportacaoS2240, error)
+}
+
+type ESocialController struct {
+	service ESocialService
+}
+
+func NewESocialController(service ESocialService) *ESocialController {
+
+	return &ESocialController{service: service}
+}
+
+// DefinirFuncao godoc
+// @Summary      Dados da função para o eSocial
+// @Description  Define o agente nocivo (tabela 24) e a descrição das atividades da função, usados no S-2240
+// @Tags         esocial
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                  true  "ID da função"
+// @Param        body  body      model.FuncaoEsocial  true  "Dados do eSocial"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  helper.HTTPError "Dados inválidos"
+// @Failure      404   {object}  helper.HTTPError "Função não encontrada"
+// @Failure      500   {object}  helper.HTTPError "Erro interno"
+// @Router       /funcao/{id}/esocial [put]
+// @Security     BearerAuth
+func (e *ESocialController) DefinirFuncao() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		var input model.FuncaoEsocial
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		err = e.service.DefinirFuncao(ctx, id, input, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "funcao nao encontrada",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao salvar os dados do eSocial da funcao",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "dados do eSocial da funcao salvos"})
+	}
+}
+
+// ExportarS2240 godoc
+// @Summary      Exportar S-2240 do eSocial
+// @Description  Gera um xml do evento S-2240 por funcionario (e função da época) com os CAs dos epis entregues no período, confere os campos que vêm do cadastro, valida cada xml contra o XSD oficial do leiaute S-1.2 e devolve um zip para a contabilidade transmitir. Se algum evento tiver pendência, nada é gerado e a lista de erros volta
+// @Tags         esocial
+// @Accept       json
+// @Produce      application/zip
+// @Param        body  body      model.ExportacaoS2240  true  "Período, funcionarios e responsável pelos registros ambientais"
+// @Success      200   {file}    file
+// @Failure      400   {object}  helper.HTTPError "Dados ou período inválidos"
+// @Failure      404   {object}  helper.HTTPError "Nenhuma entrega no período"
+// @Failure      422   {array}   model.ErroExportacaoS2240 "Funcionarios com pendências (cadastro ou XSD)"
+// @Failure      500   {object}  helper.HTTPError "Erro interno"
+// @Failure      503   {object}  helper.HTTPError "XSD do eSocial ou xmllint indisponível no servidor"
+// @Router       /esocial/s2240 [post]
+// @Security     BearerAuth
+func (e *ESocialController) ExportarS2240() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var input model.ExportacaoS2240
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		arquivo, pendencias, err := e.service.ExportarS2240(ctx, input, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrPeriodoInvalido) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "nenhuma entrega de epi no periodo para os funcionarios informados",
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrXsdEsocial) {
+				ctx.JSON(http.StatusServiceUnavailable, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrExportacaoEsocial) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":        err.Error(),
+					"funcionarios": pendencias,
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao gerar o S-2240",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		nome := fmt.Sprintf("S-2240_%s.zip", time.Now().Format("20060102150405"))
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", nome))
+		ctx.Data(http.StatusOK, "application/zip", arquivo)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
@@ -14,7 +13,8 @@ import (
 )
 
 type EntregasService interface {
-	Salvar(ctx context.Context, model model.EntregaParaInserir, tenantid int32) error
+	Salvar(ctx context.Context, model model.EntregaParaInserir, tenantid int32) (bool, error)
+	SalvarLote(ctx context.Context, lote model.EntregaLoteInserir, tenantId int32) (model.ResultadoEntregaLote, error)
 	ListaEntregas(ctx context.Context, f service.FiltroEntregas, tenantId int32) (service.EntregaPaginada, error)
 	CancelarEntrega(ctx context.Context, tenantId, id, iduser int) error
 }
@@ -54,7 +54,7 @@ func (e *EntregaController) Adicionar() gin.HandlerFunc {
 			return
 		}
 
-		err := e.Service.Salvar(ctx, input, tenantId)
+		pendenciaTreinamento, err := e.Service.Salvar(ctx, input, tenantId)
 		if err != nil {
 
 			if errors.Is(err, helper.ErrNaoEncontrado) {
@@ -65,7 +65,7 @@ func (e *EntregaController) Adicionar() gin.HandlerFunc {
 				return
 			}
 
-			if strings.Contains(err.Error(), "estoque insuficiente") {
+			if errors.Is(err, helper.ErrEstoqueInsuficiente) {
 
 				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
 					"error":    err.Error(),
@@ -75,6 +75,13 @@ func (e *EntregaController) Adicionar() gin.HandlerFunc {
 
 			}
 
+			if errors.Is(err, helper.ErrReservaInvalida) || errors.Is(err, helper.ErrSemTreinamento) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
 			ctx.JSON(http.StatusInternalServerError, gin.H{
 
 				"detalhes": err.Error(),
@@ -82,7 +89,77 @@ func (e *EntregaController) Adicionar() gin.HandlerFunc {
 			return
 		}
 
+		if pendenciaTreinamento {
+			ctx.JSON(http.StatusOK, gin.H{
+				"mensagem":              "entrega cadastrada com sucesso",
+				"pendencia_treinamento": true,
+				"aviso":                 "o funcionario não tem treinamento válido para algum epi entregue; a entrega ficou registrada com pendência",
+			})
+			return
+		}
+
 		ctx.JSON(http.StatusOK, gin.H{"mensagem": "entrega cadastrada com sucesso"})
 
 	}
 }
+
+func (e *EntregaController) AdicionarLote() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var input model.EntregaLoteInserir
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		resultado, err := e.Service.SalvarLote(ctx, input, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrLoteSemDestino) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":    "nenhum funcionario ativo encontrado para o lote",
+					"detalhes": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		// 207 quando parte do lote entrou e parte falhou
+		status := http.StatusOK
+		if !resultado.Efetivado {
+			status = http.StatusUnprocessableEntity
+		} else if resultado.Falhas > 0 {
+			status = http.StatusMultiStatus
+		}
+
+		ctx.JSON(status, resultado)
+	}
+}
@@ -86,6 +86,24 @@ func (e *EpiController) AdicionarEpi() gin.HandlerFunc {
 				return
 			}
 
+			if errors.Is(err, helper.ErrCampoObrigatorio) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+
+					"error":    "dados invalidos",
+					"detalhes": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrCaCancelado) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+
+					"error":    "CA cancelado ou suspenso",
+					"detalhes": err.Error(),
+				})
+				return
+			}
+
 			if errors.Is(err, helper.ErrConflitoIntegridade) {
 				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
 
@@ -294,6 +312,16 @@ func (e *EpiController) AtualizaEpi() gin.HandlerFunc {
 				return
 			}
 
+			if errors.Is(err, helper.ErrCaCancelado) {
+
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+
+					"error":    "CA cancelado ou suspenso",
+					"detalhes": err.Error(),
+				})
+				return
+			}
+
 			if errors.Is(err, helper.ErrConflitoIntegridade) {
 
 				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
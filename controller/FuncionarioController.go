@@ -8,6 +8,7 @@ import (
 
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/service"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
 	"github.com/gin-gonic/gin"
 )
@@ -15,11 +16,18 @@ import (
 type FuncionarioService interface {
 	SalvarFuncionario(ctx context.Context, model model.FuncionarioINserir, tenantId int32) error
 	ListarFuncionario(ctx context.Context, matricula string, tenantId int32) (model.Funcionario_Dto, error)
-	ListaTodosFuncionarios(ctx context.Context, tenantId int32) ([]model.Funcionario_Dto, error)
+	ListaTodosFuncionarios(ctx context.Context, filtro service.FiltroFuncionarios, tenantId int32) (service.FuncionarioPaginado, error)
 	DeletarFuncionario(ctx context.Context, id int, tenantId int32) error
 	AtualizarFuncionarioCompleto(ctx context.Context, id int, req model.UpdateFuncionarioRequest, tenantId int) error
+	Desligar(ctx context.Context, id int, req model.DesligamentoFuncionario, idUsuario int, tenantId int32) ([]model.PosseEpiDto, error)
+	ImportarFuncionarios(ctx context.Context, linhas [][]string, opcoes model.ImportacaoFuncionarioOpcoes, tenantId int32) (model.ResultadoImportacaoFuncionarios, error)
+	HistoricoCargos(ctx context.Context, id int, tenantId int32) ([]model.CargoHistoricoDto, error)
+	DadosPessoais(ctx context.Context, id int, idUsuario int, tenantId int32) (model.DadosPessoaisFuncionarioDto, error)
 }
 
+// tamanhoMaximoImportacao limita o arquivo da importação; 5MB passa com folga de dezenas de milhares de linhas
+const tamanhoMaximoImportacao = 5 << 20
+
 type FuncionarioController struct {
 	Service FuncionarioService
 }
@@ -40,7 +48,7 @@ func NewFuncionarioController(service FuncionarioService) *FuncionarioController
 // @Param        funcionario body model.FuncionarioINserir true "Dados do funcionario"
 // @Success      201  {object}  map[string]string
 // @Failure      400  {object}  helper.HTTPError "Dados inválidos"
-// @Failure      409  {object}  helper.HTTPError "Departamento já existe"
+// @Failure      409  {object}  helper.HTTPError "Matrícula ou CPF já cadastrado"
 // @Failure      500  {object}  helper.HTTPError "Erro interno"
 // @Router       /cadastro-funcionario [post]
 // @Security     BearerAuth
@@ -63,6 +71,11 @@ func (f *FuncionarioController) Adicionar() gin.HandlerFunc {
 			Matricula:       input.Matricula,
 			ID_departamento: input.ID_departamento,
 			ID_funcao:       input.ID_funcao,
+			DataAdmissao:    input.DataAdmissao,
+			Cpf:             input.Cpf,
+			DataNascimento:  input.DataNascimento,
+			Email:           input.Email,
+			Telefone:        input.Telefone,
 		}
 		tenantID, ok := middleware.GetTenantID(ctx)
 		if !ok {
@@ -73,6 +86,13 @@ func (f *FuncionarioController) Adicionar() gin.HandlerFunc {
 		err := f.Service.SalvarFuncionario(ctx, novoFunc, tenantID)
 		if err != nil {
 
+			if errors.Is(err, helper.ErrCpfDuplicado) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
 			if errors.Is(err, helper.ErrDadoDuplicado) {
 				ctx.JSON(http.StatusConflict, gin.H{
 
@@ -106,11 +126,21 @@ func (f *FuncionarioController) Adicionar() gin.HandlerFunc {
 }
 
 // ListarFuncionarios godoc
-// @Summary      Listar todos
-// @Description  Retorna uma lista com todos os funcionarios
+// @Summary      Listar funcionarios
+// @Description  Retorna os funcionarios paginados, com filtros por departamento, função, nome (sem diferenciar acento) e matricula
 // @Tags         funcionarios
 // @Produce      json
-// @Success      200  {array}   model.Funcionario_Dto
+// @Param        pagina           query     int     false  "Pagina" default(1)
+// @Param        limite           query     int     false  "Itens por pagina" default(10)
+// @Param        departamento_id  query     int     false  "ID do departamento"
+// @Param        funcao_id        query     int     false  "ID da função"
+// @Param        nome             query     string  false  "Parte do nome"
+// @Param        matricula        query     string  false  "Parte da matricula"
+// @Param        situacao         query     string  false  "ativos (padrão), desligados ou todos" Enums(ativos, desligados, todos)
+// @Param        ordenar          query     string  false  "Campo de ordenação" Enums(nome, matricula, departamento, funcao)
+// @Param        decrescente      query     bool    false  "Ordem decrescente"
+// @Success      200  {object}  service.FuncionarioPaginado
+// @Failure      400  {object}  helper.HTTPError "Parametros inválidos"
 // @Failure      500  {object}  helper.HTTPError "Erro interno"
 // @Router       /funcionarios [get]
 // @Security     BearerAuth
@@ -124,7 +154,17 @@ func (f *FuncionarioController) ListarFuncionarios() gin.HandlerFunc {
 			return
 		}
 
-		funcs, err := f.Service.ListaTodosFuncionarios(ctx, tenantID)
+		var filtro service.FiltroFuncionarios
+
+		if err := ctx.ShouldBindQuery(&filtro); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "parametros de busca invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		funcs, err := f.Service.ListaTodosFuncionarios(ctx, filtro, tenantID)
 		if err != nil {
 
 			ctx.JSON(http.StatusInternalServerError, gin.H{
@@ -147,13 +187,15 @@ func (f *FuncionarioController) ListarFuncionarios() gin.HandlerFunc {
 // @Failure      400  {object}  helper.HTTPError "ID inválido"
 // @Failure      404  {object}  helper.HTTPError "Não encontrado"
 // @Failure      500  {object}  helper.HTTPError "Erro interno"
-// @Router       /funcionario/{matricula} [get]
+// @Router       /funcionario/{id} [get]
 // @Security     BearerAuth
 func (f *FuncionarioController) ListarFuncionarioPorMatricula() gin.HandlerFunc {
 
 	return func(ctx *gin.Context) {
 
-		matricula := ctx.Param("matricula")
+		// o curinga se chama id porque divide a posição com as rotas por id do funcionario,
+		// mas no GET o segmento é a matrícula
+		matricula := ctx.Param("id")
 
 		tenantID, ok := middleware.GetTenantID(ctx)
 		if !ok {
@@ -192,6 +234,7 @@ func (f *FuncionarioController) ListarFuncionarioPorMatricula() gin.HandlerFunc
 // @Success      204  "Sem Conteúdo (Sucesso)"
 // @Failure      400  {object}  helper.HTTPError "ID inválido"
 // @Failure      404  {object}  helper.HTTPError "Não encontrado"
+// @Failure      422  {object}  helper.HTTPError "Funcionario ainda tem epis em posse"
 // @Failure      500  {object}  helper.HTTPError "Erro interno"
 // @Router       /funcionario/{id} [delete]
 // @Security     BearerAuth
@@ -229,6 +272,15 @@ func (f *FuncionarioController) DeletarFuncionaioId() gin.HandlerFunc {
 				return
 			}
 
+			if errors.Is(err, helper.ErrPosseDesligamento) {
+
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":    err.Error(),
+					"detalhes": "use POST /funcionario/{id}/desligamento para registrar as baixas",
+				})
+				return
+			}
+
 			ctx.JSON(http.StatusInternalServerError, gin.H{
 
 				"error": err.Error(),
@@ -240,6 +292,108 @@ func (f *FuncionarioController) DeletarFuncionaioId() gin.HandlerFunc {
 	}
 }
 
+// Desligar godoc
+// @Summary      Desligar funcionario
+// @Description  Registra as baixas informadas e desliga o funcionario. Se ainda sobrar epi em posse, nada é gravado e a resposta lista o que falta devolver ou baixar
+// @Tags         funcionarios
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                            true  "ID do funcionario"
+// @Param        body  body      model.DesligamentoFuncionario  true  "Data, motivo e baixas"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  helper.HTTPError "Dados inválidos"
+// @Failure      404   {object}  helper.HTTPError "Não encontrado"
+// @Failure      422   {object}  map[string]interface{} "Epis ainda em posse"
+// @Failure      500   {object}  helper.HTTPError "Erro interno"
+// @Router       /funcionario/{id}/desligamento [post]
+// @Security     BearerAuth
+func (f *FuncionarioController) Desligar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		var input model.DesligamentoFuncionario
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		idUser, existe := ctx.Get("userId")
+		if !existe {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token inválido ou sem id",
+			})
+			return
+		}
+
+		pendencias, err := f.Service.Desligar(ctx, id, input, int(idUser.(uint)), tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrPosseDesligamento) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":         err.Error(),
+					"epis_em_posse": pendencias,
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrId) || errors.Is(err, helper.ErrDataDesligamento) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "funcionario nao encontrado ou ja desligado",
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrDevolucaoSemPosse) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":    "baixa maior do que o funcionario tem em posse",
+					"detalhes": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrConflitoIntegridade) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error": "epi ou tamanho da baixa nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao desligar funcionario",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "funcionario desligado"})
+	}
+}
+
 // AtualizaFuncionario godoc
 // @Summary      Atualizar funcionario
 // @Description  Atualiza os dados de um funcionario existente
@@ -249,7 +403,7 @@ func (f *FuncionarioController) DeletarFuncionaioId() gin.HandlerFunc {
 // @Param        id   path      int                      true  "ID do funcionario"
 // @Param        body body      model.UpdateFuncionarioRequest true  "funcionario novos dados"
 // @Success      200  {object}  map[string]string "Sucesso"
-// @Failure      400  {object}  helper.HTTPError "Erro de validação (ID ou Nome curto)"
+// @Failure      400  {object}  helper.HTTPError "Erro de validação (ID, Nome curto ou data da transferência)"
 // @Failure      404  {object}  helper.HTTPError "Não encontrado"
 // @Failure      500  {object}  helper.HTTPError "Erro interno"
 // @Router       /funcionario/{id} [patch]
@@ -297,6 +451,20 @@ func (f *FuncionarioController) AtualizaFuncionario() gin.HandlerFunc {
 				return
 			}
 
+			if errors.Is(err, helper.ErrCpfDuplicado) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrDataCargo) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
 			if errors.Is(err, helper.ErrDadoDuplicado) {
 				ctx.JSON(http.StatusConflict, gin.H{
 
@@ -326,3 +494,234 @@ func (f *FuncionarioController) AtualizaFuncionario() gin.HandlerFunc {
 
 	}
 }
+
+// HistoricoCargos godoc
+// @Summary      Histórico de cargos do funcionario
+// @Description  Lista as funções/departamentos por onde o funcionario passou, do cargo atual para o mais antigo
+// @Tags         funcionarios
+// @Produce      json
+// @Param        id   path      int  true  "ID do funcionario"
+// @Success      200  {array}   model.CargoHistoricoDto
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      404  {object}  helper.HTTPError "Não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /funcionario/{id}/historico-cargos [get]
+// @Security     BearerAuth
+func (f *FuncionarioController) HistoricoCargos() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		historico, err := f.Service.HistoricoCargos(ctx, id, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "funcionario nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, historico)
+	}
+}
+
+// DadosPessoais godoc
+// @Summary      Dados pessoais do funcionario
+// @Description  Devolve o CPF completo, data de nascimento e contatos. As listagens mostram o CPF mascarado; cada consulta aqui fica registrada com o usuario que consultou (LGPD)
+// @Tags         funcionarios
+// @Produce      json
+// @Param        id   path      int  true  "ID do funcionario"
+// @Success      200  {object}  model.DadosPessoaisFuncionarioDto
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      401  {object}  helper.HTTPError "Token sem usuario"
+// @Failure      404  {object}  helper.HTTPError "Não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /funcionario/{id}/dados-pessoais [get]
+// @Security     BearerAuth
+func (f *FuncionarioController) DadosPessoais() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		idUser, existe := ctx.Get("userId")
+		if !existe {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token inválido ou sem id",
+			})
+			return
+		}
+
+		dados, err := f.Service.DadosPessoais(ctx, id, int(idUser.(uint)), tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "funcionario nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, dados)
+	}
+}
+
+// ImportarFuncionarios godoc
+// @Summary      Importar funcionarios de planilha
+// @Description  Recebe um .csv ou .xlsx com as colunas nome, matricula, departamento e funcao. Valida todas as linhas e só grava se nenhuma tiver erro, tudo numa transação. Com dry_run=true devolve apenas o relatório
+// @Tags         funcionarios
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        arquivo          formData  file  true   "Planilha .csv ou .xlsx"
+// @Param        dry_run          query     bool  false  "Só validar, sem gravar"
+// @Param        criar_cadastros  query     bool  false  "Criar departamentos e funções que não existirem"
+// @Success      200  {object}  model.ResultadoImportacaoFuncionarios "Dry-run"
+// @Success      201  {object}  model.ResultadoImportacaoFuncionarios
+// @Failure      400  {object}  helper.HTTPError "Arquivo inválido"
+// @Failure      422  {object}  model.ResultadoImportacaoFuncionarios "Linhas com erro, nada importado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /importacao-funcionarios [post]
+// @Security     BearerAuth
+func (f *FuncionarioController) ImportarFuncionarios() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var opcoes model.ImportacaoFuncionarioOpcoes
+
+		if err := ctx.ShouldBindQuery(&opcoes); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "parametros invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, tamanhoMaximoImportacao)
+
+		arquivo, err := ctx.FormFile("arquivo")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "envie a planilha no campo arquivo (maximo 5MB)",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		conteudo, err := arquivo.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "erro ao abrir a planilha",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+		defer conteudo.Close()
+
+		linhas, err := helper.LerPlanilha(arquivo.Filename, conteudo)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		resultado, err := f.Service.ImportarFuncionarios(ctx, linhas, opcoes, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrImportacaoInvalida) {
+				ctx.JSON(http.StatusUnprocessableEntity, resultado)
+				return
+			}
+
+			if errors.Is(err, helper.ErrCampoObrigatorio) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrDadoDuplicado) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"error":    "matricula cadastrada durante a importação, nada foi importado",
+					"detalhes": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao importar funcionarios",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		if opcoes.DryRun {
+			ctx.JSON(http.StatusOK, resultado)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, resultado)
+	}
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/service"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type ManutencaoService interface {
+	Concluir(ctx context.Context, id int, conclusao model.ConcluirOrdemManutencao, idUsuario int, tenantId int32) error
+	Listar(ctx context.Context, f service.FiltroManutencao, tenantId int32) (service.ManutencaoPaginada, error)
+	Relatorio(ctx context.Context, tenantId int32) ([]model.ItemEmManutencaoDto, error)
+}
+
+type ManutencaoController struct {
+	service ManutencaoService
+}
+
+func NewManutencaoController(service ManutencaoService) *ManutencaoController {
+
+	return &ManutencaoController{service: service}
+}
+
+func (m *ManutencaoController) Listar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var filtro service.FiltroManutencao
+
+		if err := ctx.ShouldBindQuery(&filtro); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "parametros de busca invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro ao receber tenantId",
+			})
+			return
+		}
+
+		if filtro.Pagina <= 0 {
+			filtro.Pagina = 1
+		}
+		if filtro.Quantidade <= 0 {
+			filtro.Quantidade = 10
+		}
+
+		ordens, err := m.service.Listar(ctx, filtro, tenantId)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao buscar as ordens de manutenção",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, ordens)
+	}
+}
+
+func (m *ManutencaoController) Concluir() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		var input model.ConcluirOrdemManutencao
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		idUser, existe := ctx.Get("userId")
+		if !existe {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token inválido ou sem id",
+			})
+			return
+		}
+
+		err = m.service.Concluir(ctx, id, input, int(idUser.(uint)), tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "ordem de manutenção não encontrada",
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrOrdemEncerrada) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrSemLoteDevolucao) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao concluir ordem de manutenção",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "ordem de manutenção concluída"})
+	}
+}
+
+func (m *ManutencaoController) Relatorio() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		itens, err := m.service.Relatorio(ctx, tenantId)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao gerar relatório de manutenção",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, itens)
+	}
+}
@@ -0,0 +1,259 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type MotivoDevolucaoService interface {
+	Salvar(ctx context.Context, model model.MotivoDevolucao, tenantId int32) error
+	ListarMotivo(ctx context.Context, id int, tenantid int32) (model.MotivoDevolucaoEpiDto, error)
+	ListarMotivos(ctx context.Context, tenantId int32) ([]model.MotivoDevolucaoEpiDto, error)
+	AtualizarMotivo(ctx context.Context, id int, model model.MotivoDevolucao, tenantId int32) error
+	DeletarMotivo(ctx context.Context, id int, tenantId int32) error
+}
+
+type MotivoDevolucaoController struct {
+	service MotivoDevolucaoService
+}
+
+func NewMotivoDevolucaoController(service MotivoDevolucaoService) *MotivoDevolucaoController {
+
+	return &MotivoDevolucaoController{service: service}
+}
+
+func (m *MotivoDevolucaoController) Adicionar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var input model.MotivoDevolucao
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		err := m.service.Salvar(ctx, input, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrDestinoMotivo) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrDadoDuplicado) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"error": "esse motivo de devolução ja está no sistema",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{
+			"mensagem": "motivo de devolução cadastrado",
+		})
+	}
+}
+
+func (m *MotivoDevolucaoController) ListarMotivos() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "erro interno de tenant"})
+			return
+		}
+
+		motivos, err := m.service.ListarMotivos(ctx, tenantId)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, motivos)
+	}
+}
+
+func (m *MotivoDevolucaoController) ListarMotivoPorId() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "erro interno de tenant"})
+			return
+		}
+
+		motivo, err := m.service.ListarMotivo(ctx, id, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "motivo de devolução nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, motivo)
+	}
+}
+
+func (m *MotivoDevolucaoController) AtualizarMotivo() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		var input model.MotivoDevolucao
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "erro interno de tenant"})
+			return
+		}
+
+		err = m.service.AtualizarMotivo(ctx, id, input, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) || errors.Is(err, helper.ErrDestinoMotivo) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "motivo de devolução nao encontrado",
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrDadoDuplicado) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"error": "esse motivo de devolução ja está no sistema",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"mensagem": "motivo de devolução atualizado",
+		})
+	}
+}
+
+func (m *MotivoDevolucaoController) DeletarMotivo() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "erro interno de tenant"})
+			return
+		}
+
+		err = m.service.DeletarMotivo(ctx, id, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "motivo de devolução nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.Status(http.StatusNoContent)
+	}
+}
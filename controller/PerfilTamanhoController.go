@@ -0,0 +1,300 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type PerfilTamanhoService interface {
+	Listar(ctx context.Context, idFuncionario int, tenantId int32) ([]model.PerfilTamanhoDto, error)
+	Definir(ctx context.Context, idFuncionario int, model model.PerfilTamanhoInserir, tenantId int32) error
+	Remover(ctx context.Context, idFuncionario, id int, tenantId int32) error
+	Sugeridos(ctx context.Context, idFuncionario int, idsEpis []int, tenantId int32) ([]model.TamanhoSugeridoDto, error)
+}
+
+type PerfilTamanhoController struct {
+	service PerfilTamanhoService
+}
+
+func NewPerfilTamanhoController(service PerfilTamanhoService) *PerfilTamanhoController {
+
+	return &PerfilTamanhoController{service: service}
+}
+
+// Listar godoc
+// @Summary      Perfil de tamanhos do funcionario
+// @Description  Tamanho que o funcionario usa por epi e por tipo de proteção, aprendido das entregas ou editado à mão
+// @Tags         funcionario
+// @Produce      json
+// @Param        id   path      int  true  "ID do funcionario"
+// @Success      200  {array}   model.PerfilTamanhoDto
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /funcionario/{id}/perfil-tamanhos [get]
+// @Security     BearerAuth
+func (p *PerfilTamanhoController) Listar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		perfil, err := p.service.Listar(ctx, id, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, perfil)
+	}
+}
+
+// Sugeridos godoc
+// @Summary      Tamanhos sugeridos para a entrega
+// @Description  Tamanho padrão do funcionario para cada epi informado; o manual ganha do aprendido e o do epi ganha do tipo de proteção. Epi sem tamanho no perfil não volta
+// @Tags         funcionario
+// @Produce      json
+// @Param        id   path      int    true  "ID do funcionario"
+// @Param        epi  query     []int  true  "IDs dos epis (repita o parametro)" collectionFormat(multi)
+// @Success      200  {array}   model.TamanhoSugeridoDto
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /funcionario/{id}/tamanhos-sugeridos [get]
+// @Security     BearerAuth
+func (p *PerfilTamanhoController) Sugeridos() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		var idsEpis []int
+		for _, e := range ctx.QueryArray("epi") {
+
+			idEpi, err := strconv.Atoi(e)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": "epi deve ser um numero",
+				})
+				return
+			}
+			idsEpis = append(idsEpis, idEpi)
+		}
+
+		if len(idsEpis) == 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "informe ao menos um epi",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		tamanhos, err := p.service.Sugeridos(ctx, id, idsEpis, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, tamanhos)
+	}
+}
+
+// Definir godoc
+// @Summary      Editar tamanho do funcionario
+// @Description  Fixa o tamanho para um epi ou para um tipo de proteção; editado à mão ele não é mais trocado pelas entregas
+// @Tags         funcionario
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                         true  "ID do funcionario"
+// @Param        body  body      model.PerfilTamanhoInserir  true  "Tamanho"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  helper.HTTPError "Dados inválidos"
+// @Failure      404   {object}  helper.HTTPError "Funcionario não encontrado"
+// @Failure      409   {object}  helper.HTTPError "epi, protecao ou tamanho nao existe no sistema"
+// @Failure      500   {object}  helper.HTTPError "Erro interno"
+// @Router       /funcionario/{id}/perfil-tamanhos [put]
+// @Security     BearerAuth
+func (p *PerfilTamanhoController) Definir() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		var input model.PerfilTamanhoInserir
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		err = p.service.Definir(ctx, id, input, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) || errors.Is(err, helper.ErrPerfilTamanhoAlvo) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "funcionario nao encontrado",
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrConflitoIntegridade) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao salvar perfil de tamanho",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "tamanho salvo no perfil do funcionario"})
+	}
+}
+
+// Remover godoc
+// @Summary      Remover tamanho do perfil
+// @Description  Apaga uma linha do perfil; o epi (ou tipo de proteção) volta a ser aprendido na próxima entrega
+// @Tags         funcionario
+// @Produce      json
+// @Param        id      path      int  true  "ID do funcionario"
+// @Param        perfil  path      int  true  "ID da linha do perfil"
+// @Success      200     {object}  map[string]string
+// @Failure      400     {object}  helper.HTTPError "ID inválido"
+// @Failure      404     {object}  helper.HTTPError "Não encontrado"
+// @Failure      500     {object}  helper.HTTPError "Erro interno"
+// @Router       /funcionario/{id}/perfil-tamanhos/{perfil} [delete]
+// @Security     BearerAuth
+func (p *PerfilTamanhoController) Remover() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idFuncionario, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		id, err := strconv.Atoi(ctx.Param("perfil"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id do perfil deve ser um numero",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		err = p.service.Remover(ctx, idFuncionario, id, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "tamanho nao encontrado no perfil do funcionario",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "tamanho removido do perfil"})
+	}
+}
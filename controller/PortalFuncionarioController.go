@@ -0,0 +1,362 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/service"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type PortalFuncionarioService interface {
+	DefinirPin(ctx context.Context, idFuncionario int, pin string, tenantId int32) error
+	Login(ctx context.Context, matricula, pin string, tenantId int32) (string, model.PerfilPortalDto, error)
+	Perfil(ctx context.Context, idFuncionario int32, tenantId int32) (model.PerfilPortalDto, error)
+	EpisEmPosse(ctx context.Context, idFuncionario int32, tenantId int32) ([]model.EpiEmPosseDto, error)
+	Entregas(ctx context.Context, idFuncionario int32, pagina, quantidade int32, tenantId int32) (service.EntregaPaginada, error)
+	AssinaturasPendentes(ctx context.Context, idFuncionario int32, tenantId int32) ([]model.AssinaturaPendenteDto, error)
+	ConfirmarCiencia(ctx context.Context, idEntrega int, idFuncionario int32, tenantId int32) error
+	ProximasTrocas(ctx context.Context, idFuncionario int32, tenantId int32) (model.SugestaoEntregaDto, error)
+	SolicitarTroca(ctx context.Context, idFuncionario int32, input model.SolicitacaoPortalInserir, tenantId int32) (int, error)
+}
+
+type PortalFuncionarioController struct {
+	service PortalFuncionarioService
+}
+
+func NewPortalFuncionarioController(service PortalFuncionarioService) *PortalFuncionarioController {
+
+	return &PortalFuncionarioController{service: service}
+}
+
+// DefinirPin fica nas rotas do sistema: é o usuario logado que libera o portal para o funcionario
+func (p *PortalFuncionarioController) DefinirPin() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		var input model.DefinirPin
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		err = p.service.DefinirPin(ctx, id, input.Pin, tenantId)
+		if err != nil {
+
+			switch {
+			case errors.Is(err, helper.ErrId):
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, helper.ErrNaoEncontrado):
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "funcionario não encontrado"})
+			default:
+				ctx.JSON(http.StatusInternalServerError, gin.H{"detalhes": err.Error()})
+			}
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "PIN do portal definido"})
+	}
+}
+
+func (p *PortalFuncionarioController) Login() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var input model.LoginPortalInput
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "Dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		token, perfil, err := p.service.Login(ctx, input.Matricula, input.Pin, tenantId)
+		if err != nil {
+
+			switch {
+			case errors.Is(err, helper.ErrCredenciaisPortal):
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			case errors.Is(err, helper.ErrPinBloqueado):
+				ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			default:
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Erro interno ao realizar login"})
+			}
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"token":       token,
+			"funcionario": perfil,
+		})
+	}
+}
+
+func (p *PortalFuncionarioController) Perfil() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idFuncionario, tenantId, ok := funcionarioDoToken(ctx)
+		if !ok {
+			return
+		}
+
+		perfil, err := p.service.Perfil(ctx, idFuncionario, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "funcionario não encontrado"})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{"detalhes": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, perfil)
+	}
+}
+
+func (p *PortalFuncionarioController) EpisEmPosse() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idFuncionario, tenantId, ok := funcionarioDoToken(ctx)
+		if !ok {
+			return
+		}
+
+		epis, err := p.service.EpisEmPosse(ctx, idFuncionario, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "funcionario não encontrado"})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao buscar os epis em posse",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, epis)
+	}
+}
+
+func (p *PortalFuncionarioController) Entregas() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idFuncionario, tenantId, ok := funcionarioDoToken(ctx)
+		if !ok {
+			return
+		}
+
+		pagina, _ := strconv.Atoi(ctx.DefaultQuery("pagina", "1"))
+		quantidade, _ := strconv.Atoi(ctx.DefaultQuery("quantidade", "10"))
+		if pagina <= 0 {
+			pagina = 1
+		}
+		if quantidade <= 0 {
+			quantidade = 10
+		}
+
+		entregas, err := p.service.Entregas(ctx, idFuncionario, int32(pagina), int32(quantidade), tenantId)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao buscar as entregas",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, entregas)
+	}
+}
+
+func (p *PortalFuncionarioController) AssinaturasPendentes() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idFuncionario, tenantId, ok := funcionarioDoToken(ctx)
+		if !ok {
+			return
+		}
+
+		pendentes, err := p.service.AssinaturasPendentes(ctx, idFuncionario, tenantId)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao buscar as assinaturas pendentes",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, pendentes)
+	}
+}
+
+func (p *PortalFuncionarioController) ConfirmarCiencia() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idFuncionario, tenantId, ok := funcionarioDoToken(ctx)
+		if !ok {
+			return
+		}
+
+		idEntrega, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		err = p.service.ConfirmarCiencia(ctx, idEntrega, idFuncionario, tenantId)
+		if err != nil {
+
+			switch {
+			case errors.Is(err, helper.ErrId):
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, helper.ErrNaoEncontrado):
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "entrega não encontrada ou já assinada"})
+			default:
+				ctx.JSON(http.StatusInternalServerError, gin.H{"detalhes": err.Error()})
+			}
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "recebimento confirmado"})
+	}
+}
+
+func (p *PortalFuncionarioController) ProximasTrocas() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idFuncionario, tenantId, ok := funcionarioDoToken(ctx)
+		if !ok {
+			return
+		}
+
+		trocas, err := p.service.ProximasTrocas(ctx, idFuncionario, tenantId)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao buscar as proximas trocas",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, trocas)
+	}
+}
+
+func (p *PortalFuncionarioController) SolicitarTroca() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idFuncionario, tenantId, ok := funcionarioDoToken(ctx)
+		if !ok {
+			return
+		}
+
+		var input model.SolicitacaoPortalInserir
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		id, err := p.service.SolicitarTroca(ctx, idFuncionario, input, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrFuncionarioInativo) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrConflitoIntegridade) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":    "epi ou tamanho não encontrado",
+					"detalhes": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{"detalhes": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{
+			"mensagem": "solicitação enviada para aprovação",
+			"id":       id,
+		})
+	}
+}
+
+// funcionarioDoToken lê o funcionario logado no portal e o tenant; em caso de erro já responde
+func funcionarioDoToken(ctx *gin.Context) (int32, int32, bool) {
+
+	idFuncionario, ok := middleware.GetFuncionarioID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Token inválido ou sem id",
+		})
+		return 0, 0, false
+	}
+
+	tenantId, ok := middleware.GetTenantID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "erro interno de tenant",
+		})
+		return 0, 0, false
+	}
+
+	return idFuncionario, tenantId, true
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type PosseService interface {
+	Listar(ctx context.Context, idFuncionario int, tenantId int32) ([]model.PosseEpiDto, error)
+}
+
+type PosseController struct {
+	service PosseService
+}
+
+func NewPosseController(service PosseService) *PosseController {
+
+	return &PosseController{service: service}
+}
+
+// EpisEmPosse godoc
+// @Summary      Epis em posse do funcionario
+// @Description  Retorna o que o funcionario tem em mãos agora (entregue menos devolvido), com as entregas e lotes de origem
+// @Tags         funcionarios
+// @Produce      json
+// @Param        id   path      int  true  "ID do funcionario"
+// @Success      200  {array}   model.PosseEpiDto
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      404  {object}  helper.HTTPError "Não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /funcionario/{id}/epis-em-posse [get]
+// @Security     BearerAuth
+func (p *PosseController) EpisEmPosse() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		posse, err := p.service.Listar(ctx, id, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "funcionario nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, posse)
+	}
+}
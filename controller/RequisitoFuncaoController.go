@@ -0,0 +1,382 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type RequisitoFuncaoService interface {
+	SalvarRequisito(ctx context.Context, idFuncao int, model model.RequisitoFuncaoInserir, tenantId int32) (int, error)
+	ListarRequisitos(ctx context.Context, idFuncao int, tenantId int32) ([]model.RequisitoFuncaoDto, error)
+	AtualizarRequisito(ctx context.Context, id int, model model.RequisitoFuncaoAtualizar, tenantId int32) error
+	DeletarRequisito(ctx context.Context, id int, tenantId int32) error
+	SugestaoEntrega(ctx context.Context, idFuncionario int, tenantId int32) (model.SugestaoEntregaDto, error)
+	RelatorioConformidade(ctx context.Context, idDepartamento int, tenantId int32) (model.RelatorioConformidadeDto, error)
+}
+
+type RequisitoFuncaoController struct {
+	service RequisitoFuncaoService
+}
+
+func NewRequisitoFuncaoController(service RequisitoFuncaoService) *RequisitoFuncaoController {
+
+	return &RequisitoFuncaoController{service: service}
+}
+
+// AdicionarRequisito godoc
+// @Summary      Adicionar epi obrigatorio a uma funcao
+// @Description  Cadastra um requisito (epi especifico ou tipo de protecao) na matriz da funcao
+// @Tags         funcao
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID da funcao"
+// @Param        requisito body model.RequisitoFuncaoInserir true "Dados do requisito"
+// @Success      201  {object}  map[string]int
+// @Failure      400  {object}  helper.HTTPError "Dados inválidos"
+// @Failure      409  {object}  helper.HTTPError "funcao, epi ou protecao nao existe no sistema"
+// @Failure      422  {object}  helper.HTTPError "requisito já cadastrado para a funcao"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /funcao/{id}/requisitos [post]
+// @Security     BearerAuth
+func (r *RequisitoFuncaoController) AdicionarRequisito() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idFuncao, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		var input model.RequisitoFuncaoInserir
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		id, err := r.service.SalvarRequisito(ctx, idFuncao, input, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) || errors.Is(err, helper.ErrRequisitoAlvo) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrDadoDuplicado) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error": "requisito já cadastrado para esta funcao",
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrConflitoIntegridade) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{"id": id})
+	}
+}
+
+// ListarRequisitos godoc
+// @Summary      Listar epis obrigatorios da funcao
+// @Description  Retorna a matriz de epis obrigatorios de uma funcao
+// @Tags         funcao
+// @Produce      json
+// @Param        id   path      int  true  "ID da funcao"
+// @Success      200  {array}   model.RequisitoFuncaoDto
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /funcao/{id}/requisitos [get]
+// @Security     BearerAuth
+func (r *RequisitoFuncaoController) ListarRequisitos() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idFuncao, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		requisitos, err := r.service.ListarRequisitos(ctx, idFuncao, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro interno ao listar requisitos",
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, requisitos)
+	}
+}
+
+// AtualizarRequisito godoc
+// @Summary      Atualizar requisito
+// @Description  Atualiza a quantidade e o intervalo de troca de um requisito
+// @Tags         funcao
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "ID do requisito"
+// @Param        body body      model.RequisitoFuncaoAtualizar true "Novos valores"
+// @Success      200  {object}  map[string]string "Sucesso"
+// @Failure      400  {object}  helper.HTTPError "Dados inválidos"
+// @Failure      404  {object}  helper.HTTPError "Não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /requisito-funcao/{id} [put]
+// @Security     BearerAuth
+func (r *RequisitoFuncaoController) AtualizarRequisito() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		var input model.RequisitoFuncaoAtualizar
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		err = r.service.AtualizarRequisito(ctx, id, input, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "requisito nao encontrado para atualizar",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"sucesso": "requisito atualizado"})
+	}
+}
+
+// DeletarRequisito godoc
+// @Summary      Deletar requisito
+// @Description  Remove (inativa) um requisito da matriz da funcao
+// @Tags         funcao
+// @Param        id   path      int  true  "ID do requisito"
+// @Success      204  "Sem Conteúdo (Sucesso)"
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      404  {object}  helper.HTTPError "Não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /requisito-funcao/{id} [delete]
+// @Security     BearerAuth
+func (r *RequisitoFuncaoController) DeletarRequisito() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		err = r.service.DeletarRequisito(ctx, id, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "requisito nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// SugestaoEntrega godoc
+// @Summary      Sugestao de entrega
+// @Description  Compara a matriz da funcao do funcionario com os epis em posse e retorna o que falta entregar
+// @Tags         funcionario
+// @Produce      json
+// @Param        id   path      int  true  "ID do funcionario"
+// @Success      200  {object}  model.SugestaoEntregaDto
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /sugestao-entrega/{id} [get]
+// @Security     BearerAuth
+func (r *RequisitoFuncaoController) SugestaoEntrega() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		sugestao, err := r.service.SugestaoEntrega(ctx, id, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, sugestao)
+	}
+}
+
+// RelatorioConformidade godoc
+// @Summary      Relatorio de conformidade
+// @Description  Cruza cada funcionario ativo com os epis obrigatorios da sua funcao e aponta itens faltantes ou vencidos, agrupados por departamento e com o resumo por categoria da NR-6
+// @Tags         relatorios
+// @Produce      json
+// @Param        departamento query int false "ID do departamento"
+// @Success      200  {object}  model.RelatorioConformidadeDto
+// @Failure      400  {object}  helper.HTTPError "departamento inválido"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /relatorio-conformidade [get]
+// @Security     BearerAuth
+func (r *RequisitoFuncaoController) RelatorioConformidade() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idDepartamento := 0
+		if dep := ctx.Query("departamento"); dep != "" {
+
+			id, err := strconv.Atoi(dep)
+			if err != nil {
+
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": "departamento deve ser um numero",
+				})
+				return
+			}
+			idDepartamento = id
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		relatorio, err := r.service.RelatorioConformidade(ctx, idDepartamento, tenantID)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, relatorio)
+	}
+}
@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/service"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type ReservaService interface {
+	Salvar(ctx context.Context, model model.ReservaInserir, idUsuario int, tenantId int32) (int, error)
+	Listar(ctx context.Context, f service.FiltroReserva, tenantId int32) (service.ReservaPaginada, error)
+	Cancelar(ctx context.Context, id, idUsuario int, tenantId int32) error
+	Saldo(ctx context.Context, idEpi, idTamanho int, tenantId int32) (model.SaldoEstoqueDto, error)
+}
+
+type ReservaController struct {
+	service ReservaService
+}
+
+func NewReservaController(service ReservaService) *ReservaController {
+
+	return &ReservaController{service: service}
+}
+
+func (r *ReservaController) Adicionar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var input model.ReservaInserir
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		idUser, existe := ctx.Get("userId")
+		if !existe {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token inválido ou sem id",
+			})
+			return
+		}
+
+		id, err := r.service.Salvar(ctx, input, int(idUser.(uint)), tenantId)
+		if err != nil {
+
+			switch {
+			case errors.Is(err, helper.ErrReservaExpirada):
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, helper.ErrEstoqueInsuficiente):
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			case errors.Is(err, helper.ErrConflitoIntegridade):
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":    "epi, tamanho ou lote não encontrado",
+					"detalhes": err.Error(),
+				})
+			default:
+				ctx.JSON(http.StatusInternalServerError, gin.H{"detalhes": err.Error()})
+			}
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{
+			"mensagem": "reserva cadastrada",
+			"id":       id,
+		})
+	}
+}
+
+func (r *ReservaController) Listar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var filtro service.FiltroReserva
+
+		if err := ctx.ShouldBindQuery(&filtro); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "parametros de busca invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro ao receber tenantId",
+			})
+			return
+		}
+
+		if filtro.Pagina <= 0 {
+			filtro.Pagina = 1
+		}
+		if filtro.Quantidade <= 0 {
+			filtro.Quantidade = 10
+		}
+
+		reservas, err := r.service.Listar(ctx, filtro, tenantId)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao buscar as reservas",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, reservas)
+	}
+}
+
+func (r *ReservaController) Cancelar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, tenantId, idUser, ok := parametrosSolicitacao(ctx)
+		if !ok {
+			return
+		}
+
+		err := r.service.Cancelar(ctx, id, idUser, tenantId)
+		if err != nil {
+
+			switch {
+			case errors.Is(err, helper.ErrId):
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, helper.ErrNaoEncontrado):
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "reserva não encontrada ou já encerrada"})
+			default:
+				ctx.JSON(http.StatusInternalServerError, gin.H{"detalhes": err.Error()})
+			}
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "reserva cancelada"})
+	}
+}
+
+func (r *ReservaController) Saldo() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		idEpi, errEpi := strconv.Atoi(ctx.Query("epi"))
+		idTamanho, errTamanho := strconv.Atoi(ctx.Query("tamanho"))
+		if errEpi != nil || errTamanho != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "epi e tamanho devem ser numeros",
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		saldo, err := r.service.Saldo(ctx, idEpi, idTamanho, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{"detalhes": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, saldo)
+	}
+}
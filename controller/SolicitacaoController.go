@@ -0,0 +1,263 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/service"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type SolicitacaoService interface {
+	Salvar(ctx context.Context, model model.SolicitacaoInserir, idUsuario int, tenantId int32) (int, error)
+	Listar(ctx context.Context, f service.FiltroSolicitacao, tenantId int32) (service.SolicitacaoPaginada, error)
+	Aprovar(ctx context.Context, id, idUsuario int, tenantId int32) error
+	Rejeitar(ctx context.Context, id, idUsuario int, motivo string, tenantId int32) error
+	Atender(ctx context.Context, id, idUsuario int, dados model.AtenderSolicitacao, tenantId int32) (int, error)
+}
+
+type SolicitacaoController struct {
+	service SolicitacaoService
+}
+
+func NewSolicitacaoController(service SolicitacaoService) *SolicitacaoController {
+
+	return &SolicitacaoController{service: service}
+}
+
+func (s *SolicitacaoController) Adicionar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var input model.SolicitacaoInserir
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		idUser, existe := ctx.Get("userId")
+		if !existe {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token inválido ou sem id",
+			})
+			return
+		}
+
+		id, err := s.service.Salvar(ctx, input, int(idUser.(uint)), tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrFuncionarioInativo) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrConflitoIntegridade) {
+				ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":    "funcionario, epi ou tamanho não encontrado",
+					"detalhes": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{
+			"mensagem": "solicitação cadastrada",
+			"id":       id,
+		})
+	}
+}
+
+func (s *SolicitacaoController) Listar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var filtro service.FiltroSolicitacao
+
+		if err := ctx.ShouldBindQuery(&filtro); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "parametros de busca invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro ao receber tenantId",
+			})
+			return
+		}
+
+		if filtro.Pagina <= 0 {
+			filtro.Pagina = 1
+		}
+		if filtro.Quantidade <= 0 {
+			filtro.Quantidade = 10
+		}
+
+		solicitacoes, err := s.service.Listar(ctx, filtro, tenantId)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao buscar as solicitações",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, solicitacoes)
+	}
+}
+
+func (s *SolicitacaoController) Aprovar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, tenantId, idUser, ok := parametrosSolicitacao(ctx)
+		if !ok {
+			return
+		}
+
+		err := s.service.Aprovar(ctx, id, idUser, tenantId)
+		if err != nil {
+			responderErroSolicitacao(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "solicitação aprovada, estoque reservado"})
+	}
+}
+
+func (s *SolicitacaoController) Rejeitar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, tenantId, idUser, ok := parametrosSolicitacao(ctx)
+		if !ok {
+			return
+		}
+
+		var input model.RejeitarSolicitacao
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		err := s.service.Rejeitar(ctx, id, idUser, input.Motivo, tenantId)
+		if err != nil {
+			responderErroSolicitacao(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "solicitação rejeitada"})
+	}
+}
+
+func (s *SolicitacaoController) Atender() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, tenantId, idUser, ok := parametrosSolicitacao(ctx)
+		if !ok {
+			return
+		}
+
+		var input model.AtenderSolicitacao
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		idEntrega, err := s.service.Atender(ctx, id, idUser, input, tenantId)
+		if err != nil {
+			responderErroSolicitacao(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"mensagem":   "solicitação atendida",
+			"id_entrega": idEntrega,
+		})
+	}
+}
+
+// parametrosSolicitacao lê o id da rota, o tenant e o usuario logado; em caso de erro já responde
+func parametrosSolicitacao(ctx *gin.Context) (int, int32, int, bool) {
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "id deve ser um numero",
+		})
+		return 0, 0, 0, false
+	}
+
+	tenantId, ok := middleware.GetTenantID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": "erro interno de tenant",
+		})
+		return 0, 0, 0, false
+	}
+
+	idUser, existe := ctx.Get("userId")
+	if !existe {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Token inválido ou sem id",
+		})
+		return 0, 0, 0, false
+	}
+
+	return id, tenantId, int(idUser.(uint)), true
+}
+
+func responderErroSolicitacao(ctx *gin.Context, err error) {
+
+	switch {
+	case errors.Is(err, helper.ErrId):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, helper.ErrNaoEncontrado):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "solicitação não encontrada"})
+	case errors.Is(err, helper.ErrStatusSolicitacao):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, helper.ErrEstoqueInsuficiente), errors.Is(err, helper.ErrSemTreinamento):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"detalhes": err.Error()})
+	}
+}
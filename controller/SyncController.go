@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type SyncService interface {
+	Catalogos(ctx context.Context, f model.FiltroCatalogoSync, tenantId int32) (model.CatalogosSyncDto, error)
+	Enviar(ctx context.Context, operacoes []model.OperacaoSync, idUsuario int, tenantId int32) model.ResultadoSync
+}
+
+type SyncController struct {
+	service SyncService
+}
+
+func NewSyncController(service SyncService) *SyncController {
+
+	return &SyncController{service: service}
+}
+
+func (s *SyncController) Catalogos() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var filtro model.FiltroCatalogoSync
+
+		if err := ctx.ShouldBindQuery(&filtro); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "parametros de busca invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro ao receber tenantId",
+			})
+			return
+		}
+
+		catalogos, err := s.service.Catalogos(ctx, filtro, tenantId)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao montar os catalogos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, catalogos)
+	}
+}
+
+// Enviar sempre responde 200 quando o lote é valido: o status de cada operação vem no corpo
+func (s *SyncController) Enviar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var input model.EnviarSyncInput
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "erro interno de tenant",
+			})
+			return
+		}
+
+		idUser, existe := ctx.Get("userId")
+		if !existe {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token inválido ou sem id",
+			})
+			return
+		}
+
+		resultado := s.service.Enviar(ctx, input.Operacoes, int(idUser.(uint)), tenantId)
+
+		ctx.JSON(http.StatusOK, resultado)
+	}
+}
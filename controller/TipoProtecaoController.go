@@ -17,6 +17,8 @@ type TipoProtecaoService interface {
 	ListarProtecao(ctx context.Context, id int, tenatId int32) (model.TipoProtecaoDto, error)
 	ListarProtecoes(ctx context.Context, tenantId int32) ([]model.TipoProtecaoDto, error)
 	DeletarProtecao(ctx context.Context, id int, tenantId int32) error
+	DefinirCategoria(ctx context.Context, id int, categoria model.DefinirCategoriaProtecao, tenantId int32) error
+	Classificacao(ctx context.Context, tenantId int32) (model.ClassificacaoProtecaoDto, error)
 }
 
 type TipoProtecaoController struct {
@@ -47,7 +49,8 @@ func (t *TipoProtecaoController) AdicionarProtecao() gin.HandlerFunc {
 		}
 
 		protec := model.TipoProtecao{
-			Nome: input.Nome,
+			Nome:        input.Nome,
+			IdCategoria: input.IdCategoria,
 		}
 
 		tenantId, ok := middleware.GetTenantID(ctx)
@@ -71,6 +74,15 @@ func (t *TipoProtecaoController) AdicionarProtecao() gin.HandlerFunc {
 				return
 			}
 
+			if errors.Is(err, helper.ErrConflitoIntegridade) {
+
+				ctx.JSON(http.StatusConflict, gin.H{
+
+					"error": "categoria nao existe no sistema",
+				})
+				return
+			}
+
 			ctx.JSON(http.StatusInternalServerError, gin.H{
 
 				"error": err.Error(),
@@ -191,3 +203,98 @@ func (t *TipoProtecaoController) DeletarProtecao() gin.HandlerFunc {
 		ctx.Status(http.StatusNoContent)
 	}
 }
+
+func (t *TipoProtecaoController) Classificacao() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+
+			ctx.JSON(500, gin.H{"error": "erro interno de tenant"})
+			return
+		}
+
+		classificacao, err := t.service.Classificacao(ctx, tenantId)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, classificacao)
+	}
+}
+
+func (t *TipoProtecaoController) DefinirCategoria() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		var input model.DefinirCategoriaProtecao
+
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantId, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		err = t.service.DefinirCategoria(ctx, id, input, tenantId)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+
+				ctx.JSON(http.StatusBadRequest, gin.H{
+
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+
+				ctx.JSON(http.StatusNotFound, gin.H{
+
+					"error": "protecao nao encontrada",
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrConflitoIntegridade) {
+
+				ctx.JSON(http.StatusConflict, gin.H{
+
+					"error": "categoria nao existe no sistema",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "categoria da protecao atualizada"})
+	}
+}
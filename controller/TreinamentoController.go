@@ -0,0 +1,324 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type TreinamentoService interface {
+	Salvar(ctx context.Context, model model.TreinamentoInserir, tenantId int32) (int, error)
+	Listar(ctx context.Context, idFuncionario, idEpi *int, tenantId int32) ([]model.TreinamentoDto, error)
+	Buscar(ctx context.Context, id int, tenantId int32) (model.TreinamentoDto, error)
+	Deletar(ctx context.Context, id int, tenantId int32) error
+	DefinirRegra(ctx context.Context, regra model.RegraTreinamentoEntrega, tenantId int32) error
+}
+
+type TreinamentoController struct {
+	service TreinamentoService
+}
+
+func NewTreinamentoController(service TreinamentoService) *TreinamentoController {
+
+	return &TreinamentoController{service: service}
+}
+
+// Adicionar godoc
+// @Summary      Registrar treinamento
+// @Description  Cadastra um treinamento (de um epi ou de um tipo de proteção) com a lista de presença
+// @Tags         treinamento
+// @Accept       json
+// @Produce      json
+// @Param        treinamento body model.TreinamentoInserir true "Dados do treinamento"
+// @Success      201  {object}  map[string]int
+// @Failure      400  {object}  helper.HTTPError "Dados inválidos"
+// @Failure      404  {object}  helper.HTTPError "Participante não encontrado"
+// @Failure      409  {object}  helper.HTTPError "epi ou protecao nao existe no sistema"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /cadastro-treinamento [post]
+// @Security     BearerAuth
+func (t *TreinamentoController) Adicionar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var input model.TreinamentoInserir
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		id, err := t.service.Salvar(ctx, input, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrTreinamentoAlvo) || errors.Is(err, helper.ErrValidadeTreinamento) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrConflitoIntegridade) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao salvar treinamento",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, gin.H{"id": id})
+	}
+}
+
+// Listar godoc
+// @Summary      Listar treinamentos
+// @Description  Lista os treinamentos do tenant, com filtro opcional por funcionario participante e por epi
+// @Tags         treinamento
+// @Produce      json
+// @Param        funcionario query int false "ID do funcionario"
+// @Param        epi         query int false "ID do epi (inclui os treinamentos do tipo de proteção dele)"
+// @Success      200  {array}   model.TreinamentoDto
+// @Failure      400  {object}  helper.HTTPError "Filtro inválido"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /treinamentos [get]
+// @Security     BearerAuth
+func (t *TreinamentoController) Listar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var idFuncionario, idEpi *int
+
+		if f := ctx.Query("funcionario"); f != "" {
+
+			id, err := strconv.Atoi(f)
+			if err != nil {
+
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": "funcionario deve ser um numero",
+				})
+				return
+			}
+			idFuncionario = &id
+		}
+
+		if e := ctx.Query("epi"); e != "" {
+
+			id, err := strconv.Atoi(e)
+			if err != nil {
+
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": "epi deve ser um numero",
+				})
+				return
+			}
+			idEpi = &id
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		treinamentos, err := t.service.Listar(ctx, idFuncionario, idEpi, tenantID)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro interno ao listar treinamentos",
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, treinamentos)
+	}
+}
+
+// Detalhe godoc
+// @Summary      Detalhe do treinamento
+// @Description  Retorna o treinamento com a lista de presença
+// @Tags         treinamento
+// @Produce      json
+// @Param        id   path      int  true  "ID do treinamento"
+// @Success      200  {object}  model.TreinamentoDto
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      404  {object}  helper.HTTPError "Não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /treinamento/{id} [get]
+// @Security     BearerAuth
+func (t *TreinamentoController) Detalhe() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		treinamento, err := t.service.Buscar(ctx, id, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "treinamento nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro interno ao buscar treinamento",
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, treinamento)
+	}
+}
+
+// Deletar godoc
+// @Summary      Deletar treinamento
+// @Description  Remove o treinamento e a lista de presença dele
+// @Tags         treinamento
+// @Produce      json
+// @Param        id   path      int  true  "ID do treinamento"
+// @Success      200  {object}  map[string]string "Sucesso"
+// @Failure      400  {object}  helper.HTTPError "ID inválido"
+// @Failure      404  {object}  helper.HTTPError "Não encontrado"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /treinamento/{id} [delete]
+// @Security     BearerAuth
+func (t *TreinamentoController) Deletar() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "id deve ser um numero",
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		err = t.service.Deletar(ctx, id, tenantID)
+		if err != nil {
+
+			if errors.Is(err, helper.ErrId) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			if errors.Is(err, helper.ErrNaoEncontrado) {
+				ctx.JSON(http.StatusNotFound, gin.H{
+					"error": "treinamento nao encontrado",
+				})
+				return
+			}
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Erro interno ao deletar treinamento",
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "treinamento deletado com sucesso"})
+	}
+}
+
+// DefinirRegra godoc
+// @Summary      Regra de treinamento na entrega
+// @Description  Define o que a entrega de epi faz quando o funcionario não tem treinamento válido: desligada (nada), avisar (grava a entrega marcada com pendência) ou bloquear (recusa a entrega)
+// @Tags         treinamento
+// @Accept       json
+// @Produce      json
+// @Param        body body      model.RegraTreinamentoEntrega true "Regra"
+// @Success      200  {object}  map[string]string "Sucesso"
+// @Failure      400  {object}  helper.HTTPError "Regra inválida"
+// @Failure      500  {object}  helper.HTTPError "Erro interno"
+// @Router       /regra-treinamento-entrega [put]
+// @Security     BearerAuth
+func (t *TreinamentoController) DefinirRegra() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		var input model.RegraTreinamentoEntrega
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":    "dados invalidos",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		tenantID, ok := middleware.GetTenantID(ctx)
+		if !ok {
+			ctx.JSON(500, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		err := t.service.DefinirRegra(ctx, input, tenantID)
+		if err != nil {
+
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":    "erro ao salvar a regra de treinamento",
+				"detalhes": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"mensagem": "regra de treinamento salva"})
+	}
+}
@@ -0,0 +1,29 @@
+-- Matriz de EPIs obrigatórios por função
+-- Cada linha exige OU um EPI específico OU qualquer EPI de um tipo de proteção
+CREATE TABLE requisito_funcao (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdFuncao INT NOT NULL,
+    IdEpi INT NULL,
+    IdTipoProtecao INT NULL,
+    quantidade INT NOT NULL DEFAULT 1,
+    periodicidade_dias INT NULL, -- Intervalo de troca. NULL = sem troca periódica
+    ativo BOOLEAN NOT NULL DEFAULT TRUE,
+    deletado_em TIMESTAMP NULL,
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdFuncao) REFERENCES funcao(id),
+    FOREIGN KEY (IdEpi) REFERENCES epi(id),
+    FOREIGN KEY (IdTipoProtecao) REFERENCES tipo_protecao(id),
+    CONSTRAINT chk_requisito_alvo CHECK ((IdEpi IS NULL) <> (IdTipoProtecao IS NULL)),
+    CONSTRAINT chk_requisito_quantidade CHECK (quantidade > 0),
+    CONSTRAINT chk_requisito_periodicidade CHECK (periodicidade_dias IS NULL OR periodicidade_dias > 0)
+);
+
+-- Não deixa repetir o mesmo EPI (ou tipo de proteção) na mesma função
+CREATE UNIQUE INDEX idx_requisito_funcao_epi
+ON requisito_funcao (tenant_id, IdFuncao, IdEpi)
+WHERE deletado_em IS NULL AND IdEpi IS NOT NULL;
+
+CREATE UNIQUE INDEX idx_requisito_funcao_protecao
+ON requisito_funcao (tenant_id, IdFuncao, IdTipoProtecao)
+WHERE deletado_em IS NULL AND IdTipoProtecao IS NOT NULL;
@@ -0,0 +1,14 @@
+-- Guarda o resultado das requisições de criação que vieram com o header Idempotency-Key
+-- Um retry com a mesma chave devolve a resposta original em vez de executar de novo
+CREATE TABLE requisicao_idempotente (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    chave VARCHAR(100) NOT NULL,
+    hash_requisicao VARCHAR(64) NOT NULL, -- sha256 de metodo + rota + corpo
+    status_resposta INT NULL, -- NULL = ainda em processamento
+    corpo_resposta TEXT NULL,
+    criado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    concluida_em TIMESTAMP NULL,
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    UNIQUE (tenant_id, chave)
+);
@@ -0,0 +1,37 @@
+-- Pedido de EPI feito pelo supervisor, aprovado pelo técnico de segurança e atendido pelo almoxarifado
+CREATE TABLE solicitacao_epi (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdFuncionario INT NOT NULL,
+    IdUsuarioSolicitante INT NOT NULL,
+    justificativa VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pendente',
+    IdUsuarioAnalise INT NULL,
+    motivo_rejeicao VARCHAR(255) NULL,
+    analisada_em TIMESTAMP NULL,
+    IdEntrega INT NULL, -- entrega gerada quando a solicitação é atendida
+    atendida_em TIMESTAMP NULL,
+    criada_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id),
+    FOREIGN KEY (IdUsuarioSolicitante) REFERENCES usuarios(id),
+    FOREIGN KEY (IdUsuarioAnalise) REFERENCES usuarios(id),
+    FOREIGN KEY (IdEntrega) REFERENCES entrega_epi(id),
+    CONSTRAINT chk_solicitacao_status CHECK (status IN ('pendente', 'aprovada', 'rejeitada', 'atendida'))
+);
+
+CREATE TABLE item_solicitacao (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdSolicitacao INT NOT NULL,
+    IdEpi INT NOT NULL,
+    IdTamanho INT NOT NULL,
+    quantidade INT NOT NULL,
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdSolicitacao) REFERENCES solicitacao_epi(id),
+    FOREIGN KEY (IdEpi) REFERENCES epi(id),
+    FOREIGN KEY (IdTamanho) REFERENCES tamanho(id),
+    CONSTRAINT chk_item_solicitacao_quantidade CHECK (quantidade > 0)
+);
+
+CREATE INDEX idx_solicitacao_epi_status ON solicitacao_epi (tenant_id, status);
@@ -0,0 +1,32 @@
+-- Estoque prometido: solicitações aprovadas e entregas agendadas seguram o saldo até expirar
+-- Saldo disponivel = quantidadeAtual - reservas ativas (não consumidas, não canceladas e não expiradas)
+CREATE TABLE reserva_estoque (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdEpi INT NOT NULL,
+    IdTamanho INT NOT NULL,
+    IdEntrada INT NULL, -- opcional: reserva presa a um lote especifico
+    quantidade INT NOT NULL,
+    expira_em TIMESTAMP NOT NULL,
+    IdSolicitacao INT NULL,
+    observacao VARCHAR(255) NULL,
+    IdUsuario INT NULL,
+    criada_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    IdEntrega INT NULL, -- entrega que consumiu a reserva
+    consumida_em TIMESTAMP NULL,
+    cancelada_em TIMESTAMP NULL,
+    id_usuario_cancelamento INT NULL,
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdEpi) REFERENCES epi(id),
+    FOREIGN KEY (IdTamanho) REFERENCES tamanho(id),
+    FOREIGN KEY (IdEntrada) REFERENCES entrada_epi(id),
+    FOREIGN KEY (IdSolicitacao) REFERENCES solicitacao_epi(id),
+    FOREIGN KEY (IdUsuario) REFERENCES usuarios(id),
+    FOREIGN KEY (IdEntrega) REFERENCES entrega_epi(id),
+    FOREIGN KEY (id_usuario_cancelamento) REFERENCES usuarios(id),
+    CONSTRAINT chk_reserva_quantidade CHECK (quantidade > 0)
+);
+
+CREATE INDEX idx_reserva_estoque_ativa 
+ON reserva_estoque (tenant_id, IdEpi, IdTamanho)
+WHERE consumida_em IS NULL AND cancelada_em IS NULL;
@@ -0,0 +1,12 @@
+-- Portal do funcionário: o próprio trabalhador entra com matrícula + PIN (fora da tabela usuarios)
+ALTER TABLE funcionario ADD COLUMN pin_hash VARCHAR(255) NULL; -- NULL = sem acesso ao portal
+ALTER TABLE funcionario ADD COLUMN tentativas_pin INT NOT NULL DEFAULT 0;
+ALTER TABLE funcionario ADD COLUMN pin_bloqueado_ate TIMESTAMP NULL; -- bloqueio após erros seguidos de PIN
+
+-- Ciência do funcionário no portal. NULL = assinatura pendente
+ALTER TABLE entrega_epi ADD COLUMN ciente_em TIMESTAMP NULL;
+
+-- Solicitação aberta pelo próprio funcionário não tem usuário solicitante
+ALTER TABLE solicitacao_epi ALTER COLUMN IdUsuarioSolicitante DROP NOT NULL;
+ALTER TABLE solicitacao_epi ADD COLUMN origem VARCHAR(20) NOT NULL DEFAULT 'sistema';
+ALTER TABLE solicitacao_epi ADD CONSTRAINT chk_solicitacao_origem CHECK (origem IN ('sistema', 'portal'));
@@ -0,0 +1,18 @@
+-- Operações feitas offline pelos tablets do almoxarifado, identificadas pelo UUID gerado no aparelho.
+-- O UUID aplicado uma vez nunca é aplicado de novo; conflito (ex: sem estoque) pode ser reenviado
+CREATE TABLE sync_operacao (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    uuid UUID NOT NULL,
+    tipo VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    id_registro INT NULL, -- id da entrega ou devolução criada
+    erro TEXT NULL,
+    IdUsuario INT NULL,
+    recebida_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdUsuario) REFERENCES usuarios(id),
+    CONSTRAINT chk_sync_operacao_tipo CHECK (tipo IN ('entrega', 'devolucao')),
+    CONSTRAINT chk_sync_operacao_status CHECK (status IN ('processando', 'aplicada', 'conflito')),
+    UNIQUE (tenant_id, uuid)
+);
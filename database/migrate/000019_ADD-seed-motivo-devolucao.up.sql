@@ -0,0 +1,27 @@
+-- Motivos de devolução padrão: toda empresa nova já nasce com eles e pode cadastrar os seus depois
+CREATE OR REPLACE FUNCTION seed_motivos_devolucao(p_tenant_id INT) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO motivo_devolucao (tenant_id, motivo)
+    VALUES (p_tenant_id, 'Desgaste'),
+           (p_tenant_id, 'Dano'),
+           (p_tenant_id, 'Vencimento'),
+           (p_tenant_id, 'Troca de tamanho'),
+           (p_tenant_id, 'Desligamento');
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION trg_empresa_motivos_devolucao() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM seed_motivos_devolucao(NEW.id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER empresa_motivos_devolucao
+AFTER INSERT ON empresas
+FOR EACH ROW EXECUTE FUNCTION trg_empresa_motivos_devolucao();
+
+-- Empresas que já existem e ainda não têm nenhum motivo recebem o mesmo conjunto
+SELECT seed_motivos_devolucao(e.id)
+FROM empresas e
+WHERE NOT EXISTS (SELECT 1 FROM motivo_devolucao m WHERE m.tenant_id = e.id);
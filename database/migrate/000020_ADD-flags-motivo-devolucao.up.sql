@@ -0,0 +1,33 @@
+-- O que acontece com o epi devolvido passa a ser configurado no motivo, e não mais por id fixo.
+-- Destino: exatamente um entre voltar ao estoque, descartar ou mandar para higienização
+ALTER TABLE motivo_devolucao ADD COLUMN volta_estoque BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE motivo_devolucao ADD COLUMN descarte BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE motivo_devolucao ADD COLUMN higienizacao BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE motivo_devolucao ADD COLUMN exige_inspecao BOOLEAN NOT NULL DEFAULT FALSE; -- o item passa por inspeção antes do destino
+
+-- Mantém o comportamento antigo: desgaste, dano e vencimento eram descarte
+UPDATE motivo_devolucao
+SET volta_estoque = FALSE,
+    descarte = TRUE
+WHERE LOWER(motivo) IN ('desgaste', 'dano', 'vencimento');
+
+UPDATE motivo_devolucao
+SET volta_estoque = FALSE,
+    higienizacao = TRUE,
+    exige_inspecao = TRUE
+WHERE LOWER(motivo) = 'desligamento';
+
+ALTER TABLE motivo_devolucao ADD CONSTRAINT chk_motivo_devolucao_destino
+CHECK (volta_estoque::int + descarte::int + higienizacao::int = 1);
+
+-- Os motivos padrão das empresas novas já nascem com o destino certo
+CREATE OR REPLACE FUNCTION seed_motivos_devolucao(p_tenant_id INT) RETURNS VOID AS $$
+BEGIN
+    INSERT INTO motivo_devolucao (tenant_id, motivo, volta_estoque, descarte, higienizacao, exige_inspecao)
+    VALUES (p_tenant_id, 'Desgaste', FALSE, TRUE, FALSE, FALSE),
+           (p_tenant_id, 'Dano', FALSE, TRUE, FALSE, FALSE),
+           (p_tenant_id, 'Vencimento', FALSE, TRUE, FALSE, FALSE),
+           (p_tenant_id, 'Troca de tamanho', TRUE, FALSE, FALSE, FALSE),
+           (p_tenant_id, 'Desligamento', FALSE, FALSE, TRUE, TRUE);
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,23 @@
+-- Documento de devolução: várias devoluções (cada uma com seu motivo e troca opcional) assinadas de uma vez.
+-- As trocas do documento saem numa única entrega, ligada aqui pelo IdEntregaTroca
+CREATE TABLE devolucao_documento (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdFuncionario INT NOT NULL,
+    data_devolucao DATE NOT NULL,
+    assinatura_digital TEXT NOT NULL,
+    token_validacao TEXT NULL,
+    IdEntregaTroca INT NULL,
+    IdUsuario INT NULL,
+    criado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    cancelada_em TIMESTAMP NULL,
+    id_usuario_cancelamento INT NULL,
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id),
+    FOREIGN KEY (IdEntregaTroca) REFERENCES entrega_epi(id),
+    FOREIGN KEY (IdUsuario) REFERENCES usuarios(id),
+    FOREIGN KEY (id_usuario_cancelamento) REFERENCES usuarios(id)
+);
+
+ALTER TABLE devolucao ADD COLUMN IdDocumento INT NULL REFERENCES devolucao_documento(id);
+CREATE INDEX idx_devolucao_documento ON devolucao (IdDocumento) WHERE IdDocumento IS NOT NULL;
@@ -0,0 +1,25 @@
+-- Epi devolvido por motivo de higienização fica fora do estoque até a ordem de serviço ser concluída.
+-- As ordens abertas são o "pool" de itens em higienização/manutenção
+CREATE TABLE ordem_manutencao (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdEpi INT NOT NULL,
+    IdTamanho INT NOT NULL,
+    quantidade INT NOT NULL,
+    IdDevolucao INT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'aberta',
+    servico_realizado VARCHAR(255) NULL, -- o que foi feito (limpeza, troca de filtro, reparo...)
+    observacao VARCHAR(255) NULL,
+    aberta_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    concluida_em TIMESTAMP NULL,
+    IdUsuarioConclusao INT NULL,
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdEpi) REFERENCES epi(id),
+    FOREIGN KEY (IdTamanho) REFERENCES tamanho(id),
+    FOREIGN KEY (IdDevolucao) REFERENCES devolucao(id),
+    FOREIGN KEY (IdUsuarioConclusao) REFERENCES usuarios(id),
+    CONSTRAINT chk_ordem_manutencao_quantidade CHECK (quantidade > 0),
+    CONSTRAINT chk_ordem_manutencao_status CHECK (status IN ('aberta', 'liberada', 'descartada', 'cancelada'))
+);
+
+CREATE INDEX idx_ordem_manutencao_status ON ordem_manutencao (tenant_id, status);
@@ -0,0 +1,4 @@
+-- Busca de funcionario por nome sem diferenciar acento ("João" acha "joao")
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+CREATE INDEX IF NOT EXISTS idx_funcionario_tenant_ativo ON funcionario (tenant_id, ativo);
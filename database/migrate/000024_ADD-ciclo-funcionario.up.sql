@@ -0,0 +1,34 @@
+-- Admissão e desligamento do funcionario. Quem já existia fica sem data de admissão (não sabemos),
+-- os próximos recebem a data do cadastro por padrão
+ALTER TABLE funcionario ADD COLUMN data_admissao DATE NULL;
+ALTER TABLE funcionario ALTER COLUMN data_admissao SET DEFAULT CURRENT_DATE;
+ALTER TABLE funcionario ADD COLUMN data_desligamento DATE NULL;
+ALTER TABLE funcionario ADD COLUMN motivo_desligamento VARCHAR(255) NULL;
+ALTER TABLE funcionario ADD COLUMN IdUsuarioDesligamento INT NULL REFERENCES usuarios(id);
+
+-- os inativados antes desta versão viram desligados na data em que foram inativados
+UPDATE funcionario
+SET data_desligamento = deletado_em::date
+WHERE ativo = FALSE
+  AND deletado_em IS NOT NULL;
+
+-- Baixa de epi que o funcionario não vai devolver (extraviado, levado no desligamento...).
+-- Abate da posse como uma devolução, mas sem mexer em estoque
+CREATE TABLE baixa_posse (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdFuncionario INT NOT NULL,
+    IdEpi INT NOT NULL,
+    IdTamanho INT NOT NULL,
+    quantidade INT NOT NULL CHECK (quantidade > 0),
+    motivo VARCHAR(255) NOT NULL,
+    data_baixa TIMESTAMP NOT NULL DEFAULT NOW(),
+    IdUsuario INT NULL,
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id),
+    FOREIGN KEY (IdEpi) REFERENCES epi(id),
+    FOREIGN KEY (IdTamanho) REFERENCES tamanho(id),
+    FOREIGN KEY (IdUsuario) REFERENCES usuarios(id)
+);
+
+CREATE INDEX idx_baixa_posse_funcionario ON baixa_posse (tenant_id, IdFuncionario);
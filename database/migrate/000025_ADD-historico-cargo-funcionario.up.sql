@@ -0,0 +1,33 @@
+-- Histórico de departamento/função do funcionario. Cada linha vale de data_inicio (inclusive)
+-- até data_fim (exclusive); a linha aberta (data_fim NULL) é o cargo atual
+CREATE TABLE funcionario_cargo_historico (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdFuncionario INT NOT NULL,
+    IdDepartamento INT NOT NULL,
+    IdFuncao INT NOT NULL,
+    data_inicio DATE NOT NULL,
+    data_fim DATE NULL,
+    criado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id),
+    FOREIGN KEY (IdDepartamento) REFERENCES departamento(id),
+    FOREIGN KEY (IdFuncao) REFERENCES funcao(id),
+    CHECK (data_fim IS NULL OR data_fim >= data_inicio)
+);
+
+CREATE INDEX idx_cargo_historico_funcionario ON funcionario_cargo_historico (tenant_id, IdFuncionario, data_inicio);
+CREATE UNIQUE INDEX uq_cargo_historico_aberto ON funcionario_cargo_historico (IdFuncionario) WHERE data_fim IS NULL;
+
+-- O que aconteceu antes desta versão não tem como recuperar: o cargo atual passa a valer desde a
+-- admissão (ou desde a primeira entrega), para as entregas antigas continuarem aparecendo como hoje
+INSERT INTO funcionario_cargo_historico (tenant_id, IdFuncionario, IdDepartamento, IdFuncao, data_inicio, data_fim)
+SELECT
+    f.tenant_id, f.id, f.IdDepartamento, f.IdFuncao,
+    LEAST(
+        COALESCE(f.data_admissao, CURRENT_DATE),
+        COALESCE((SELECT MIN(ee.data_entrega) FROM entrega_epi ee WHERE ee.IdFuncionario = f.id), CURRENT_DATE),
+        COALESCE((SELECT MIN(d.data_devolucao) FROM devolucao d WHERE d.IdFuncionario = f.id), CURRENT_DATE)
+    ),
+    NULL
+FROM funcionario f;
@@ -0,0 +1,23 @@
+-- Dados pessoais do funcionario (eSocial e ficha de epi identificam o trabalhador pelo CPF).
+-- Todos opcionais: quem já está cadastrado continua válido até alguém completar o cadastro
+ALTER TABLE funcionario ADD COLUMN cpf VARCHAR(11) NULL;
+ALTER TABLE funcionario ADD COLUMN data_nascimento DATE NULL;
+ALTER TABLE funcionario ADD COLUMN email VARCHAR(150) NULL;
+ALTER TABLE funcionario ADD COLUMN telefone VARCHAR(20) NULL;
+
+-- o CPF é gravado só com os dígitos e não se repete dentro da empresa (desligados inclusos)
+CREATE UNIQUE INDEX uq_funcionario_cpf_tenant ON funcionario (tenant_id, cpf) WHERE cpf IS NOT NULL;
+
+-- LGPD: toda consulta aos dados pessoais completos fica registrada com quem consultou
+CREATE TABLE acesso_dados_pessoais (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdFuncionario INT NOT NULL,
+    IdUsuario INT NOT NULL,
+    acessado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id),
+    FOREIGN KEY (IdUsuario) REFERENCES usuarios(id)
+);
+
+CREATE INDEX idx_acesso_dados_pessoais_funcionario ON acesso_dados_pessoais (tenant_id, IdFuncionario);
@@ -0,0 +1,11 @@
+-- Dados da função que o S-2240 (condições ambientais do trabalho) exige e que o cadastro não tem:
+-- o agente nocivo (código da tabela 24 do eSocial) e a descrição das atividades
+CREATE TABLE funcao_esocial (
+    IdFuncao INT PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    cod_agente_nocivo VARCHAR(10) NOT NULL,
+    descricao_atividade VARCHAR(999) NOT NULL,
+    atualizado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (IdFuncao) REFERENCES funcao(id),
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id)
+);
@@ -0,0 +1,41 @@
+-- Treinamento de uso de epi (NR-6). Vale para um epi especifico OU para todo epi de um tipo de proteção,
+-- igual ao requisito por função
+CREATE TABLE treinamento (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    titulo VARCHAR(150) NOT NULL,
+    IdEpi INT NULL,
+    IdTipoProtecao INT NULL,
+    data_realizacao DATE NOT NULL,
+    instrutor VARCHAR(150) NOT NULL,
+    data_validade DATE NULL, -- NULL = não vence
+    ativo BOOLEAN NOT NULL DEFAULT TRUE,
+    criado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    deletado_em TIMESTAMP NULL,
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdEpi) REFERENCES epi(id),
+    FOREIGN KEY (IdTipoProtecao) REFERENCES tipo_protecao(id),
+    CONSTRAINT chk_treinamento_alvo CHECK ((IdEpi IS NULL) <> (IdTipoProtecao IS NULL)),
+    CONSTRAINT chk_treinamento_validade CHECK (data_validade IS NULL OR data_validade >= data_realizacao)
+);
+
+-- lista de presença
+CREATE TABLE treinamento_participante (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdTreinamento INT NOT NULL,
+    IdFuncionario INT NOT NULL,
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdTreinamento) REFERENCES treinamento(id),
+    FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id),
+    CONSTRAINT uq_treinamento_participante UNIQUE (IdTreinamento, IdFuncionario)
+);
+
+CREATE INDEX idx_treinamento_participante_funcionario ON treinamento_participante (tenant_id, IdFuncionario);
+
+-- O que a entrega faz quando o funcionario não tem treinamento válido para o epi:
+-- desligada (padrão), avisar (entrega sai marcada com pendência) ou bloquear
+ALTER TABLE empresas ADD COLUMN regra_treinamento_entrega VARCHAR(10) NOT NULL DEFAULT 'desligada'
+    CONSTRAINT chk_empresas_regra_treinamento CHECK (regra_treinamento_entrega IN ('desligada', 'avisar', 'bloquear'));
+
+ALTER TABLE entrega_epi ADD COLUMN pendencia_treinamento BOOLEAN NOT NULL DEFAULT FALSE;
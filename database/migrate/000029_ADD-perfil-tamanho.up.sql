@@ -0,0 +1,32 @@
+-- Tamanho que cada funcionario usa, por epi e por tipo de proteção, para a entrega já vir preenchida.
+-- origem 'entrega' = aprendido da última entrega; 'manual' = editado, e a entrega não sobrescreve mais
+CREATE TABLE perfil_tamanho (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdFuncionario INT NOT NULL,
+    IdEpi INT NULL,
+    IdTipoProtecao INT NULL,
+    IdTamanho INT NOT NULL,
+    origem VARCHAR(10) NOT NULL DEFAULT 'entrega',
+    atualizado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id),
+    FOREIGN KEY (IdEpi) REFERENCES epi(id),
+    FOREIGN KEY (IdTipoProtecao) REFERENCES tipo_protecao(id),
+    FOREIGN KEY (IdTamanho) REFERENCES tamanho(id),
+    CONSTRAINT chk_perfil_tamanho_alvo CHECK ((IdEpi IS NULL) <> (IdTipoProtecao IS NULL)),
+    CONSTRAINT chk_perfil_tamanho_origem CHECK (origem IN ('entrega', 'manual')),
+    CONSTRAINT uq_perfil_tamanho UNIQUE NULLS NOT DISTINCT (IdFuncionario, IdEpi, IdTipoProtecao)
+);
+
+-- Carga inicial com o histórico: o tamanho da entrega mais recente de cada epi e de cada tipo de proteção
+INSERT INTO perfil_tamanho (tenant_id, IdFuncionario, IdEpi, IdTipoProtecao, IdTamanho)
+SELECT DISTINCT ON (ee.IdFuncionario, alvo.IdEpi, alvo.IdTipoProtecao)
+    ee.tenant_id, ee.IdFuncionario, alvo.IdEpi, alvo.IdTipoProtecao, i.IdTamanho
+FROM epis_entregues i
+INNER JOIN entrega_epi ee ON i.IdEntrega = ee.id
+INNER JOIN epi e ON i.IdEpi = e.id
+CROSS JOIN LATERAL (VALUES (e.id, NULL::int), (NULL::int, e.IdTipoProtecao)) AS alvo(IdEpi, IdTipoProtecao)
+WHERE ee.ativo = TRUE
+  AND i.ativo = TRUE
+ORDER BY ee.IdFuncionario, alvo.IdEpi, alvo.IdTipoProtecao, ee.data_entrega DESC, ee.id DESC;
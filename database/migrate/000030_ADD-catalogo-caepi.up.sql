@@ -0,0 +1,62 @@
+-- Importações do arquivo CAEPI publicado pelo Ministério do Trabalho
+CREATE TABLE importacao_caepi (
+    id SERIAL PRIMARY KEY,
+    arquivo VARCHAR(255) NOT NULL,
+    total_registros INT NOT NULL DEFAULT 0,
+    novos INT NOT NULL DEFAULT 0,
+    alterados INT NOT NULL DEFAULT 0,
+    importado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    sinalizada_em TIMESTAMP NULL -- quando o job já marcou os epis dos tenants afetados
+);
+
+-- Catálogo oficial de CAs, compartilhado entre todos os tenants (por isso sem tenant_id)
+CREATE TABLE catalogo_ca (
+    ca VARCHAR(20) PRIMARY KEY,
+    situacao VARCHAR(30) NOT NULL,
+    validade DATE NULL,
+    cnpj_fabricante VARCHAR(14) NULL,
+    fabricante VARCHAR(255) NOT NULL,
+    equipamento VARCHAR(255) NOT NULL,
+    descricao TEXT NOT NULL DEFAULT '',
+    situacao_anterior VARCHAR(30) NULL,
+    validade_anterior DATE NULL,
+    IdImportacao INT NOT NULL, -- última importação que trouxe o CA
+    IdImportacaoAlteracao INT NULL, -- última importação em que a situação ou a validade mudou
+    FOREIGN KEY (IdImportacao) REFERENCES importacao_caepi(id),
+    FOREIGN KEY (IdImportacaoAlteracao) REFERENCES importacao_caepi(id)
+);
+
+CREATE INDEX idx_catalogo_ca_alteracao ON catalogo_ca (IdImportacaoAlteracao);
+
+-- Área de carga do COPY; as linhas só vivem durante a transação da importação
+CREATE UNLOGGED TABLE catalogo_ca_carga (
+    IdImportacao INT NOT NULL,
+    ca VARCHAR(20) NOT NULL,
+    situacao VARCHAR(30) NOT NULL,
+    validade DATE NULL,
+    cnpj_fabricante VARCHAR(14) NULL,
+    fabricante VARCHAR(255) NOT NULL,
+    equipamento VARCHAR(255) NOT NULL,
+    descricao TEXT NOT NULL
+);
+
+-- Epis do tenant cujo CA foi cancelado/suspenso ou renovado numa importação
+CREATE TABLE alerta_ca (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdEpi INT NOT NULL,
+    IdImportacao INT NOT NULL,
+    tipo VARCHAR(10) NOT NULL,
+    situacao VARCHAR(30) NOT NULL,
+    validade_anterior DATE NULL, -- validade que o epi tinha no cadastro do tenant
+    validade_nova DATE NULL,
+    criado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolvido_em TIMESTAMP NULL,
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdEpi) REFERENCES epi(id),
+    FOREIGN KEY (IdImportacao) REFERENCES importacao_caepi(id),
+    CONSTRAINT chk_alerta_ca_tipo CHECK (tipo IN ('cancelado', 'renovado')),
+    CONSTRAINT uq_alerta_ca UNIQUE (IdEpi, IdImportacao)
+);
+
+CREATE INDEX idx_alerta_ca_tenant ON alerta_ca (tenant_id) WHERE resolvido_em IS NULL;
@@ -0,0 +1,42 @@
+-- Categorias do Anexo I da NR-6: são do sistema (iguais para todas as empresas) e os tipos
+-- de proteção de cada empresa passam a ser subcategorias delas
+CREATE TABLE categoria_protecao (
+    id SERIAL PRIMARY KEY,
+    codigo CHAR(1) NOT NULL UNIQUE, -- letra do grupo no Anexo I
+    nome VARCHAR(100) NOT NULL
+);
+
+INSERT INTO categoria_protecao (codigo, nome)
+VALUES ('A', 'Proteção da cabeça'),
+       ('B', 'Proteção dos olhos e face'),
+       ('C', 'Proteção auditiva'),
+       ('D', 'Proteção respiratória'),
+       ('E', 'Proteção do tronco'),
+       ('F', 'Proteção dos membros superiores'),
+       ('G', 'Proteção dos membros inferiores'),
+       ('H', 'Proteção do corpo inteiro'),
+       ('I', 'Proteção contra quedas com diferença de nível');
+
+-- NULL só para os tipos antigos que não deu para classificar pelo nome; os novos já nascem com categoria
+ALTER TABLE tipo_protecao ADD COLUMN IdCategoria INT NULL;
+ALTER TABLE tipo_protecao ADD CONSTRAINT fk_tipo_protecao_categoria
+FOREIGN KEY (IdCategoria) REFERENCES categoria_protecao(id);
+
+CREATE INDEX idx_tipo_protecao_categoria ON tipo_protecao (IdCategoria);
+
+-- Classifica os tipos que já existem pelas palavras mais comuns no nome; o que sobrar a empresa classifica na tela
+UPDATE tipo_protecao tp
+SET IdCategoria = c.id
+FROM categoria_protecao c
+WHERE tp.IdCategoria IS NULL
+  AND c.codigo = CASE
+        WHEN tp.nome ~* '(queda|altura|cinto|talabarte|trava)' THEN 'I'
+        WHEN tp.nome ~* '(respira|m[aá]scara|respirador|filtro)' THEN 'D'
+        WHEN tp.nome ~* '(audi|auricular|ouvido|abafador)' THEN 'C'
+        WHEN tp.nome ~* '(olho|face|facial|[oó]culos|viseira)' THEN 'B'
+        WHEN tp.nome ~* '(cabe[cç]a|capacete|capuz)' THEN 'A'
+        WHEN tp.nome ~* '(superior|m[aã]o|luva|bra[cç]o|manga)' THEN 'F'
+        WHEN tp.nome ~* '(inferior|p[eé]s|perna|bota|botina|cal[cç]ado|perneira)' THEN 'G'
+        WHEN tp.nome ~* '(corpo inteiro|macac)' THEN 'H'
+        WHEN tp.nome ~* '(tronco|avental|colete|vestimenta)' THEN 'E'
+      END;
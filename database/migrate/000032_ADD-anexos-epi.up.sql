@@ -0,0 +1,25 @@
+-- Anexos do epi (foto, certificado do CA, manual, ficha técnica). O arquivo fica no armazenamento
+-- configurado na aplicação; aqui fica só a chave para achar ele de volta
+CREATE TABLE anexo_epi (
+    id SERIAL PRIMARY KEY,
+    tenant_id INT NOT NULL,
+    IdEpi INT NOT NULL,
+    tipo VARCHAR(20) NOT NULL,
+    nome_arquivo VARCHAR(255) NOT NULL, -- nome original, usado no download
+    mime VARCHAR(100) NOT NULL, -- detectado pelo conteúdo, não pelo que o cliente mandou
+    tamanho BIGINT NOT NULL,
+    chave VARCHAR(255) NOT NULL UNIQUE,
+    principal BOOLEAN NOT NULL DEFAULT FALSE, -- foto que aparece no cadastro do epi
+    IdUsuario INT NULL,
+    criado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+    FOREIGN KEY (IdEpi) REFERENCES epi(id),
+    FOREIGN KEY (IdUsuario) REFERENCES usuarios(id),
+    CONSTRAINT chk_anexo_epi_tipo CHECK (tipo IN ('foto', 'certificado_ca', 'manual', 'ficha_tecnica')),
+    CONSTRAINT chk_anexo_epi_principal CHECK (NOT principal OR tipo = 'foto')
+);
+
+CREATE INDEX idx_anexo_epi ON anexo_epi (IdEpi);
+
+-- no máximo uma foto principal por epi
+CREATE UNIQUE INDEX uq_anexo_epi_principal ON anexo_epi (IdEpi) WHERE principal;
@@ -0,0 +1,6 @@
+-- Motivo com exige_inspecao segura o item fora do estoque numa ordem de inspeção;
+-- o destino (estoque ou descarte) sai da conclusão da ordem
+ALTER TABLE ordem_manutencao ADD COLUMN tipo VARCHAR(20) NOT NULL DEFAULT 'higienizacao';
+
+ALTER TABLE ordem_manutencao ADD CONSTRAINT chk_ordem_manutencao_tipo
+CHECK (tipo IN ('higienizacao', 'inspecao'));
@@ -0,0 +1,9 @@
+-- Tudo que saiu da posse do funcionario: devoluções não canceladas e baixas (desligamento, perda).
+-- Toda conta de saldo em posse subtrai daqui, para nenhuma esquecer as baixas
+CREATE VIEW saida_posse AS
+SELECT d.tenant_id, d.IdFuncionario, d.IdEpi, d.IdTamanho, d.quantidadeAdevolver AS quantidade, d.data_devolucao AS data_saida
+FROM devolucao d
+WHERE d.cancelada_em IS NULL
+UNION ALL
+SELECT b.tenant_id, b.IdFuncionario, b.IdEpi, b.IdTamanho, b.quantidade, b.data_baixa::date AS data_saida
+FROM baixa_posse b;
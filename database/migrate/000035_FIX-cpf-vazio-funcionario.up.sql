@@ -0,0 +1,2 @@
+-- CPF gravado como texto vazio pela edição de dados pessoais: sem CPF é NULL, fora do índice único
+UPDATE funcionario SET cpf = NULL WHERE cpf = '';
@@ -0,0 +1,8 @@
+-- Guarda o destino aplicado na devolução e o lote que recebeu o item: o motivo pode mudar depois,
+-- e o cancelamento precisa tirar do mesmo lote o que a devolução colocou nele.
+-- Devoluções anteriores ficam com NULL e o cancelamento delas não mexe no estoque
+ALTER TABLE devolucao ADD COLUMN destino VARCHAR(20) NULL;
+ALTER TABLE devolucao ADD COLUMN IdEntradaEstoque INT NULL REFERENCES entrada_epi(id);
+
+ALTER TABLE devolucao ADD CONSTRAINT chk_devolucao_destino
+CHECK (destino IN ('estoque', 'descarte', 'higienizacao', 'inspecao'));
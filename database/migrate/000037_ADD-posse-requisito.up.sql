@@ -0,0 +1,42 @@
+-- Quanto de cada item entregue ainda está com o funcionario: as saídas (devoluções e baixas) abatem
+-- primeiro as entregas mais antigas do mesmo epi/tamanho, a mesma conta do PosseService
+CREATE VIEW posse_item_entregue AS
+SELECT 
+    i.id, ee.tenant_id, ee.IdFuncionario, i.IdEpi, e.IdTipoProtecao, i.IdTamanho, ee.data_entrega, i.quantidade,
+    LEAST(i.quantidade, GREATEST(0,
+        SUM(i.quantidade) OVER (
+            PARTITION BY ee.tenant_id, ee.IdFuncionario, i.IdEpi, i.IdTamanho
+            ORDER BY ee.data_entrega, i.id
+        ) - COALESCE(s.quantidade, 0)
+    ))::int AS em_posse
+FROM epis_entregues i
+INNER JOIN entrega_epi ee ON i.IdEntrega = ee.id
+INNER JOIN epi e ON i.IdEpi = e.id
+LEFT JOIN (
+    SELECT tenant_id, IdFuncionario, IdEpi, IdTamanho, SUM(quantidade) AS quantidade
+    FROM saida_posse
+    GROUP BY tenant_id, IdFuncionario, IdEpi, IdTamanho
+) s ON s.tenant_id = ee.tenant_id 
+    AND s.IdFuncionario = ee.IdFuncionario 
+    AND s.IdEpi = i.IdEpi 
+    AND s.IdTamanho = i.IdTamanho
+WHERE ee.cancelada_em IS NULL
+  AND i.ativo = TRUE;
+
+-- Posse do funcionario que conta para um requisito da função: só o que foi entregue dentro do intervalo
+-- de troca e ainda não saiu. A sugestão de entrega e o relatório de conformidade usam os dois a mesma conta
+CREATE OR REPLACE FUNCTION posse_requisito(p_funcionario_id INT, p_tenant_id INT, p_requisito_id INT)
+RETURNS TABLE (quantidade_em_posse INT, ultima_entrega DATE) AS $$
+    SELECT 
+        COALESCE(SUM(p.em_posse) FILTER (
+            WHERE r.periodicidade_dias IS NULL OR p.data_entrega > CURRENT_DATE - r.periodicidade_dias
+        ), 0)::int,
+        MAX(p.data_entrega)::date
+    FROM requisito_funcao r
+    LEFT JOIN posse_item_entregue p ON p.IdFuncionario = p_funcionario_id
+        AND p.tenant_id = r.tenant_id
+        AND (r.IdEpi IS NULL OR p.IdEpi = r.IdEpi)
+        AND (r.IdTipoProtecao IS NULL OR p.IdTipoProtecao = r.IdTipoProtecao)
+    WHERE r.id = p_requisito_id
+      AND r.tenant_id = p_tenant_id; -- SEGURANÇA
+$$ LANGUAGE sql STABLE;
@@ -0,0 +1,2 @@
+-- Cada PIN novo troca a versão: o token do portal guarda a versão do login e deixa de valer quando o PIN muda
+ALTER TABLE funcionario ADD COLUMN pin_versao INT NOT NULL DEFAULT 0;
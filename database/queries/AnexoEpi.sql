@@ -0,0 +1,53 @@
+-- name: AdicionarAnexoEpi :one
+INSERT INTO anexo_epi (tenant_id, IdEpi, tipo, nome_arquivo, mime, tamanho, chave, principal, IdUsuario)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, criado_em;
+
+-- name: TemFotoPrincipal :one
+SELECT EXISTS (
+    SELECT 1 FROM anexo_epi
+    WHERE IdEpi = $1
+      AND tenant_id = $2 -- SEGURANÇA
+      AND principal = TRUE
+);
+
+-- name: DesmarcarFotoPrincipal :exec
+UPDATE anexo_epi
+SET principal = FALSE
+WHERE IdEpi = $1
+  AND tenant_id = $2 -- SEGURANÇA
+  AND principal = TRUE;
+
+-- name: PromoverFotoPrincipal :exec
+-- Quando a foto principal sai, a foto mais recente que sobrou assume
+UPDATE anexo_epi
+SET principal = TRUE
+WHERE id = (
+    SELECT a.id FROM anexo_epi a
+    WHERE a.IdEpi = $1
+      AND a.tenant_id = $2 -- SEGURANÇA
+      AND a.tipo = 'foto'
+    ORDER BY a.criado_em DESC, a.id DESC
+    LIMIT 1
+);
+
+-- name: ListarAnexosEpi :many
+SELECT id, tipo, nome_arquivo, mime, tamanho, principal, criado_em
+FROM anexo_epi
+WHERE IdEpi = $1
+  AND tenant_id = $2 -- SEGURANÇA
+ORDER BY principal DESC, criado_em DESC, id DESC;
+
+-- name: BuscarAnexoEpi :one
+SELECT id, tipo, nome_arquivo, mime, tamanho, chave, principal
+FROM anexo_epi
+WHERE id = $1
+  AND IdEpi = $2
+  AND tenant_id = $3; -- SEGURANÇA
+
+-- name: RemoverAnexoEpi :one
+DELETE FROM anexo_epi
+WHERE id = $1
+  AND IdEpi = $2
+  AND tenant_id = $3 -- SEGURANÇA
+RETURNING chave, principal;
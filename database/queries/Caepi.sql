@@ -0,0 +1,117 @@
+-- name: IniciarImportacaoCaepi :one
+INSERT INTO importacao_caepi (arquivo)
+VALUES ($1)
+RETURNING id;
+
+-- name: CarregarCatalogoCa :copyfrom
+INSERT INTO catalogo_ca_carga (
+    IdImportacao, ca, situacao, validade, cnpj_fabricante, fabricante, equipamento, descricao
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: MesclarCatalogoCa :one
+-- Passa a carga para o catálogo. Quando a situação ou a validade de um CA muda, guarda o valor
+-- anterior e marca a importação, que é o que o job usa para sinalizar os epis dos tenants
+WITH mesclados AS (
+    INSERT INTO catalogo_ca (
+        ca, situacao, validade, cnpj_fabricante, fabricante, equipamento, descricao, IdImportacao
+    )
+    SELECT ca, situacao, validade, cnpj_fabricante, fabricante, equipamento, descricao, IdImportacao
+    FROM catalogo_ca_carga
+    WHERE IdImportacao = sqlc.arg('id_importacao')
+    ON CONFLICT (ca) DO UPDATE
+    SET situacao = EXCLUDED.situacao,
+        validade = EXCLUDED.validade,
+        cnpj_fabricante = EXCLUDED.cnpj_fabricante,
+        fabricante = EXCLUDED.fabricante,
+        equipamento = EXCLUDED.equipamento,
+        descricao = EXCLUDED.descricao,
+        IdImportacao = EXCLUDED.IdImportacao,
+        situacao_anterior = CASE
+            WHEN catalogo_ca.situacao IS DISTINCT FROM EXCLUDED.situacao
+              OR catalogo_ca.validade IS DISTINCT FROM EXCLUDED.validade
+            THEN catalogo_ca.situacao ELSE catalogo_ca.situacao_anterior END,
+        validade_anterior = CASE
+            WHEN catalogo_ca.situacao IS DISTINCT FROM EXCLUDED.situacao
+              OR catalogo_ca.validade IS DISTINCT FROM EXCLUDED.validade
+            THEN catalogo_ca.validade ELSE catalogo_ca.validade_anterior END,
+        IdImportacaoAlteracao = CASE
+            WHEN catalogo_ca.situacao IS DISTINCT FROM EXCLUDED.situacao
+              OR catalogo_ca.validade IS DISTINCT FROM EXCLUDED.validade
+            THEN EXCLUDED.IdImportacao ELSE catalogo_ca.IdImportacaoAlteracao END
+    RETURNING (xmax = 0) AS novo, IdImportacaoAlteracao
+)
+SELECT
+    COUNT(*) FILTER (WHERE novo) AS novos,
+    COUNT(*) FILTER (WHERE NOT novo AND IdImportacaoAlteracao = sqlc.arg('id_importacao')) AS alterados
+FROM mesclados;
+
+-- name: LimparCargaCaepi :exec
+DELETE FROM catalogo_ca_carga
+WHERE IdImportacao = $1;
+
+-- name: FinalizarImportacaoCaepi :exec
+UPDATE importacao_caepi
+SET total_registros = $2,
+    novos = $3,
+    alterados = $4
+WHERE id = $1;
+
+-- name: BuscarCatalogoCa :one
+SELECT ca, situacao, validade, cnpj_fabricante, fabricante, equipamento, descricao
+FROM catalogo_ca
+WHERE ca = $1;
+
+-- name: ImportacoesCaepiPendentes :many
+SELECT id
+FROM importacao_caepi
+WHERE sinalizada_em IS NULL
+ORDER BY id;
+
+-- name: SinalizarEpisCa :execrows
+-- Marca os epis ativos de todos os tenants cujo CA foi cancelado/suspenso nesta importação,
+-- ou renovado com validade maior que a cadastrada no epi
+INSERT INTO alerta_ca (tenant_id, IdEpi, IdImportacao, tipo, situacao, validade_anterior, validade_nova)
+SELECT
+    e.tenant_id,
+    e.id,
+    c.IdImportacaoAlteracao,
+    CASE WHEN c.situacao IN ('CANCELADO', 'SUSPENSO') THEN 'cancelado' ELSE 'renovado' END,
+    c.situacao,
+    e.validade_CA,
+    c.validade
+FROM catalogo_ca c
+INNER JOIN epi e ON e.CA = c.ca AND e.ativo = TRUE
+WHERE c.IdImportacaoAlteracao = $1
+  AND (c.situacao IN ('CANCELADO', 'SUSPENSO') OR c.validade > e.validade_CA)
+ON CONFLICT (IdEpi, IdImportacao) DO NOTHING;
+
+-- name: MarcarImportacaoSinalizada :exec
+UPDATE importacao_caepi
+SET sinalizada_em = NOW()
+WHERE id = $1;
+
+-- name: ListarAlertasCa :many
+SELECT
+    a.id,
+    a.IdEpi,
+    e.nome AS epi_nome,
+    e.CA AS epi_ca,
+    a.tipo,
+    a.situacao,
+    a.validade_anterior,
+    a.validade_nova,
+    a.criado_em,
+    a.resolvido_em
+FROM alerta_ca a
+INNER JOIN epi e ON a.IdEpi = e.id
+WHERE a.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND (sqlc.arg('todos')::bool OR a.resolvido_em IS NULL)
+ORDER BY a.criado_em DESC, a.id DESC;
+
+-- name: ResolverAlertaCa :execrows
+UPDATE alerta_ca
+SET resolvido_em = NOW()
+WHERE id = $1
+  AND tenant_id = $2 -- SEGURANÇA
+  AND resolvido_em IS NULL;
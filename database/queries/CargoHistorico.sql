@@ -0,0 +1,30 @@
+-- name: BuscarCargoAtual :one
+SELECT id, IdDepartamento, IdFuncao, data_inicio
+FROM funcionario_cargo_historico
+WHERE IdFuncionario = $1
+  AND tenant_id = $2 -- SEGURANÇA
+  AND data_fim IS NULL;
+
+-- name: EncerrarCargo :exec
+UPDATE funcionario_cargo_historico
+SET data_fim = $1
+WHERE id = $2
+  AND tenant_id = $3; -- SEGURANÇA
+
+-- name: AbrirCargo :exec
+INSERT INTO funcionario_cargo_historico (tenant_id, IdFuncionario, IdDepartamento, IdFuncao, data_inicio)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListarHistoricoCargo :many
+-- Do cargo atual para o mais antigo. Cargo que começou e terminou no mesmo dia (correção de cadastro) não entra
+SELECT
+    h.id, h.data_inicio, h.data_fim,
+    d.id as dep_id, d.nome as dep_nome,
+    f.id as funcao_id, f.nome as funcao_nome
+FROM funcionario_cargo_historico h
+INNER JOIN departamento d ON h.IdDepartamento = d.id
+INNER JOIN funcao f ON h.IdFuncao = f.id
+WHERE h.IdFuncionario = $1
+  AND h.tenant_id = $2 -- SEGURANÇA
+  AND (h.data_fim IS NULL OR h.data_fim > h.data_inicio)
+ORDER BY h.data_inicio DESC, h.id DESC;
@@ -0,0 +1,26 @@
+-- name: RelatorioConsumo :many
+-- Itens entregues no período por tipo de proteção (entregas canceladas e itens estornados ficam de fora).
+-- O custo é o valor unitário do lote de onde o item saiu
+SELECT
+    cp.id as categoria_id,
+    cp.codigo as categoria_codigo,
+    cp.nome as categoria_nome,
+    tp.id as protecao_id,
+    tp.nome as protecao_nome,
+    SUM(i.quantidade)::int as quantidade,
+    SUM(i.quantidade * en.valor_unitario)::numeric(14,2) as valor_total,
+    COUNT(DISTINCT ee.IdFuncionario)::int as funcionarios
+FROM epis_entregues i
+INNER JOIN entrega_epi ee ON i.IdEntrega = ee.id
+INNER JOIN funcionario f ON ee.IdFuncionario = f.id
+INNER JOIN epi e ON i.IdEpi = e.id
+INNER JOIN tipo_protecao tp ON e.IdTipoProtecao = tp.id
+INNER JOIN entrada_epi en ON i.IdEntrada = en.id
+LEFT JOIN categoria_protecao cp ON tp.IdCategoria = cp.id
+WHERE ee.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND ee.cancelada_em IS NULL
+  AND i.ativo = TRUE
+  AND ee.data_entrega BETWEEN sqlc.arg('data_inicio')::date AND sqlc.arg('data_fim')::date
+  AND (sqlc.narg('id_departamento')::int IS NULL OR f.IdDepartamento = sqlc.narg('id_departamento')::int)
+GROUP BY cp.id, cp.codigo, cp.nome, tp.id, tp.nome
+ORDER BY cp.codigo NULLS LAST, tp.nome;
@@ -7,8 +7,9 @@ INSERT INTO devolucao (
 -- name: AddTrocaEpi :one
 INSERT INTO devolucao (
     tenant_id, IdFuncionario, IdEpi, IdMotivo, data_devolucao, IdTamanho, 
-    quantidadeAdevolver, IdEpiNovo, IdTamanhoNovo, quantidadeNova, assinatura_digital, id_usuario_cancelamento, token_validacao
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+    quantidadeAdevolver, IdEpiNovo, IdTamanhoNovo, quantidadeNova, assinatura_digital, id_usuario_cancelamento, token_validacao,
+    destino, IdEntradaEstoque
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 RETURNING id;
 
 -- name: AddEntregaVinculada :one
@@ -19,8 +20,8 @@ RETURNING id;
 -- name: ListarDevolucoes :many
 SELECT 
     d.id, d.IdFuncionario, f.nome as func_nome, f.matricula,
-    f.IdDepartamento, dd.nome as dep_nome,
-    f.IdFuncao, ff.nome as funcao_nome,
+    dd.id as IdDepartamento, dd.nome as dep_nome,
+    ff.id as IdFuncao, ff.nome as funcao_nome,
     d.IdEpi, e.nome as epi_antigo_nome, e.fabricante as epi_antigo_fab, e.CA as epi_antigo_ca,
     d.IdTamanho as tam_antigo_id, t.tamanho as tam_antigo_nome, e.descricao as desc_antiga,
     e.validade_CA as validade_ca_antiga, e.IdTipoProtecao as idprotecaoAntigo, tp.nome as tipo_protecao_nomeAntigo,
@@ -34,8 +35,12 @@ SELECT
 FROM devolucao d
 INNER JOIN epi e ON d.IdEpi = e.id
 INNER JOIN funcionario f ON d.IdFuncionario = f.id  
-INNER JOIN departamento dd ON f.IdDepartamento = dd.id
-INNER JOIN funcao ff ON f.IdFuncao = ff.id
+-- departamento/função de quando a devolução foi feita, não o cargo atual
+LEFT JOIN funcionario_cargo_historico h ON h.IdFuncionario = f.id
+    AND h.data_inicio <= d.data_devolucao
+    AND (h.data_fim IS NULL OR d.data_devolucao < h.data_fim)
+INNER JOIN departamento dd ON COALESCE(h.IdDepartamento, f.IdDepartamento) = dd.id
+INNER JOIN funcao ff ON COALESCE(h.IdFuncao, f.IdFuncao) = ff.id
 INNER JOIN tamanho t ON d.IdTamanho = t.id
 INNER JOIN motivo_devolucao m ON d.IdMotivo = m.id
 INNER JOIN tipo_protecao tp ON e.IdTipoProtecao = tp.id
@@ -61,4 +66,5 @@ SET cancelada_em = NOW(),
 WHERE id = $1 
   AND tenant_id = $3 -- SEGURANÇA: Só cancela se pertencer à empresa correta
   AND cancelada_em IS NULL
-RETURNING id;
\ No newline at end of file
+  AND IdDocumento IS NULL -- item de documento só sai cancelando o documento inteiro
+RETURNING id, quantidadeAdevolver, IdEntradaEstoque;
\ No newline at end of file
@@ -0,0 +1,65 @@
+-- name: AddDevolucaoDocumento :one
+INSERT INTO devolucao_documento (
+    tenant_id, IdFuncionario, data_devolucao, assinatura_digital, token_validacao, IdUsuario
+) VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id;
+
+-- name: VincularDevolucaoDocumento :exec
+UPDATE devolucao
+SET IdDocumento = $1
+WHERE id = $2
+  AND tenant_id = $3; -- SEGURANÇA
+
+-- name: VincularEntregaTrocaDocumento :exec
+UPDATE devolucao_documento
+SET IdEntregaTroca = $1
+WHERE id = $2
+  AND tenant_id = $3; -- SEGURANÇA
+
+-- name: BuscarDevolucaoDocumento :one
+SELECT 
+    dd.id, dd.data_devolucao, dd.IdEntregaTroca, dd.criado_em, dd.cancelada_em,
+    f.id as func_id, f.nome as func_nome, f.matricula
+FROM devolucao_documento dd
+INNER JOIN funcionario f ON dd.IdFuncionario = f.id
+WHERE dd.id = $1
+  AND dd.tenant_id = $2; -- SEGURANÇA
+
+-- name: ListarItensDevolucaoDocumento :many
+SELECT 
+    d.id, d.quantidadeAdevolver,
+    e.id as epi_id, e.nome as epi_nome,
+    t.id as tam_id, t.tamanho as tam_nome,
+    m.id as motivo_id, m.motivo,
+    d.IdEpiNovo, en.nome as epi_novo_nome,
+    d.IdTamanhoNovo, tn.tamanho as tam_novo_nome,
+    d.quantidadeNova
+FROM devolucao d
+INNER JOIN epi e ON d.IdEpi = e.id
+INNER JOIN tamanho t ON d.IdTamanho = t.id
+INNER JOIN motivo_devolucao m ON d.IdMotivo = m.id
+LEFT JOIN epi en ON d.IdEpiNovo = en.id
+LEFT JOIN tamanho tn ON d.IdTamanhoNovo = tn.id
+WHERE d.IdDocumento = $1
+  AND d.tenant_id = $2 -- SEGURANÇA
+ORDER BY d.id;
+
+-- name: CancelarDevolucaoDocumento :one
+UPDATE devolucao_documento
+SET cancelada_em = NOW(),
+    id_usuario_cancelamento = $2
+WHERE id = $1
+  AND tenant_id = $3 -- SEGURANÇA
+  AND cancelada_em IS NULL
+RETURNING IdEntregaTroca;
+
+-- name: CancelarDevolucoesDoDocumento :many
+-- Devolve a quantidade e o lote que recebeu cada item, para o cancelamento tirar do estoque o que voltou
+UPDATE devolucao
+SET cancelada_em = NOW(),
+    ativo = FALSE,
+    id_usuario_devolucao_cancelamento = $2
+WHERE IdDocumento = $1
+  AND tenant_id = $3 -- SEGURANÇA
+  AND cancelada_em IS NULL
+RETURNING id, quantidadeAdevolver, IdEntradaEstoque;
@@ -0,0 +1,51 @@
+-- name: DefinirFuncaoEsocial :execrows
+-- Só grava se a função existir e for do tenant; 0 linhas = função não encontrada
+INSERT INTO funcao_esocial (IdFuncao, tenant_id, cod_agente_nocivo, descricao_atividade)
+SELECT f.id, f.tenant_id, sqlc.arg('cod_agente_nocivo'), sqlc.arg('descricao_atividade')
+FROM funcao f
+WHERE f.id = sqlc.arg('id_funcao')
+  AND f.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND f.ativo = TRUE
+ON CONFLICT (IdFuncao) DO UPDATE
+SET cod_agente_nocivo = EXCLUDED.cod_agente_nocivo,
+    descricao_atividade = EXCLUDED.descricao_atividade,
+    atualizado_em = NOW();
+
+-- name: BuscarEmpregadorEsocial :one
+SELECT razao_social, cnpj
+FROM empresas
+WHERE id = $1;
+
+-- name: ListarExposicoesS2240 :many
+-- Um registro por funcionario + função da época + CA entregue no período.
+-- O início da condição é o início do cargo (ou a admissão, ou a primeira entrega, o que existir)
+SELECT
+    fn.id as funcionario_id,
+    fn.nome,
+    fn.matricula,
+    fn.cpf,
+    ff.id as funcao_id,
+    ff.nome as funcao_nome,
+    d.nome as departamento_nome,
+    fe.cod_agente_nocivo,
+    fe.descricao_atividade,
+    e.CA,
+    MIN(COALESCE(h.data_inicio, fn.data_admissao, ee.data_entrega))::date as inicio_condicao
+FROM entrega_epi ee
+INNER JOIN funcionario fn ON ee.IdFuncionario = fn.id
+-- departamento/função de quando a entrega foi feita, não o cargo atual
+LEFT JOIN funcionario_cargo_historico h ON h.IdFuncionario = fn.id
+    AND h.data_inicio <= ee.data_entrega
+    AND (h.data_fim IS NULL OR ee.data_entrega < h.data_fim)
+INNER JOIN departamento d ON COALESCE(h.IdDepartamento, fn.IdDepartamento) = d.id
+INNER JOIN funcao ff ON COALESCE(h.IdFuncao, fn.IdFuncao) = ff.id
+LEFT JOIN funcao_esocial fe ON fe.IdFuncao = ff.id
+INNER JOIN epis_entregues i ON i.IdEntrega = ee.id
+INNER JOIN epi e ON i.IdEpi = e.id
+WHERE ee.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND ee.cancelada_em IS NULL
+  AND i.ativo = TRUE
+  AND ee.data_entrega BETWEEN sqlc.arg('data_inicio')::date AND sqlc.arg('data_fim')::date
+  AND (sqlc.narg('ids_funcionarios')::int[] IS NULL OR fn.id = ANY(sqlc.narg('ids_funcionarios')::int[]))
+GROUP BY fn.id, fn.nome, fn.matricula, fn.cpf, ff.id, ff.nome, d.nome, fe.cod_agente_nocivo, fe.descricao_atividade, e.CA
+ORDER BY fn.nome, fn.id, ff.id, e.CA;
@@ -1,9 +1,9 @@
 -- name: AddEntregaEpi :one
 INSERT INTO entrega_epi (
     tenant_id, -- Novo campo
-    IdFuncionario, data_entrega, assinatura, IdTroca, token_validacao, id_usuario_entrega
+    IdFuncionario, data_entrega, assinatura, IdTroca, token_validacao, id_usuario_entrega, pendencia_treinamento
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING id;
 
 -- name: AddItemEntregue :one
@@ -23,7 +23,7 @@ RETURNING IdEntrada, quantidade;
 
 -- name: ListarEntregas :many
 SELECT 
-    ee.id as entrega_id, ee.data_entrega, ee.assinatura, ee.token_validacao, ee.id_usuario_entrega,   
+    ee.id as entrega_id, ee.data_entrega, ee.assinatura, ee.token_validacao, ee.id_usuario_entrega, ee.pendencia_treinamento,
     f.id as func_id, f.nome as func_nome, f.matricula,
     d.id as dep_id, d.nome as dep_nome,
     ff.id as funcao_id, ff.nome as funcao_nome,
@@ -34,8 +34,12 @@ SELECT
     COUNT(*) OVER() as total_geral
 FROM entrega_epi ee
 INNER JOIN funcionario f ON ee.IdFuncionario = f.id
-INNER JOIN departamento d ON f.IdDepartamento = d.id
-INNER JOIN funcao ff ON f.IdFuncao = ff.id
+-- departamento/função de quando a entrega foi feita, não o cargo atual
+LEFT JOIN funcionario_cargo_historico h ON h.IdFuncionario = f.id
+    AND h.data_inicio <= ee.data_entrega
+    AND (h.data_fim IS NULL OR ee.data_entrega < h.data_fim)
+INNER JOIN departamento d ON COALESCE(h.IdDepartamento, f.IdDepartamento) = d.id
+INNER JOIN funcao ff ON COALESCE(h.IdFuncao, f.IdFuncao) = ff.id
 INNER JOIN epis_entregues i ON i.IdEntrega = ee.id
 INNER JOIN epi e ON i.IdEpi = e.id
 INNER JOIN tipo_protecao tp ON e.IdTipoProtecao = tp.id
@@ -11,9 +11,11 @@ VALUES ($1, $2, $3);
 SELECT 
     e.id, e.nome, e.fabricante, e.CA, e.descricao,
     e.validade_CA, e.alerta_minimo, e.IdTipoProtecao, 
-    tp.nome as tipo_protecao_nome
+    tp.nome as tipo_protecao_nome,
+    a.id as foto_id, a.nome_arquivo as foto_nome, a.mime as foto_mime
 FROM epi e
 INNER JOIN tipo_protecao tp ON e.IdTipoProtecao = tp.id
+LEFT JOIN anexo_epi a ON a.IdEpi = e.id AND a.principal = TRUE
 WHERE e.id = $1 
   AND e.tenant_id = $2 -- SEGURANÇA
   AND e.ativo = TRUE;
@@ -31,9 +33,11 @@ SELECT
     e.id, e.nome, e.fabricante, e.CA, e.descricao,
     e.validade_CA, e.alerta_minimo, e.IdTipoProtecao, 
     tp.nome as tipo_protecao_nome,
+    a.id as foto_id, a.nome_arquivo as foto_nome, a.mime as foto_mime,
     COUNT(*) OVER() as total_geral
 FROM epi e
 INNER JOIN tipo_protecao tp ON e.IdTipoProtecao = tp.id
+LEFT JOIN anexo_epi a ON a.IdEpi = e.id AND a.principal = TRUE
 WHERE e.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA: Filtro de Tenant
   AND e.ativo = TRUE
 ORDER BY e.id
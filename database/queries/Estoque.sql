@@ -1,10 +1,25 @@
 -- name: ListarLotesParaConsumo :many
 -- O PostgreSQL usa FOR UPDATE para travar apenas as linhas desse cliente específico.
-SELECT id, quantidadeAtual, data_validade, valor_unitario 
+-- quantidade_livre desconta as reservas ativas presas ao lote, menos a reserva que a entrega está atendendo
+SELECT 
+    id, 
+    quantidadeAtual, 
+    data_validade, 
+    valor_unitario,
+    (quantidadeAtual - COALESCE((
+        SELECT SUM(r.quantidade)
+        FROM reserva_estoque r
+        WHERE r.IdEntrada = entrada_epi.id
+          AND r.tenant_id = entrada_epi.tenant_id
+          AND r.consumida_em IS NULL
+          AND r.cancelada_em IS NULL
+          AND r.expira_em > NOW()
+          AND (sqlc.narg('id_reserva')::int IS NULL OR r.id <> sqlc.narg('id_reserva'))
+    ), 0))::int as quantidade_livre
 FROM entrada_epi 
-WHERE tenant_id = $1 -- SEGURANÇA: Só busca lotes da empresa logada
-  AND IdEpi = $2 
-  AND IdTamanho = $3 
+WHERE tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA: Só busca lotes da empresa logada
+  AND IdEpi = sqlc.arg('idepi') 
+  AND IdTamanho = sqlc.arg('idtamanho') 
   AND quantidadeAtual > 0 
   AND data_validade >= CURRENT_DATE
   AND ativo = TRUE
@@ -33,7 +48,8 @@ INSERT INTO epis_entregues (
 ) 
 VALUES ($1, $2, $3, $4, $5, $6);
 
--- name: DevolverItemAoEstoque :exec
+-- name: DevolverItemAoEstoque :one
+-- Devolve o id do lote que recebeu o item; sem lote ativo do epi/tamanho não volta linha
 UPDATE entrada_epi
 SET quantidadeAtual = entrada_epi.quantidadeAtual + $4 -- Quantidade é o $4 agora
 WHERE id = (
@@ -46,4 +62,5 @@ WHERE id = (
     ORDER BY ee.data_entrada DESC
     LIMIT 1
 )
-AND tenant_id = $1; -- SEGURANÇA NO UPDATE
\ No newline at end of file
+AND tenant_id = $1 -- SEGURANÇA NO UPDATE
+RETURNING id;
\ No newline at end of file
@@ -1,8 +1,17 @@
 -- name: AddFuncionario :exec
-INSERT INTO funcionario (tenant_id, nome, matricula, IdDepartamento, IdFuncao) 
-VALUES ($1, $2, $3, $4, $5);
+-- Já abre o primeiro cargo no histórico, valendo desde a admissão
+WITH novo AS (
+    INSERT INTO funcionario (tenant_id, nome, matricula, IdDepartamento, IdFuncao, data_admissao, cpf, data_nascimento, email, telefone) 
+    VALUES ($1, $2, $3, $4, $5, COALESCE(sqlc.narg('data_admissao')::date, CURRENT_DATE),
+            sqlc.narg('cpf'), sqlc.narg('data_nascimento'), sqlc.narg('email'), sqlc.narg('telefone'))
+    RETURNING id, tenant_id, IdDepartamento, IdFuncao, data_admissao
+)
+INSERT INTO funcionario_cargo_historico (tenant_id, IdFuncionario, IdDepartamento, IdFuncao, data_inicio)
+SELECT tenant_id, id, IdDepartamento, IdFuncao, data_admissao
+FROM novo;
 
 -- name: BuscaFuncionario :one
+-- Traz também os desligados: o histórico do funcionario continua consultável pela matrícula
 SELECT 
     fn.id, 
     fn.nome, 
@@ -10,13 +19,17 @@ SELECT
     fn.IdDepartamento, 
     d.nome as departamento_nome,
     fn.IdFuncao, 
-    f.nome as funcao_nome
+    f.nome as funcao_nome,
+    fn.ativo,
+    fn.data_admissao,
+    fn.data_desligamento,
+    fn.motivo_desligamento,
+    fn.cpf
 FROM funcionario fn
 INNER JOIN departamento d ON fn.IdDepartamento = d.id
 INNER JOIN funcao f ON fn.IdFuncao = f.id
 WHERE fn.matricula = $1 
-  AND fn.tenant_id = $2 -- IMPORTANTE: Matrícula só é única dentro do tenant
-  AND fn.ativo = TRUE;
+  AND fn.tenant_id = $2; -- IMPORTANTE: Matrícula só é única dentro do tenant
 
 -- name: BuscarTodosFuncionarios :many
 SELECT 
@@ -26,21 +39,82 @@ SELECT
     fn.IdDepartamento, 
     d.nome as departamento_nome,
     fn.IdFuncao, 
-    f.nome as funcao_nome
+    f.nome as funcao_nome,
+    fn.ativo,
+    fn.data_admissao,
+    fn.data_desligamento,
+    fn.motivo_desligamento,
+    fn.cpf
 FROM funcionario fn
 INNER JOIN departamento d ON fn.IdDepartamento = d.id
 INNER JOIN funcao f ON fn.IdFuncao = f.id
-WHERE fn.tenant_id = $1 -- SEGURANÇA: Só lista funcionários da empresa atual
-  AND fn.ativo = TRUE;
+WHERE fn.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA: Só lista funcionários da empresa atual
+  AND (
+    (sqlc.arg('situacao')::text = 'ativos' AND fn.ativo = TRUE) OR
+    (sqlc.arg('situacao')::text = 'desligados' AND fn.ativo = FALSE) OR
+    sqlc.arg('situacao')::text = 'todos'
+  )
+  AND (sqlc.narg('id_departamento')::int IS NULL OR fn.IdDepartamento = sqlc.narg('id_departamento')::int)
+  AND (sqlc.narg('id_funcao')::int IS NULL OR fn.IdFuncao = sqlc.narg('id_funcao')::int)
+  AND (sqlc.narg('nome')::text IS NULL OR unaccent(fn.nome) ILIKE '%' || unaccent(sqlc.narg('nome')::text) || '%')
+  AND (sqlc.narg('matricula')::text IS NULL OR fn.matricula ILIKE '%' || sqlc.narg('matricula')::text || '%')
+ORDER BY
+    CASE WHEN sqlc.arg('ordenar')::text = 'nome' AND NOT sqlc.arg('decrescente')::boolean THEN fn.nome END ASC,
+    CASE WHEN sqlc.arg('ordenar')::text = 'nome' AND sqlc.arg('decrescente')::boolean THEN fn.nome END DESC,
+    CASE WHEN sqlc.arg('ordenar')::text = 'matricula' AND NOT sqlc.arg('decrescente')::boolean THEN fn.matricula END ASC,
+    CASE WHEN sqlc.arg('ordenar')::text = 'matricula' AND sqlc.arg('decrescente')::boolean THEN fn.matricula END DESC,
+    CASE WHEN sqlc.arg('ordenar')::text = 'departamento' AND NOT sqlc.arg('decrescente')::boolean THEN d.nome END ASC,
+    CASE WHEN sqlc.arg('ordenar')::text = 'departamento' AND sqlc.arg('decrescente')::boolean THEN d.nome END DESC,
+    CASE WHEN sqlc.arg('ordenar')::text = 'funcao' AND NOT sqlc.arg('decrescente')::boolean THEN f.nome END ASC,
+    CASE WHEN sqlc.arg('ordenar')::text = 'funcao' AND sqlc.arg('decrescente')::boolean THEN f.nome END DESC,
+    fn.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ContarFuncionariosFiltrados :one
+SELECT COUNT(*)
+FROM funcionario fn
+WHERE fn.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND (
+    (sqlc.arg('situacao')::text = 'ativos' AND fn.ativo = TRUE) OR
+    (sqlc.arg('situacao')::text = 'desligados' AND fn.ativo = FALSE) OR
+    sqlc.arg('situacao')::text = 'todos'
+  )
+  AND (sqlc.narg('id_departamento')::int IS NULL OR fn.IdDepartamento = sqlc.narg('id_departamento')::int)
+  AND (sqlc.narg('id_funcao')::int IS NULL OR fn.IdFuncao = sqlc.narg('id_funcao')::int)
+  AND (sqlc.narg('nome')::text IS NULL OR unaccent(fn.nome) ILIKE '%' || unaccent(sqlc.narg('nome')::text) || '%')
+  AND (sqlc.narg('matricula')::text IS NULL OR fn.matricula ILIKE '%' || sqlc.narg('matricula')::text || '%');
 
 -- name: DeletarFuncionario :execrows
 UPDATE funcionario
 SET ativo = FALSE,
-    deletado_em = NOW()
+    deletado_em = NOW(),
+    data_desligamento = CURRENT_DATE
 WHERE id = $1 
   AND tenant_id = $2 -- SEGURANÇA
   AND ativo = TRUE;
 
+-- name: DesligarFuncionario :execrows
+UPDATE funcionario
+SET ativo = FALSE,
+    deletado_em = NOW(),
+    data_desligamento = sqlc.arg('data_desligamento'),
+    motivo_desligamento = sqlc.arg('motivo_desligamento'),
+    IdUsuarioDesligamento = sqlc.arg('id_usuario')
+WHERE id = sqlc.arg('id')
+  AND tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND ativo = TRUE;
+
+-- name: UpdateFuncionarioDadosPessoais :execrows
+-- Campo nulo mantém o valor atual; limpar_cpf apaga o CPF (NULL, fora do índice único)
+UPDATE funcionario
+SET cpf = CASE WHEN sqlc.arg('limpar_cpf')::bool THEN NULL ELSE COALESCE(sqlc.narg('cpf'), cpf) END,
+    data_nascimento = COALESCE(sqlc.narg('data_nascimento'), data_nascimento),
+    email = COALESCE(sqlc.narg('email'), email),
+    telefone = COALESCE(sqlc.narg('telefone'), telefone)
+WHERE id = sqlc.arg('id')
+  AND tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND ativo = TRUE;
+
 -- name: UpdateFuncionarioNome :execrows
 UPDATE funcionario
 SET nome = $2
@@ -76,4 +150,40 @@ INNER JOIN departamento d ON fn.IdDepartamento = d.id
 INNER JOIN funcao f ON fn.IdFuncao = f.id
 WHERE fn.id = $1 
   AND fn.tenant_id = $2 -- SEGURANÇA
-  AND fn.ativo = TRUE;
\ No newline at end of file
+  AND fn.ativo = TRUE;
+-- name: ListarIdsFuncionariosPorGrupo :many
+SELECT id
+FROM funcionario
+WHERE tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND ativo = TRUE
+  AND (sqlc.narg('id_departamento')::int IS NULL OR IdDepartamento = sqlc.narg('id_departamento')::int)
+  AND (sqlc.narg('id_funcao')::int IS NULL OR IdFuncao = sqlc.narg('id_funcao')::int)
+ORDER BY nome;
+
+-- name: ExisteCpfFuncionario :one
+-- id_ignorar é o próprio funcionario na atualização (0 no cadastro)
+SELECT EXISTS (
+    SELECT 1
+    FROM funcionario
+    WHERE tenant_id = sqlc.arg('tenant_id')
+      AND cpf = sqlc.arg('cpf')
+      AND id <> sqlc.arg('id_ignorar')::int
+);
+
+-- name: BuscarDadosPessoaisFuncionario :one
+SELECT
+    fn.id,
+    fn.nome,
+    fn.matricula,
+    fn.cpf,
+    fn.data_nascimento,
+    fn.data_admissao,
+    fn.email,
+    fn.telefone
+FROM funcionario fn
+WHERE fn.id = $1
+  AND fn.tenant_id = $2; -- SEGURANÇA
+
+-- name: RegistrarAcessoDadosPessoais :exec
+INSERT INTO acesso_dados_pessoais (tenant_id, IdFuncionario, IdUsuario)
+VALUES ($1, $2, $3);
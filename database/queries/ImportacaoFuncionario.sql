@@ -0,0 +1,15 @@
+-- name: CriarDepartamentoImportacao :one
+INSERT INTO departamento (tenant_id, nome)
+VALUES ($1, $2)
+RETURNING id;
+
+-- name: CriarFuncaoImportacao :one
+INSERT INTO funcao (tenant_id, nome, IdDepartamento)
+VALUES ($1, $2, $3)
+RETURNING id;
+
+-- name: ListarMatriculasTenant :many
+-- inclui os inativos: a unique (tenant_id, matricula) vale para eles também
+SELECT matricula, ativo
+FROM funcionario
+WHERE tenant_id = $1;
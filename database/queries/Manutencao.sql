@@ -0,0 +1,83 @@
+-- name: AbrirOrdemManutencao :one
+INSERT INTO ordem_manutencao (tenant_id, IdEpi, IdTamanho, quantidade, IdDevolucao, tipo)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id;
+
+-- name: BuscarOrdemParaConclusao :one
+-- Trava a ordem: duas conclusões ao mesmo tempo não devolvem o item duas vezes ao estoque
+SELECT id, IdEpi, IdTamanho, quantidade, status
+FROM ordem_manutencao
+WHERE id = $1
+  AND tenant_id = $2 -- SEGURANÇA
+FOR UPDATE;
+
+-- name: ConcluirOrdemManutencao :exec
+UPDATE ordem_manutencao
+SET status = $2,
+    servico_realizado = $3,
+    observacao = $4,
+    concluida_em = NOW(),
+    IdUsuarioConclusao = $5
+WHERE id = $1
+  AND tenant_id = $6 -- SEGURANÇA
+  AND status = 'aberta';
+
+-- name: TravarOrdensDaDevolucao :many
+-- Trava as ordens antes do cancelamento: uma conclusão em paralelo não pode devolver o item ao estoque no meio
+SELECT o.id, o.status
+FROM ordem_manutencao o
+WHERE o.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND o.IdDevolucao IN (
+      SELECT d.id FROM devolucao d
+      WHERE d.tenant_id = sqlc.arg('tenant_id')
+        AND (d.id = sqlc.narg('id_devolucao') OR d.IdDocumento = sqlc.narg('id_documento'))
+  )
+FOR UPDATE;
+
+-- name: CancelarOrdensDaDevolucao :exec
+-- Cancelar a devolução tira do pool o que ainda não foi concluído
+UPDATE ordem_manutencao
+SET status = 'cancelada',
+    concluida_em = NOW()
+WHERE tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND status = 'aberta'
+  AND IdDevolucao IN (
+      SELECT d.id FROM devolucao d
+      WHERE d.tenant_id = sqlc.arg('tenant_id')
+        AND (d.id = sqlc.narg('id_devolucao') OR d.IdDocumento = sqlc.narg('id_documento'))
+  );
+
+-- name: ListarOrdensManutencao :many
+SELECT 
+    o.id, o.tipo, o.status, o.quantidade, o.servico_realizado, o.observacao,
+    o.aberta_em, o.concluida_em, o.IdDevolucao,
+    e.id as epi_id, e.nome as epi_nome, e.CA,
+    t.id as tam_id, t.tamanho as tam_nome,
+    u.nome as usuario_conclusao,
+    COUNT(*) OVER() as total_geral
+FROM ordem_manutencao o
+INNER JOIN epi e ON o.IdEpi = e.id
+INNER JOIN tamanho t ON o.IdTamanho = t.id
+LEFT JOIN usuarios u ON o.IdUsuarioConclusao = u.id
+WHERE o.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND (sqlc.narg('status')::text IS NULL OR o.status = sqlc.narg('status'))
+  AND (sqlc.narg('tipo')::text IS NULL OR o.tipo = sqlc.narg('tipo'))
+  AND (sqlc.narg('id_epi')::int IS NULL OR o.IdEpi = sqlc.narg('id_epi'))
+ORDER BY o.aberta_em DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: RelatorioManutencao :many
+-- Itens parados em higienização/manutenção agora, por epi/tamanho
+SELECT 
+    e.id as epi_id, e.nome as epi_nome, e.CA,
+    t.id as tam_id, t.tamanho as tam_nome,
+    SUM(o.quantidade)::int as quantidade,
+    COUNT(*)::int as ordens,
+    MIN(o.aberta_em)::timestamp as aberta_mais_antiga
+FROM ordem_manutencao o
+INNER JOIN epi e ON o.IdEpi = e.id
+INNER JOIN tamanho t ON o.IdTamanho = t.id
+WHERE o.tenant_id = $1 -- SEGURANÇA
+  AND o.status = 'aberta'
+GROUP BY e.id, e.nome, e.CA, t.id, t.tamanho
+ORDER BY e.nome, t.tamanho;
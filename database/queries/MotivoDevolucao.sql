@@ -1,9 +1,9 @@
 -- name: AddMotivoDevolucao :exec
-INSERT INTO motivo_devolucao (tenant_id, motivo) 
-VALUES ($1, $2);
+INSERT INTO motivo_devolucao (tenant_id, motivo, volta_estoque, descarte, higienizacao, exige_inspecao) 
+VALUES ($1, $2, $3, $4, $5, $6);
 
 -- name: BuscaMotivoDevolucao :one
-SELECT id, motivo 
+SELECT id, motivo, volta_estoque, descarte, higienizacao, exige_inspecao 
 FROM motivo_devolucao 
 WHERE id = $1 
   AND tenant_id = $2 -- SEGURANÇA
@@ -11,16 +11,27 @@ WHERE id = $1
 LIMIT 1;
 
 -- name: BuscaTodosMotivosDevolucao :many
-SELECT id, motivo 
+SELECT id, motivo, volta_estoque, descarte, higienizacao, exige_inspecao 
 FROM motivo_devolucao 
 WHERE tenant_id = $1 -- SEGURANÇA: Lista apenas os motivos desta empresa
   AND ativo = TRUE
 ORDER BY motivo ASC;
 
+-- name: UpdateMotivoDevolucao :execrows
+UPDATE motivo_devolucao
+SET motivo = $3,
+    volta_estoque = $4,
+    descarte = $5,
+    higienizacao = $6,
+    exige_inspecao = $7
+WHERE id = $1
+  AND tenant_id = $2 -- SEGURANÇA
+  AND ativo = TRUE;
+
 -- name: DeleteMotivoDevolucao :execrows
 UPDATE motivo_devolucao
 SET ativo = FALSE,
     deletado_em = NOW()
 WHERE id = $1 
   AND tenant_id = $2 -- SEGURANÇA
-  AND ativo = TRUE;
\ No newline at end of file
+  AND ativo = TRUE;
@@ -0,0 +1,73 @@
+-- name: AprenderPerfilTamanho :exec
+-- Grava o tamanho entregue no perfil do funcionario, para o epi e para o tipo de proteção dele.
+-- O que foi editado à mão não é sobrescrito
+INSERT INTO perfil_tamanho (tenant_id, IdFuncionario, IdEpi, IdTipoProtecao, IdTamanho)
+SELECT e.tenant_id, sqlc.arg('id_funcionario')::int, alvo.IdEpi, alvo.IdTipoProtecao, sqlc.arg('id_tamanho')::int
+FROM epi e
+CROSS JOIN LATERAL (VALUES (e.id, NULL::int), (NULL::int, e.IdTipoProtecao)) AS alvo(IdEpi, IdTipoProtecao)
+WHERE e.id = sqlc.arg('id_epi')
+  AND e.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+ON CONFLICT (IdFuncionario, IdEpi, IdTipoProtecao) DO UPDATE
+SET IdTamanho = EXCLUDED.IdTamanho,
+    atualizado_em = NOW()
+WHERE perfil_tamanho.origem = 'entrega';
+
+-- name: DefinirPerfilTamanho :execrows
+-- 0 linhas = funcionario não encontrado no tenant
+INSERT INTO perfil_tamanho (tenant_id, IdFuncionario, IdEpi, IdTipoProtecao, IdTamanho, origem)
+SELECT fn.tenant_id, fn.id, sqlc.narg('id_epi')::int, sqlc.narg('id_tipo_protecao')::int, sqlc.arg('id_tamanho')::int, 'manual'
+FROM funcionario fn
+WHERE fn.id = sqlc.arg('id_funcionario')
+  AND fn.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND fn.ativo = TRUE
+ON CONFLICT (IdFuncionario, IdEpi, IdTipoProtecao) DO UPDATE
+SET IdTamanho = EXCLUDED.IdTamanho,
+    origem = 'manual',
+    atualizado_em = NOW();
+
+-- name: ListarPerfilTamanho :many
+SELECT
+    p.id,
+    p.IdEpi,
+    e.nome AS epi_nome,
+    p.IdTipoProtecao,
+    tp.nome AS protecao_nome,
+    p.IdTamanho,
+    t.tamanho,
+    p.origem,
+    p.atualizado_em
+FROM perfil_tamanho p
+INNER JOIN tamanho t ON p.IdTamanho = t.id
+LEFT JOIN epi e ON p.IdEpi = e.id
+LEFT JOIN tipo_protecao tp ON p.IdTipoProtecao = tp.id
+WHERE p.IdFuncionario = sqlc.arg('id_funcionario')
+  AND p.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+ORDER BY tp.nome NULLS LAST, e.nome;
+
+-- name: RemoverPerfilTamanho :execrows
+-- Apagar uma linha manual devolve o epi/tipo para o aprendizado pela entrega
+DELETE FROM perfil_tamanho
+WHERE id = sqlc.arg('id')
+  AND IdFuncionario = sqlc.arg('id_funcionario')
+  AND tenant_id = sqlc.arg('tenant_id'); -- SEGURANÇA
+
+-- name: TamanhosSugeridos :many
+-- Um tamanho por epi: o manual ganha do aprendido e o do próprio epi ganha do tipo de proteção.
+-- O do tipo de proteção só vale se o tamanho existir para o epi
+SELECT e.id AS id_epi, s.IdTamanho, s.tamanho
+FROM epi e
+CROSS JOIN LATERAL (
+    SELECT p.IdTamanho, t.tamanho
+    FROM perfil_tamanho p
+    INNER JOIN tamanho t ON p.IdTamanho = t.id
+    WHERE p.IdFuncionario = sqlc.arg('id_funcionario')
+      AND p.tenant_id = e.tenant_id
+      AND (p.IdEpi = e.id
+           OR (p.IdTipoProtecao = e.IdTipoProtecao AND EXISTS (
+                SELECT 1 FROM tamanhos_epis te
+                WHERE te.IdEpi = e.id AND te.IdTamanho = p.IdTamanho AND te.ativo = TRUE)))
+    ORDER BY (p.origem = 'manual') DESC, (p.IdEpi IS NOT NULL) DESC, p.atualizado_em DESC
+    LIMIT 1
+) s
+WHERE e.id = ANY(sqlc.arg('ids_epis')::int[])
+  AND e.tenant_id = sqlc.arg('tenant_id'); -- SEGURANÇA
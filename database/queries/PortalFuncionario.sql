@@ -0,0 +1,71 @@
+-- name: BuscarFuncionarioParaLogin :one
+SELECT id, nome, matricula, pin_hash, tentativas_pin, pin_bloqueado_ate, pin_versao
+FROM funcionario
+WHERE matricula = $1 
+  AND tenant_id = $2 -- SEGURANÇA
+  AND ativo = TRUE
+LIMIT 1;
+
+-- name: RegistrarFalhaPin :one
+UPDATE funcionario
+SET tentativas_pin = tentativas_pin + 1
+WHERE id = $1 
+  AND tenant_id = $2 -- SEGURANÇA
+RETURNING tentativas_pin;
+
+-- name: BloquearPin :exec
+UPDATE funcionario
+SET tentativas_pin = 0,
+    pin_bloqueado_ate = $2
+WHERE id = $1 
+  AND tenant_id = $3; -- SEGURANÇA
+
+-- name: ZerarTentativasPin :exec
+UPDATE funcionario
+SET tentativas_pin = 0,
+    pin_bloqueado_ate = NULL
+WHERE id = $1 
+  AND tenant_id = $2; -- SEGURANÇA
+
+-- name: DefinirPinFuncionario :execrows
+UPDATE funcionario
+SET pin_hash = $2,
+    pin_versao = pin_versao + 1, -- derruba os tokens emitidos com o PIN anterior
+    tentativas_pin = 0,
+    pin_bloqueado_ate = NULL
+WHERE id = $1 
+  AND tenant_id = $3 -- SEGURANÇA
+  AND ativo = TRUE;
+
+-- name: BuscarSessaoPortal :one
+-- Versão do PIN do funcionario ainda ativo; sem linha o token do portal não vale mais
+SELECT pin_versao
+FROM funcionario
+WHERE id = $1
+  AND tenant_id = $2 -- SEGURANÇA
+  AND ativo = TRUE
+  AND pin_hash IS NOT NULL;
+
+-- name: ListarAssinaturasPendentes :many
+-- Entregas que o funcionario ainda não deu ciência pelo portal
+SELECT 
+    ee.id as entrega_id, ee.data_entrega,
+    e.nome as epi_nome, t.tamanho as tam_nome, i.quantidade
+FROM entrega_epi ee
+INNER JOIN epis_entregues i ON i.IdEntrega = ee.id AND i.ativo = TRUE
+INNER JOIN epi e ON i.IdEpi = e.id
+INNER JOIN tamanho t ON i.IdTamanho = t.id
+WHERE ee.IdFuncionario = $1 
+  AND ee.tenant_id = $2 -- SEGURANÇA
+  AND ee.cancelada_em IS NULL
+  AND ee.ciente_em IS NULL
+ORDER BY ee.data_entrega DESC, ee.id;
+
+-- name: ConfirmarCienciaEntrega :execrows
+UPDATE entrega_epi
+SET ciente_em = NOW()
+WHERE id = $1 
+  AND IdFuncionario = $2 -- só a própria entrega
+  AND tenant_id = $3 -- SEGURANÇA
+  AND cancelada_em IS NULL
+  AND ciente_em IS NULL;
@@ -0,0 +1,58 @@
+-- name: ListarItensEntreguesFuncionario :many
+-- Cada item entregue (e não cancelado) ao funcionario, do mais antigo para o mais novo, com o lote de origem
+SELECT 
+    i.IdEntrega, ee.data_entrega,
+    i.IdEntrada, en.lote, en.data_validade as validade_lote,
+    e.id as epi_id, e.nome as epi_nome, e.CA,
+    t.id as tam_id, t.tamanho as tam_nome,
+    i.quantidade
+FROM epis_entregues i
+INNER JOIN entrega_epi ee ON i.IdEntrega = ee.id
+INNER JOIN entrada_epi en ON i.IdEntrada = en.id
+INNER JOIN epi e ON i.IdEpi = e.id
+INNER JOIN tamanho t ON i.IdTamanho = t.id
+WHERE ee.IdFuncionario = sqlc.arg('id_funcionario')
+  AND ee.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND ee.cancelada_em IS NULL
+  AND i.ativo = TRUE
+ORDER BY e.nome, t.tamanho, ee.data_entrega, i.id;
+
+-- name: ListarDevolvidoFuncionario :many
+-- Total que saiu da posse por epi/tamanho: devoluções não canceladas mais as baixas
+SELECT s.IdEpi, s.IdTamanho, SUM(s.quantidade)::int as quantidade
+FROM saida_posse s
+WHERE s.IdFuncionario = sqlc.arg('id_funcionario')
+  AND s.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+GROUP BY s.IdEpi, s.IdTamanho;
+
+-- name: TravarFuncionarioPosse :exec
+-- Segura o funcionario até o fim da transação: duas devoluções ao mesmo tempo não usam o mesmo saldo
+SELECT id FROM funcionario 
+WHERE id = $1 AND tenant_id = $2 
+FOR UPDATE;
+
+-- name: QuantidadeEmPosse :one
+SELECT (
+    COALESCE((
+        SELECT SUM(i.quantidade)
+        FROM epis_entregues i
+        INNER JOIN entrega_epi ee ON i.IdEntrega = ee.id
+        WHERE ee.IdFuncionario = sqlc.arg('id_funcionario')
+          AND ee.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+          AND ee.cancelada_em IS NULL
+          AND i.ativo = TRUE
+          AND i.IdEpi = sqlc.arg('id_epi')
+          AND i.IdTamanho = sqlc.arg('id_tamanho')
+    ), 0) - COALESCE((
+        SELECT SUM(s.quantidade)
+        FROM saida_posse s
+        WHERE s.IdFuncionario = sqlc.arg('id_funcionario')
+          AND s.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+          AND s.IdEpi = sqlc.arg('id_epi')
+          AND s.IdTamanho = sqlc.arg('id_tamanho')
+    ), 0)
+)::int as em_posse;
+
+-- name: AddBaixaPosse :exec
+INSERT INTO baixa_posse (tenant_id, IdFuncionario, IdEpi, IdTamanho, quantidade, motivo, IdUsuario)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
@@ -0,0 +1,34 @@
+-- name: ReservarChaveIdempotente :one
+-- Só devolve linha quando a chave é nova ou quando ficou presa em processamento (queda no meio da requisição)
+INSERT INTO requisicao_idempotente (tenant_id, chave, hash_requisicao)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, chave) DO UPDATE
+SET hash_requisicao = EXCLUDED.hash_requisicao,
+    criado_em = NOW()
+WHERE requisicao_idempotente.status_resposta IS NULL
+  AND requisicao_idempotente.criado_em < NOW() - INTERVAL '5 minutes'
+RETURNING id;
+
+-- name: BuscarChaveIdempotente :one
+SELECT 
+    id, 
+    hash_requisicao, 
+    status_resposta, 
+    corpo_resposta
+FROM requisicao_idempotente
+WHERE tenant_id = $2 -- SEGURANÇA
+  AND chave = $1;
+
+-- name: ConcluirChaveIdempotente :exec
+UPDATE requisicao_idempotente
+SET status_resposta = $2,
+    corpo_resposta = $3,
+    concluida_em = NOW()
+WHERE tenant_id = $4 -- SEGURANÇA
+  AND id = $1;
+
+-- name: LiberarChaveIdempotente :exec
+DELETE FROM requisicao_idempotente
+WHERE id = $1 
+  AND tenant_id = $2 -- SEGURANÇA
+  AND status_resposta IS NULL;
@@ -0,0 +1,103 @@
+-- name: AddRequisitoFuncao :one
+INSERT INTO requisito_funcao (
+    tenant_id, IdFuncao, IdEpi, IdTipoProtecao, quantidade, periodicidade_dias
+)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id;
+
+-- name: BuscarRequisitosPorFuncao :many
+SELECT 
+    r.id, 
+    r.IdFuncao, 
+    r.IdEpi, 
+    e.nome as epi_nome, 
+    e.CA as epi_ca,
+    r.IdTipoProtecao, 
+    tp.nome as protecao_nome,
+    r.quantidade, 
+    r.periodicidade_dias
+FROM requisito_funcao r
+LEFT JOIN epi e ON r.IdEpi = e.id
+LEFT JOIN tipo_protecao tp ON r.IdTipoProtecao = tp.id
+WHERE r.IdFuncao = $1 
+  AND r.tenant_id = $2 -- SEGURANÇA
+  AND r.ativo = TRUE
+ORDER BY r.id;
+
+-- name: UpdateRequisitoFuncao :execrows
+UPDATE requisito_funcao
+SET quantidade = $2,
+    periodicidade_dias = $3
+WHERE id = $1 
+  AND tenant_id = $4 -- SEGURANÇA
+  AND ativo = TRUE;
+
+-- name: DeletarRequisitoFuncao :execrows
+UPDATE requisito_funcao
+SET ativo = FALSE,
+    deletado_em = NOW()
+WHERE id = $1 
+  AND tenant_id = $2 -- SEGURANÇA
+  AND ativo = TRUE;
+
+-- name: BuscarSugestaoEntrega :many
+-- Cruza o que a função do funcionário exige com o que ele ainda tem em posse
+-- dentro do intervalo de troca de cada requisito (a conta fica na função posse_requisito)
+SELECT 
+    r.id, 
+    r.IdEpi, 
+    e.nome as epi_nome, 
+    r.IdTipoProtecao, 
+    tp.nome as protecao_nome,
+    r.quantidade, 
+    r.periodicidade_dias,
+    pr.quantidade_em_posse,
+    pr.ultima_entrega
+FROM funcionario f
+INNER JOIN requisito_funcao r ON r.IdFuncao = f.IdFuncao 
+    AND r.tenant_id = f.tenant_id 
+    AND r.ativo = TRUE
+LEFT JOIN epi e ON r.IdEpi = e.id
+LEFT JOIN tipo_protecao tp ON r.IdTipoProtecao = tp.id
+CROSS JOIN LATERAL posse_requisito(f.id, f.tenant_id, r.id) pr
+WHERE f.id = sqlc.arg('id_funcionario') 
+  AND f.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND f.ativo = TRUE
+ORDER BY r.id;
+
+-- name: BuscarRelatorioConformidade :many
+-- Mesma conta da sugestão de entrega (função posse_requisito), mas para todos os funcionarios ativos da empresa
+SELECT 
+    f.id as funcionario_id,
+    f.nome as funcionario_nome,
+    f.matricula,
+    d.id as departamento_id,
+    d.nome as departamento_nome,
+    fn.nome as funcao_nome,
+    r.id as requisito_id, 
+    r.IdEpi, 
+    e.nome as epi_nome, 
+    r.IdTipoProtecao, 
+    tp.nome as protecao_nome,
+    r.quantidade, 
+    r.periodicidade_dias,
+    pr.quantidade_em_posse,
+    pr.ultima_entrega,
+    cp.id as categoria_id,
+    cp.codigo as categoria_codigo,
+    cp.nome as categoria_nome
+FROM funcionario f
+INNER JOIN departamento d ON f.IdDepartamento = d.id
+INNER JOIN funcao fn ON f.IdFuncao = fn.id
+INNER JOIN requisito_funcao r ON r.IdFuncao = f.IdFuncao 
+    AND r.tenant_id = f.tenant_id 
+    AND r.ativo = TRUE
+LEFT JOIN epi e ON r.IdEpi = e.id
+LEFT JOIN tipo_protecao tp ON r.IdTipoProtecao = tp.id
+LEFT JOIN tipo_protecao tpe ON e.IdTipoProtecao = tpe.id -- requisito de epi específico: categoria vem do tipo do epi
+LEFT JOIN categoria_protecao cp ON cp.id = COALESCE(tp.IdCategoria, tpe.IdCategoria)
+CROSS JOIN LATERAL posse_requisito(f.id, f.tenant_id, r.id) pr
+WHERE f.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND f.ativo = TRUE
+  AND (sqlc.narg('id_departamento')::int IS NULL OR f.IdDepartamento = sqlc.narg('id_departamento')::int)
+ORDER BY d.nome, d.id, f.nome, f.id, r.id;
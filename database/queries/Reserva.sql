@@ -0,0 +1,117 @@
+-- name: AddReserva :one
+INSERT INTO reserva_estoque (
+    tenant_id, IdEpi, IdTamanho, IdEntrada, quantidade, expira_em, IdSolicitacao, observacao, IdUsuario
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id;
+
+-- name: BuscarReservaParaConsumo :one
+-- id_funcionario vem da solicitação que gerou a reserva (nulo nas reservas avulsas).
+-- Reserva vencida já não segura saldo, então também não pode ser atendida
+SELECT r.id, r.IdEpi, r.IdTamanho, r.IdEntrada, r.quantidade, s.IdFuncionario as id_funcionario
+FROM reserva_estoque r
+LEFT JOIN solicitacao_epi s ON s.id = r.IdSolicitacao AND s.tenant_id = r.tenant_id
+WHERE r.id = $1 
+  AND r.tenant_id = $2 -- SEGURANÇA
+  AND r.consumida_em IS NULL
+  AND r.cancelada_em IS NULL
+  AND r.expira_em > NOW()
+FOR UPDATE OF r;
+
+-- name: AbaterReserva :execrows
+-- Entrega parcial: o que não foi entregue continua reservado
+UPDATE reserva_estoque
+SET quantidade = quantidade - $2
+WHERE id = $1 
+  AND tenant_id = $3 -- SEGURANÇA
+  AND consumida_em IS NULL
+  AND cancelada_em IS NULL
+  AND quantidade > $2;
+
+-- name: ConsumirReserva :execrows
+UPDATE reserva_estoque
+SET consumida_em = NOW(),
+    IdEntrega = $2
+WHERE id = $1 
+  AND tenant_id = $3 -- SEGURANÇA
+  AND consumida_em IS NULL
+  AND cancelada_em IS NULL;
+
+-- name: CancelarReserva :execrows
+UPDATE reserva_estoque
+SET cancelada_em = NOW(),
+    id_usuario_cancelamento = $2
+WHERE id = $1 
+  AND tenant_id = $3 -- SEGURANÇA
+  AND consumida_em IS NULL
+  AND cancelada_em IS NULL;
+
+-- name: QuantidadeReservadaSemLote :one
+-- Reservas que não apontam lote seguram saldo de qualquer lote do epi/tamanho
+SELECT COALESCE(SUM(quantidade), 0)::int
+FROM reserva_estoque
+WHERE tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND IdEpi = sqlc.arg('idepi')
+  AND IdTamanho = sqlc.arg('idtamanho')
+  AND IdEntrada IS NULL
+  AND consumida_em IS NULL
+  AND cancelada_em IS NULL
+  AND expira_em > NOW()
+  AND (sqlc.narg('id_reserva')::int IS NULL OR id <> sqlc.narg('id_reserva'));
+
+-- name: SaldoDisponivel :one
+SELECT 
+    COALESCE((
+        SELECT SUM(ee.quantidadeAtual)
+        FROM entrada_epi ee
+        WHERE ee.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+          AND ee.IdEpi = sqlc.arg('idepi')
+          AND ee.IdTamanho = sqlc.arg('idtamanho')
+          AND ee.data_validade >= CURRENT_DATE
+          AND ee.ativo = TRUE
+    ), 0)::int as quantidade_atual,
+    COALESCE((
+        SELECT SUM(r.quantidade)
+        FROM reserva_estoque r
+        WHERE r.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+          AND r.IdEpi = sqlc.arg('idepi')
+          AND r.IdTamanho = sqlc.arg('idtamanho')
+          AND r.consumida_em IS NULL
+          AND r.cancelada_em IS NULL
+          AND r.expira_em > NOW()
+    ), 0)::int as quantidade_reservada;
+
+-- name: ListarReservasSolicitacao :many
+SELECT id, IdEpi, IdTamanho
+FROM reserva_estoque
+WHERE IdSolicitacao = $1 
+  AND tenant_id = $2 -- SEGURANÇA
+  AND consumida_em IS NULL
+  AND cancelada_em IS NULL;
+
+-- name: ListarReservas :many
+SELECT 
+    r.id,
+    r.IdEpi,
+    e.nome as epi_nome,
+    r.IdTamanho,
+    t.tamanho as tamanho_nome,
+    r.IdEntrada,
+    r.quantidade,
+    r.expira_em,
+    r.IdSolicitacao,
+    r.observacao,
+    r.criada_em,
+    COUNT(*) OVER() as total_geral
+FROM reserva_estoque r
+INNER JOIN epi e ON r.IdEpi = e.id
+INNER JOIN tamanho t ON r.IdTamanho = t.id
+WHERE r.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND r.consumida_em IS NULL
+  AND r.cancelada_em IS NULL
+  AND r.expira_em > NOW()
+  AND (sqlc.narg('id_epi')::int IS NULL OR r.IdEpi = sqlc.narg('id_epi'))
+  AND (sqlc.narg('id_tamanho')::int IS NULL OR r.IdTamanho = sqlc.narg('id_tamanho'))
+  AND (sqlc.narg('id_solicitacao')::int IS NULL OR r.IdSolicitacao = sqlc.narg('id_solicitacao'))
+ORDER BY r.expira_em ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
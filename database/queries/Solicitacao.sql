@@ -0,0 +1,102 @@
+-- name: AddSolicitacao :one
+-- Só grava para funcionario ativo: sem linha devolvida o funcionario foi desligado ou não existe
+INSERT INTO solicitacao_epi (tenant_id, IdFuncionario, IdUsuarioSolicitante, justificativa, origem)
+SELECT $1, f.id, $3, $4, $5
+FROM funcionario f
+WHERE f.id = $2
+  AND f.tenant_id = $1 -- SEGURANÇA
+  AND f.ativo = TRUE
+RETURNING id;
+
+-- name: AddItemSolicitacao :exec
+INSERT INTO item_solicitacao (tenant_id, IdSolicitacao, IdEpi, IdTamanho, quantidade)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListarSolicitacoes :many
+SELECT 
+    s.id,
+    s.status,
+    s.origem,
+    s.justificativa,
+    s.motivo_rejeicao,
+    s.criada_em,
+    s.analisada_em,
+    s.atendida_em,
+    s.IdEntrega,
+    f.id as funcionario_id,
+    f.nome as funcionario_nome,
+    f.matricula,
+    s.IdUsuarioSolicitante,
+    us.nome as solicitante_nome,
+    s.IdUsuarioAnalise,
+    ua.nome as analista_nome,
+    COUNT(*) OVER() as total_geral
+FROM solicitacao_epi s
+INNER JOIN funcionario f ON s.IdFuncionario = f.id
+LEFT JOIN usuarios us ON s.IdUsuarioSolicitante = us.id -- NULL quando veio do portal
+LEFT JOIN usuarios ua ON s.IdUsuarioAnalise = ua.id
+WHERE s.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND (sqlc.narg('status')::text IS NULL OR s.status = sqlc.narg('status'))
+  AND (sqlc.narg('id_funcionario')::int IS NULL OR s.IdFuncionario = sqlc.narg('id_funcionario'))
+  AND (sqlc.narg('id_solicitacao')::int IS NULL OR s.id = sqlc.narg('id_solicitacao'))
+ORDER BY s.criada_em DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListarItensDasSolicitacoes :many
+SELECT 
+    i.id,
+    i.IdSolicitacao,
+    i.IdEpi,
+    e.nome as epi_nome,
+    i.IdTamanho,
+    t.tamanho as tamanho_nome,
+    i.quantidade
+FROM item_solicitacao i
+INNER JOIN epi e ON i.IdEpi = e.id
+INNER JOIN tamanho t ON i.IdTamanho = t.id
+WHERE i.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND i.IdSolicitacao = ANY(sqlc.arg('ids_solicitacao')::int[])
+ORDER BY i.id;
+
+-- name: BuscarSolicitacaoParaAnalise :one
+-- Trava a solicitação para dois técnicos não aprovarem/atenderem ao mesmo tempo
+SELECT id, IdFuncionario, status
+FROM solicitacao_epi
+WHERE id = $1 
+  AND tenant_id = $2 -- SEGURANÇA
+FOR UPDATE;
+
+-- name: ListarItensSolicitacao :many
+SELECT IdEpi, IdTamanho, quantidade
+FROM item_solicitacao
+WHERE IdSolicitacao = $1 
+  AND tenant_id = $2 -- SEGURANÇA
+ORDER BY id;
+
+-- name: AprovarSolicitacao :execrows
+UPDATE solicitacao_epi
+SET status = 'aprovada',
+    IdUsuarioAnalise = $2,
+    analisada_em = NOW()
+WHERE id = $1 
+  AND tenant_id = $3 -- SEGURANÇA
+  AND status = 'pendente';
+
+-- name: RejeitarSolicitacao :execrows
+UPDATE solicitacao_epi
+SET status = 'rejeitada',
+    IdUsuarioAnalise = $2,
+    motivo_rejeicao = $3,
+    analisada_em = NOW()
+WHERE id = $1 
+  AND tenant_id = $4 -- SEGURANÇA
+  AND status = 'pendente';
+
+-- name: AtenderSolicitacao :execrows
+UPDATE solicitacao_epi
+SET status = 'atendida',
+    IdEntrega = $2,
+    atendida_em = NOW()
+WHERE id = $1 
+  AND tenant_id = $3 -- SEGURANÇA
+  AND status = 'aprovada';
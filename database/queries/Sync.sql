@@ -0,0 +1,69 @@
+-- name: ReservarOperacaoSync :one
+-- Só devolve linha se o UUID é novo ou se a última tentativa deu conflito.
+-- Operação já aplicada não volta linha (pgx.ErrNoRows) e não é executada de novo
+INSERT INTO sync_operacao (tenant_id, uuid, tipo, status, IdUsuario)
+VALUES ($1, $2, $3, 'processando', $4)
+ON CONFLICT (tenant_id, uuid) DO UPDATE
+SET status = 'processando',
+    tipo = EXCLUDED.tipo,
+    erro = NULL,
+    IdUsuario = EXCLUDED.IdUsuario,
+    recebida_em = NOW()
+WHERE sync_operacao.status = 'conflito'
+RETURNING id;
+
+-- name: ConcluirOperacaoSync :exec
+UPDATE sync_operacao
+SET status = 'aplicada',
+    id_registro = $2
+WHERE id = $1 
+  AND tenant_id = $3; -- SEGURANÇA
+
+-- name: RegistrarConflitoSync :exec
+-- Roda fora da transação da operação (que já foi desfeita), então pode criar ou atualizar a linha
+INSERT INTO sync_operacao (tenant_id, uuid, tipo, status, erro, IdUsuario)
+VALUES ($1, $2, $3, 'conflito', $4, $5)
+ON CONFLICT (tenant_id, uuid) DO UPDATE
+SET erro = EXCLUDED.erro,
+    recebida_em = NOW()
+WHERE sync_operacao.status = 'conflito';
+
+-- name: BuscarOperacaoSync :one
+SELECT tipo, status, id_registro, erro
+FROM sync_operacao
+WHERE uuid = $1 
+  AND tenant_id = $2; -- SEGURANÇA
+
+-- name: CatalogoEpisSync :many
+SELECT id, nome, fabricante, CA, validade_CA, IdTipoProtecao
+FROM epi
+WHERE tenant_id = $1 -- SEGURANÇA
+  AND ativo = TRUE
+ORDER BY id;
+
+-- name: CatalogoFuncionariosSync :many
+SELECT id, nome, matricula, IdFuncao, IdDepartamento
+FROM funcionario
+WHERE tenant_id = $1 -- SEGURANÇA
+  AND ativo = TRUE
+ORDER BY id;
+
+-- name: CatalogoSaldoLotesSync :many
+-- Mesmo critério da baixa de estoque: lote ativo, dentro da validade e sem o que está reservado nele
+SELECT 
+    id, IdEpi, IdTamanho, lote, data_validade,
+    (quantidadeAtual - COALESCE((
+        SELECT SUM(r.quantidade)
+        FROM reserva_estoque r
+        WHERE r.IdEntrada = entrada_epi.id
+          AND r.tenant_id = entrada_epi.tenant_id
+          AND r.consumida_em IS NULL
+          AND r.cancelada_em IS NULL
+          AND r.expira_em > NOW()
+    ), 0))::int as quantidade_livre
+FROM entrada_epi
+WHERE tenant_id = $1 -- SEGURANÇA
+  AND quantidadeAtual > 0
+  AND data_validade >= CURRENT_DATE
+  AND ativo = TRUE
+ORDER BY IdEpi, IdTamanho, data_validade, id;
@@ -1,21 +1,23 @@
 -- name: AddProtecao :exec
-INSERT INTO tipo_protecao (tenant_id, nome) 
-VALUES ($1, $2);
+INSERT INTO tipo_protecao (tenant_id, nome, IdCategoria) 
+VALUES ($1, $2, $3);
 
 -- name: BuscarProtecao :one
-SELECT id, nome 
-FROM tipo_protecao 
-WHERE id = $1 
-  AND tenant_id = $2 -- SEGURANÇA
-  AND ativo = TRUE 
+SELECT tp.id, tp.nome, tp.IdCategoria, c.codigo as categoria_codigo, c.nome as categoria_nome
+FROM tipo_protecao tp
+LEFT JOIN categoria_protecao c ON tp.IdCategoria = c.id
+WHERE tp.id = $1 
+  AND tp.tenant_id = $2 -- SEGURANÇA
+  AND tp.ativo = TRUE 
 LIMIT 1;
 
 -- name: BuscarTodasProtecoes :many
-SELECT id, nome 
-FROM tipo_protecao 
-WHERE tenant_id = $1 -- SEGURANÇA: Lista apenas do cliente logado
-  AND ativo = TRUE
-ORDER BY nome ASC;
+SELECT tp.id, tp.nome, tp.IdCategoria, c.codigo as categoria_codigo, c.nome as categoria_nome
+FROM tipo_protecao tp
+LEFT JOIN categoria_protecao c ON tp.IdCategoria = c.id
+WHERE tp.tenant_id = $1 -- SEGURANÇA: Lista apenas do cliente logado
+  AND tp.ativo = TRUE
+ORDER BY tp.nome ASC;
 
 -- name: DeletarProtecao :execrows
 UPDATE tipo_protecao
@@ -30,4 +32,17 @@ UPDATE tipo_protecao
 SET nome = $2
 WHERE id = $1 
   AND tenant_id = $3 -- SEGURANÇA
-  AND ativo = TRUE;
\ No newline at end of file
+  AND ativo = TRUE;
+
+-- name: DefinirCategoriaProtecao :execrows
+UPDATE tipo_protecao
+SET IdCategoria = $2
+WHERE id = $1 
+  AND tenant_id = $3 -- SEGURANÇA
+  AND ativo = TRUE;
+
+-- name: ListarCategoriasProtecao :many
+-- Categorias da NR-6, iguais para todas as empresas
+SELECT id, codigo, nome
+FROM categoria_protecao
+ORDER BY codigo;
@@ -0,0 +1,91 @@
+-- name: AddTreinamento :one
+INSERT INTO treinamento (tenant_id, titulo, IdEpi, IdTipoProtecao, data_realizacao, instrutor, data_validade)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id;
+
+-- name: AddParticipanteTreinamento :execrows
+-- Só entra funcionario ativo do próprio tenant; 0 linhas = funcionario não encontrado
+INSERT INTO treinamento_participante (tenant_id, IdTreinamento, IdFuncionario)
+SELECT fn.tenant_id, sqlc.arg('id_treinamento'), fn.id
+FROM funcionario fn
+WHERE fn.id = sqlc.arg('id_funcionario')
+  AND fn.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND fn.ativo = TRUE;
+
+-- name: ListarTreinamentos :many
+-- id_epi traz também os treinamentos do tipo de proteção do epi, que valem para ele
+SELECT
+    t.id, t.titulo, t.data_realizacao, t.instrutor, t.data_validade,
+    e.id as epi_id, e.nome as epi_nome,
+    tp.id as tp_id, tp.nome as tp_nome,
+    (SELECT COUNT(*) FROM treinamento_participante p WHERE p.IdTreinamento = t.id) as total_participantes
+FROM treinamento t
+LEFT JOIN epi e ON t.IdEpi = e.id
+LEFT JOIN tipo_protecao tp ON t.IdTipoProtecao = tp.id
+WHERE t.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND t.ativo = TRUE
+  AND (sqlc.narg('id_funcionario')::int IS NULL OR EXISTS (
+        SELECT 1 FROM treinamento_participante p
+        WHERE p.IdTreinamento = t.id AND p.IdFuncionario = sqlc.narg('id_funcionario')::int
+  ))
+  AND (sqlc.narg('id_epi')::int IS NULL
+       OR t.IdEpi = sqlc.narg('id_epi')::int
+       OR t.IdTipoProtecao = (SELECT x.IdTipoProtecao FROM epi x WHERE x.id = sqlc.narg('id_epi')::int))
+ORDER BY t.data_realizacao DESC, t.id DESC;
+
+-- name: BuscarTreinamento :one
+SELECT
+    t.id, t.titulo, t.data_realizacao, t.instrutor, t.data_validade,
+    e.id as epi_id, e.nome as epi_nome,
+    tp.id as tp_id, tp.nome as tp_nome
+FROM treinamento t
+LEFT JOIN epi e ON t.IdEpi = e.id
+LEFT JOIN tipo_protecao tp ON t.IdTipoProtecao = tp.id
+WHERE t.id = $1
+  AND t.tenant_id = $2 -- SEGURANÇA
+  AND t.ativo = TRUE;
+
+-- name: ListarParticipantesTreinamento :many
+SELECT fn.id, fn.nome, fn.matricula
+FROM treinamento_participante p
+INNER JOIN funcionario fn ON p.IdFuncionario = fn.id
+WHERE p.IdTreinamento = $1
+  AND p.tenant_id = $2 -- SEGURANÇA
+ORDER BY fn.nome;
+
+-- name: DeletarTreinamento :execrows
+UPDATE treinamento
+SET ativo = FALSE,
+    deletado_em = NOW()
+WHERE id = $1
+  AND tenant_id = $2 -- SEGURANÇA
+  AND ativo = TRUE;
+
+-- name: BuscarRegraTreinamento :one
+SELECT regra_treinamento_entrega
+FROM empresas
+WHERE id = $1;
+
+-- name: DefinirRegraTreinamento :execrows
+UPDATE empresas
+SET regra_treinamento_entrega = $2
+WHERE id = $1;
+
+-- name: EpisSemTreinamentoValido :many
+-- Dos epis informados, os que o funcionario não tem treinamento válido na data (do próprio epi ou do tipo de proteção dele)
+SELECT e.id
+FROM epi e
+WHERE e.id = ANY(sqlc.arg('ids_epis')::int[])
+  AND e.tenant_id = sqlc.arg('tenant_id') -- SEGURANÇA
+  AND NOT EXISTS (
+      SELECT 1
+      FROM treinamento t
+      INNER JOIN treinamento_participante p ON p.IdTreinamento = t.id
+      WHERE p.IdFuncionario = sqlc.arg('id_funcionario')
+        AND t.tenant_id = e.tenant_id
+        AND t.ativo = TRUE
+        AND t.data_realizacao <= sqlc.arg('data')::date
+        AND (t.data_validade IS NULL OR t.data_validade >= sqlc.arg('data')::date)
+        AND (t.IdEpi = e.id OR t.IdTipoProtecao = e.IdTipoProtecao)
+  )
+ORDER BY e.id;
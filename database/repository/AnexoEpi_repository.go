@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AnexoEpiRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewAnexoEpiRepository(pool *pgxpool.Pool) *AnexoEpiRepository {
+
+	return &AnexoEpiRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+func (a *AnexoEpiRepository) Listar(ctx context.Context, arg ListarAnexosEpiParams) ([]ListarAnexosEpiRow, error) {
+
+	anexos, err := a.q.ListarAnexosEpi(ctx, arg)
+	if err != nil {
+
+		return []ListarAnexosEpiRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return anexos, nil
+}
+
+// Buscar devolve o erro cru para o service conseguir identificar o pgx.ErrNoRows
+func (a *AnexoEpiRepository) Buscar(ctx context.Context, arg BuscarAnexoEpiParams) (BuscarAnexoEpiRow, error) {
+
+	return a.q.BuscarAnexoEpi(ctx, arg)
+}
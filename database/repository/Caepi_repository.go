@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CaepiRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewCaepiRepository(pool *pgxpool.Pool) *CaepiRepository {
+
+	return &CaepiRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+// BuscarCa devolve o erro cru para o service conseguir identificar o pgx.ErrNoRows
+func (c *CaepiRepository) BuscarCa(ctx context.Context, ca string) (BuscarCatalogoCaRow, error) {
+
+	return c.q.BuscarCatalogoCa(ctx, ca)
+}
+
+func (c *CaepiRepository) ImportacoesPendentes(ctx context.Context) ([]int32, error) {
+
+	ids, err := c.q.ImportacoesCaepiPendentes(ctx)
+	if err != nil {
+
+		return []int32{}, helper.TraduzErroPostgres(err)
+	}
+
+	return ids, nil
+}
+
+func (c *CaepiRepository) ListarAlertas(ctx context.Context, arg ListarAlertasCaParams) ([]ListarAlertasCaRow, error) {
+
+	alertas, err := c.q.ListarAlertasCa(ctx, arg)
+	if err != nil {
+
+		return []ListarAlertasCaRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return alertas, nil
+}
+
+func (c *CaepiRepository) ResolverAlerta(ctx context.Context, arg ResolverAlertaCaParams) (int64, error) {
+
+	linhas, err := c.q.ResolverAlertaCa(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhas, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ConsumoRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewConsumoRepository(pool *pgxpool.Pool) *ConsumoRepository {
+
+	return &ConsumoRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+func (c *ConsumoRepository) Relatorio(ctx context.Context, arg RelatorioConsumoParams) ([]RelatorioConsumoRow, error) {
+
+	linhas, err := c.q.RelatorioConsumo(ctx, arg)
+	if err != nil {
+
+		return []RelatorioConsumoRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return linhas, nil
+}
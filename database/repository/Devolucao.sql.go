@@ -77,8 +77,9 @@ func (q *Queries) AddEntregaVinculada(ctx context.Context, arg AddEntregaVincula
 const addTrocaEpi = `-- name: AddTrocaEpi :one
 INSERT INTO devolucao (
     tenant_id, IdFuncionario, IdEpi, IdMotivo, data_devolucao, IdTamanho, 
-    quantidadeAdevolver, IdEpiNovo, IdTamanhoNovo, quantidadeNova, assinatura_digital, id_usuario_cancelamento, token_validacao
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+    quantidadeAdevolver, IdEpiNovo, IdTamanhoNovo, quantidadeNova, assinatura_digital, id_usuario_cancelamento, token_validacao,
+    destino, IdEntradaEstoque
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 RETURNING id
 `
 
@@ -96,6 +97,8 @@ type AddTrocaEpiParams struct {
 	AssinaturaDigital     string
 	IDUsuarioCancelamento pgtype.Int4
 	TokenValidacao        pgtype.Text
+	Destino               pgtype.Text
+	Identradaestoque      pgtype.Int4
 }
 
 func (q *Queries) AddTrocaEpi(ctx context.Context, arg AddTrocaEpiParams) (int32, error) {
@@ -113,6 +116,8 @@ func (q *Queries) AddTrocaEpi(ctx context.Context, arg AddTrocaEpiParams) (int32
 		arg.AssinaturaDigital,
 		arg.IDUsuarioCancelamento,
 		arg.TokenValidacao,
+		arg.Destino,
+		arg.Identradaestoque,
 	)
 	var id int32
 	err := row.Scan(&id)
@@ -127,7 +132,8 @@ SET cancelada_em = NOW(),
 WHERE id = $1 
   AND tenant_id = $3 -- SEGURANÇA: Só cancela se pertencer à empresa correta
   AND cancelada_em IS NULL
-RETURNING id
+  AND IdDocumento IS NULL -- item de documento só sai cancelando o documento inteiro
+RETURNING id, quantidadeAdevolver, IdEntradaEstoque
 `
 
 type CancelarDevolucaoParams struct {
@@ -136,18 +142,24 @@ type CancelarDevolucaoParams struct {
 	TenantID                       int32
 }
 
-func (q *Queries) CancelarDevolucao(ctx context.Context, arg CancelarDevolucaoParams) (int32, error) {
+type CancelarDevolucaoRow struct {
+	ID                  int32
+	Quantidadeadevolver int32
+	Identradaestoque    pgtype.Int4
+}
+
+func (q *Queries) CancelarDevolucao(ctx context.Context, arg CancelarDevolucaoParams) (CancelarDevolucaoRow, error) {
 	row := q.db.QueryRow(ctx, cancelarDevolucao, arg.ID, arg.IDUsuarioDevolucaoCancelamento, arg.TenantID)
-	var id int32
-	err := row.Scan(&id)
-	return id, err
+	var i CancelarDevolucaoRow
+	err := row.Scan(&i.ID, &i.Quantidadeadevolver, &i.Identradaestoque)
+	return i, err
 }
 
 const listarDevolucoes = `-- name: ListarDevolucoes :many
 SELECT 
     d.id, d.IdFuncionario, f.nome as func_nome, f.matricula,
-    f.IdDepartamento, dd.nome as dep_nome,
-    f.IdFuncao, ff.nome as funcao_nome,
+    dd.id as IdDepartamento, dd.nome as dep_nome,
+    ff.id as IdFuncao, ff.nome as funcao_nome,
     d.IdEpi, e.nome as epi_antigo_nome, e.fabricante as epi_antigo_fab, e.CA as epi_antigo_ca,
     d.IdTamanho as tam_antigo_id, t.tamanho as tam_antigo_nome, e.descricao as desc_antiga,
     e.validade_CA as validade_ca_antiga, e.IdTipoProtecao as idprotecaoAntigo, tp.nome as tipo_protecao_nomeAntigo,
@@ -161,8 +173,12 @@ SELECT
 FROM devolucao d
 INNER JOIN epi e ON d.IdEpi = e.id
 INNER JOIN funcionario f ON d.IdFuncionario = f.id  
-INNER JOIN departamento dd ON f.IdDepartamento = dd.id
-INNER JOIN funcao ff ON f.IdFuncao = ff.id
+-- departamento/função de quando a devolução foi feita, não o cargo atual
+LEFT JOIN funcionario_cargo_historico h ON h.IdFuncionario = f.id
+    AND h.data_inicio <= d.data_devolucao
+    AND (h.data_fim IS NULL OR d.data_devolucao < h.data_fim)
+INNER JOIN departamento dd ON COALESCE(h.IdDepartamento, f.IdDepartamento) = dd.id
+INNER JOIN funcao ff ON COALESCE(h.IdFuncao, f.IdFuncao) = ff.id
 INNER JOIN tamanho t ON d.IdTamanho = t.id
 INNER JOIN motivo_devolucao m ON d.IdMotivo = m.id
 INNER JOIN tipo_protecao tp ON e.IdTipoProtecao = tp.id
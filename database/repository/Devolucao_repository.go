@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -57,14 +60,18 @@ func (d *DevolucaoRepository) EntregaVinculada(ctx context.Context, qtx *Queries
 	return identrega, nil
 }
 
-func (d *DevolucaoRepository) Cancelar(ctx context.Context,qtx *Queries , arg CancelarDevolucaoParams) (int32, error) {
+// Cancelar devolve o pgx.ErrNoRows cru: o service precisa dele para responder "não encontrada"
+func (d *DevolucaoRepository) Cancelar(ctx context.Context,qtx *Queries , arg CancelarDevolucaoParams) (CancelarDevolucaoRow, error) {
 
-	id, err:= qtx.CancelarDevolucao(ctx, arg)
+	cancelada, err:= qtx.CancelarDevolucao(ctx, arg)
 	if err != nil {
-		return 0, helper.TraduzErroPostgres(err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return CancelarDevolucaoRow{}, err
+		}
+		return CancelarDevolucaoRow{}, helper.TraduzErroPostgres(err)
 	}
 
-	return  id, nil
+	return  cancelada, nil
 }
 
 func (d *DevolucaoRepository) Listar(ctx context.Context,args ListarDevolucoesParams) ([]ListarDevolucoesRow, error){
@@ -76,4 +83,71 @@ func (d *DevolucaoRepository) Listar(ctx context.Context,args ListarDevolucoesPa
 	}
 
 	return devolucoes, nil
-}
\ No newline at end of file
+}
+func (d *DevolucaoRepository) AdicionarDocumento(ctx context.Context, qtx *Queries, arg AddDevolucaoDocumentoParams) (int32, error) {
+
+	id, err := qtx.AddDevolucaoDocumento(ctx, arg)
+	if err != nil {
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return id, nil
+}
+
+func (d *DevolucaoRepository) VincularAoDocumento(ctx context.Context, qtx *Queries, arg VincularDevolucaoDocumentoParams) error {
+
+	if err := qtx.VincularDevolucaoDocumento(ctx, arg); err != nil {
+		return helper.TraduzErroPostgres(err)
+	}
+
+	return nil
+}
+
+func (d *DevolucaoRepository) VincularEntregaTroca(ctx context.Context, qtx *Queries, arg VincularEntregaTrocaDocumentoParams) error {
+
+	if err := qtx.VincularEntregaTrocaDocumento(ctx, arg); err != nil {
+		return helper.TraduzErroPostgres(err)
+	}
+
+	return nil
+}
+
+// BuscarDocumento devolve o erro cru: o service precisa enxergar o pgx.ErrNoRows
+func (d *DevolucaoRepository) BuscarDocumento(ctx context.Context, arg BuscarDevolucaoDocumentoParams) (BuscarDevolucaoDocumentoRow, error) {
+
+	return d.q.BuscarDevolucaoDocumento(ctx, arg)
+}
+
+func (d *DevolucaoRepository) ItensDocumento(ctx context.Context, arg ListarItensDevolucaoDocumentoParams) ([]ListarItensDevolucaoDocumentoRow, error) {
+
+	itens, err := d.q.ListarItensDevolucaoDocumento(ctx, arg)
+	if err != nil {
+		return []ListarItensDevolucaoDocumentoRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return itens, nil
+}
+
+// CancelarDocumento devolve o pgx.ErrNoRows cru: o service precisa dele para responder "não encontrado"
+func (d *DevolucaoRepository) CancelarDocumento(ctx context.Context, qtx *Queries, arg CancelarDevolucaoDocumentoParams) (pgtype.Int4, error) {
+
+	idEntregaTroca, err := qtx.CancelarDevolucaoDocumento(ctx, arg)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgtype.Int4{}, err
+		}
+		return pgtype.Int4{}, helper.TraduzErroPostgres(err)
+	}
+
+	return idEntregaTroca, nil
+}
+
+func (d *DevolucaoRepository) CancelarItensDocumento(ctx context.Context, qtx *Queries, arg CancelarDevolucoesDoDocumentoParams) ([]CancelarDevolucoesDoDocumentoRow, error) {
+
+	itens, err := qtx.CancelarDevolucoesDoDocumento(ctx, arg)
+	if err != nil {
+		return nil, helper.TraduzErroPostgres(err)
+	}
+
+	return itens, nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ESocialRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewESocialRepository(pool *pgxpool.Pool) *ESocialRepository {
+
+	return &ESocialRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+func (e *ESocialRepository) DefinirFuncao(ctx context.Context, arg DefinirFuncaoEsocialParams) (int64, error) {
+
+	linhas, err := e.q.DefinirFuncaoEsocial(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhas, nil
+}
+
+// Empregador devolve o erro cru para o service conseguir identificar o pgx.ErrNoRows
+func (e *ESocialRepository) Empregador(ctx context.Context, tenantId int32) (BuscarEmpregadorEsocialRow, error) {
+
+	return e.q.BuscarEmpregadorEsocial(ctx, tenantId)
+}
+
+func (e *ESocialRepository) ExposicoesS2240(ctx context.Context, arg ListarExposicoesS2240Params) ([]ListarExposicoesS2240Row, error) {
+
+	exposicoes, err := e.q.ListarExposicoesS2240(ctx, arg)
+	if err != nil {
+
+		return []ListarExposicoesS2240Row{}, helper.TraduzErroPostgres(err)
+	}
+
+	return exposicoes, nil
+}
@@ -14,20 +14,21 @@ import (
 const addEntregaEpi = `-- name: AddEntregaEpi :one
 INSERT INTO entrega_epi (
     tenant_id, -- Novo campo
-    IdFuncionario, data_entrega, assinatura, IdTroca, token_validacao, id_usuario_entrega
+    IdFuncionario, data_entrega, assinatura, IdTroca, token_validacao, id_usuario_entrega, pendencia_treinamento
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING id
 `
 
 type AddEntregaEpiParams struct {
-	TenantID         int32
-	Idfuncionario    int32
-	DataEntrega      pgtype.Date
-	Assinatura       string
-	Idtroca          pgtype.Int4
-	TokenValidacao   pgtype.Text
-	IDUsuarioEntrega pgtype.Int4
+	TenantID             int32
+	Idfuncionario        int32
+	DataEntrega          pgtype.Date
+	Assinatura           string
+	Idtroca              pgtype.Int4
+	TokenValidacao       pgtype.Text
+	IDUsuarioEntrega     pgtype.Int4
+	PendenciaTreinamento bool
 }
 
 func (q *Queries) AddEntregaEpi(ctx context.Context, arg AddEntregaEpiParams) (int32, error) {
@@ -39,6 +40,7 @@ func (q *Queries) AddEntregaEpi(ctx context.Context, arg AddEntregaEpiParams) (i
 		arg.Idtroca,
 		arg.TokenValidacao,
 		arg.IDUsuarioEntrega,
+		arg.PendenciaTreinamento,
 	)
 	var id int32
 	err := row.Scan(&id)
@@ -241,7 +243,7 @@ func (q *Queries) CancelarEntrega(ctx context.Context, arg CancelarEntregaParams
 
 const listarEntregas = `-- name: ListarEntregas :many
 SELECT 
-    ee.id as entrega_id, ee.data_entrega, ee.assinatura, ee.token_validacao, ee.id_usuario_entrega,   
+    ee.id as entrega_id, ee.data_entrega, ee.assinatura, ee.token_validacao, ee.id_usuario_entrega, ee.pendencia_treinamento,
     f.id as func_id, f.nome as func_nome, f.matricula,
     d.id as dep_id, d.nome as dep_nome,
     ff.id as funcao_id, ff.nome as funcao_nome,
@@ -252,8 +254,12 @@ SELECT
     COUNT(*) OVER() as total_geral
 FROM entrega_epi ee
 INNER JOIN funcionario f ON ee.IdFuncionario = f.id
-INNER JOIN departamento d ON f.IdDepartamento = d.id
-INNER JOIN funcao ff ON f.IdFuncao = ff.id
+-- departamento/função de quando a entrega foi feita, não o cargo atual
+LEFT JOIN funcionario_cargo_historico h ON h.IdFuncionario = f.id
+    AND h.data_inicio <= ee.data_entrega
+    AND (h.data_fim IS NULL OR ee.data_entrega < h.data_fim)
+INNER JOIN departamento d ON COALESCE(h.IdDepartamento, f.IdDepartamento) = d.id
+INNER JOIN funcao ff ON COALESCE(h.IdFuncao, f.IdFuncao) = ff.id
 INNER JOIN epis_entregues i ON i.IdEntrega = ee.id
 INNER JOIN epi e ON i.IdEpi = e.id
 INNER JOIN tipo_protecao tp ON e.IdTipoProtecao = tp.id
@@ -284,30 +290,31 @@ type ListarEntregasParams struct {
 }
 
 type ListarEntregasRow struct {
-	EntregaID        int32
-	DataEntrega      pgtype.Date
-	Assinatura       string
-	TokenValidacao   pgtype.Text
-	IDUsuarioEntrega pgtype.Int4
-	FuncID           int32
-	FuncNome         string
-	Matricula        string
-	DepID            int32
-	DepNome          string
-	FuncaoID         int32
-	FuncaoNome       string
-	EpiID            int32
-	EpiNome          string
-	Fabricante       string
-	Ca               string
-	EpiDesc          string
-	ValidadeCa       pgtype.Date
-	TpID             int32
-	TpNome           string
-	TamID            int32
-	TamNome          string
-	Quantidade       int32
-	TotalGeral       int64
+	EntregaID            int32
+	DataEntrega          pgtype.Date
+	Assinatura           string
+	TokenValidacao       pgtype.Text
+	IDUsuarioEntrega     pgtype.Int4
+	PendenciaTreinamento bool
+	FuncID               int32
+	FuncNome             string
+	Matricula            string
+	DepID                int32
+	DepNome              string
+	FuncaoID             int32
+	FuncaoNome           string
+	EpiID                int32
+	EpiNome              string
+	Fabricante           string
+	Ca                   string
+	EpiDesc              string
+	ValidadeCa           pgtype.Date
+	TpID                 int32
+	TpNome               string
+	TamID                int32
+	TamNome              string
+	Quantidade           int32
+	TotalGeral           int64
 }
 
 func (q *Queries) ListarEntregas(ctx context.Context, arg ListarEntregasParams) ([]ListarEntregasRow, error) {
@@ -334,6 +341,7 @@ func (q *Queries) ListarEntregas(ctx context.Context, arg ListarEntregasParams)
 			&i.Assinatura,
 			&i.TokenValidacao,
 			&i.IDUsuarioEntrega,
+			&i.PendenciaTreinamento,
 			&i.FuncID,
 			&i.FuncNome,
 			&i.Matricula,
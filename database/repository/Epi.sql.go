@@ -64,9 +64,11 @@ const buscarEpi = `-- name: BuscarEpi :one
 SELECT 
     e.id, e.nome, e.fabricante, e.CA, e.descricao,
     e.validade_CA, e.alerta_minimo, e.IdTipoProtecao, 
-    tp.nome as tipo_protecao_nome
+    tp.nome as tipo_protecao_nome,
+    a.id as foto_id, a.nome_arquivo as foto_nome, a.mime as foto_mime
 FROM epi e
 INNER JOIN tipo_protecao tp ON e.IdTipoProtecao = tp.id
+LEFT JOIN anexo_epi a ON a.IdEpi = e.id AND a.principal = TRUE
 WHERE e.id = $1 
   AND e.tenant_id = $2 -- SEGURANÇA
   AND e.ativo = TRUE
@@ -87,6 +89,9 @@ type BuscarEpiRow struct {
 	AlertaMinimo     int32
 	Idtipoprotecao   int32
 	TipoProtecaoNome string
+	FotoID           pgtype.Int4
+	FotoNome         pgtype.Text
+	FotoMime         pgtype.Text
 }
 
 func (q *Queries) BuscarEpi(ctx context.Context, arg BuscarEpiParams) (BuscarEpiRow, error) {
@@ -102,6 +107,9 @@ func (q *Queries) BuscarEpi(ctx context.Context, arg BuscarEpiParams) (BuscarEpi
 		&i.AlertaMinimo,
 		&i.Idtipoprotecao,
 		&i.TipoProtecaoNome,
+		&i.FotoID,
+		&i.FotoNome,
+		&i.FotoMime,
 	)
 	return i, err
 }
@@ -150,9 +158,11 @@ SELECT
     e.id, e.nome, e.fabricante, e.CA, e.descricao,
     e.validade_CA, e.alerta_minimo, e.IdTipoProtecao, 
     tp.nome as tipo_protecao_nome,
+    a.id as foto_id, a.nome_arquivo as foto_nome, a.mime as foto_mime,
     COUNT(*) OVER() as total_geral
 FROM epi e
 INNER JOIN tipo_protecao tp ON e.IdTipoProtecao = tp.id
+LEFT JOIN anexo_epi a ON a.IdEpi = e.id AND a.principal = TRUE
 WHERE e.tenant_id = $3 -- SEGURANÇA: Filtro de Tenant
   AND e.ativo = TRUE
 ORDER BY e.id
@@ -175,6 +185,9 @@ type BuscarTodosEpisPaginadoRow struct {
 	AlertaMinimo     int32
 	Idtipoprotecao   int32
 	TipoProtecaoNome string
+	FotoID           pgtype.Int4
+	FotoNome         pgtype.Text
+	FotoMime         pgtype.Text
 	TotalGeral       int64
 }
 
@@ -197,6 +210,9 @@ func (q *Queries) BuscarTodosEpisPaginado(ctx context.Context, arg BuscarTodosEp
 			&i.AlertaMinimo,
 			&i.Idtipoprotecao,
 			&i.TipoProtecaoNome,
+			&i.FotoID,
+			&i.FotoNome,
+			&i.FotoMime,
 			&i.TotalGeral,
 		); err != nil {
 			return nil, err
@@ -34,7 +34,7 @@ func (q *Queries) AbaterEstoqueLote(ctx context.Context, arg AbaterEstoqueLotePa
 	return result.RowsAffected(), nil
 }
 
-const devolverItemAoEstoque = `-- name: DevolverItemAoEstoque :exec
+const devolverItemAoEstoque = `-- name: DevolverItemAoEstoque :one
 UPDATE entrada_epi
 SET quantidadeAtual = entrada_epi.quantidadeAtual + $4 -- Quantidade é o $4 agora
 WHERE id = (
@@ -47,7 +47,8 @@ WHERE id = (
     ORDER BY ee.data_entrada DESC
     LIMIT 1
 )
-AND tenant_id = $1
+AND tenant_id = $1 -- SEGURANÇA NO UPDATE
+RETURNING id
 `
 
 type DevolverItemAoEstoqueParams struct {
@@ -57,22 +58,39 @@ type DevolverItemAoEstoqueParams struct {
 	Quantidadeatual int32
 }
 
-func (q *Queries) DevolverItemAoEstoque(ctx context.Context, arg DevolverItemAoEstoqueParams) error {
-	_, err := q.db.Exec(ctx, devolverItemAoEstoque,
+// Devolve o id do lote que recebeu o item; sem lote ativo do epi/tamanho não volta linha
+func (q *Queries) DevolverItemAoEstoque(ctx context.Context, arg DevolverItemAoEstoqueParams) (int32, error) {
+	row := q.db.QueryRow(ctx, devolverItemAoEstoque,
 		arg.TenantID,
 		arg.Idepi,
 		arg.Idtamanho,
 		arg.Quantidadeatual,
 	)
-	return err
+	var id int32
+	err := row.Scan(&id)
+	return id, err
 }
 
 const listarLotesParaConsumo = `-- name: ListarLotesParaConsumo :many
-SELECT id, quantidadeAtual, data_validade, valor_unitario 
+SELECT 
+    id, 
+    quantidadeAtual, 
+    data_validade, 
+    valor_unitario,
+    (quantidadeAtual - COALESCE((
+        SELECT SUM(r.quantidade)
+        FROM reserva_estoque r
+        WHERE r.IdEntrada = entrada_epi.id
+          AND r.tenant_id = entrada_epi.tenant_id
+          AND r.consumida_em IS NULL
+          AND r.cancelada_em IS NULL
+          AND r.expira_em > NOW()
+          AND ($1::int IS NULL OR r.id <> $1)
+    ), 0))::int as quantidade_livre
 FROM entrada_epi 
-WHERE tenant_id = $1 -- SEGURANÇA: Só busca lotes da empresa logada
-  AND IdEpi = $2 
-  AND IdTamanho = $3 
+WHERE tenant_id = $2 -- SEGURANÇA: Só busca lotes da empresa logada
+  AND IdEpi = $3 
+  AND IdTamanho = $4 
   AND quantidadeAtual > 0 
   AND data_validade >= CURRENT_DATE
   AND ativo = TRUE
@@ -81,6 +99,7 @@ FOR UPDATE
 `
 
 type ListarLotesParaConsumoParams struct {
+	IDReserva pgtype.Int4
 	TenantID  int32
 	Idepi     int32
 	Idtamanho int32
@@ -91,11 +110,18 @@ type ListarLotesParaConsumoRow struct {
 	Quantidadeatual int32
 	DataValidade    pgtype.Date
 	ValorUnitario   pgtype.Numeric
+	QuantidadeLivre int32
 }
 
 // O PostgreSQL usa FOR UPDATE para travar apenas as linhas desse cliente específico.
+// quantidade_livre desconta as reservas ativas presas ao lote, menos a reserva que a entrega está atendendo
 func (q *Queries) ListarLotesParaConsumo(ctx context.Context, arg ListarLotesParaConsumoParams) ([]ListarLotesParaConsumoRow, error) {
-	rows, err := q.db.Query(ctx, listarLotesParaConsumo, arg.TenantID, arg.Idepi, arg.Idtamanho)
+	rows, err := q.db.Query(ctx, listarLotesParaConsumo,
+		arg.IDReserva,
+		arg.TenantID,
+		arg.Idepi,
+		arg.Idtamanho,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -108,6 +134,7 @@ func (q *Queries) ListarLotesParaConsumo(ctx context.Context, arg ListarLotesPar
 			&i.Quantidadeatual,
 			&i.DataValidade,
 			&i.ValorUnitario,
+			&i.QuantidadeLivre,
 		); err != nil {
 			return nil, err
 		}
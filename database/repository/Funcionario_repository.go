@@ -40,9 +40,9 @@ func(f *FuncionarioRepository) ListarFuncionario(ctx context.Context, arg BuscaF
 	return f.q.BuscaFuncionario(ctx, arg)
 }
 
-func (f *FuncionarioRepository) ListarFuncionarios(ctx context.Context, tenantId int32)([]BuscarTodosFuncionariosRow, error) {
+func (f *FuncionarioRepository) ListarFuncionarios(ctx context.Context, arg BuscarTodosFuncionariosParams)([]BuscarTodosFuncionariosRow, error) {
 
-	funcs, err:= f.q.BuscarTodosFuncionarios(ctx, tenantId)
+	funcs, err:= f.q.BuscarTodosFuncionarios(ctx, arg)
 	if err != nil {
 
 		return []BuscarTodosFuncionariosRow{}, helper.TraduzErroPostgres(err)
@@ -51,6 +51,28 @@ func (f *FuncionarioRepository) ListarFuncionarios(ctx context.Context, tenantId
 	return  funcs, nil
 }
 
+func (f *FuncionarioRepository) TotalFuncionarios(ctx context.Context, arg ContarFuncionariosFiltradosParams) (int64, error) {
+
+	total, err := f.q.ContarFuncionariosFiltrados(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return total, nil
+}
+
+func (f *FuncionarioRepository) HistoricoCargos(ctx context.Context, arg ListarHistoricoCargoParams) ([]ListarHistoricoCargoRow, error) {
+
+	historico, err := f.q.ListarHistoricoCargo(ctx, arg)
+	if err != nil {
+
+		return []ListarHistoricoCargoRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return historico, nil
+}
+
 func (f *FuncionarioRepository) CancelarFuncionario(ctx context.Context, arg DeletarFuncionarioParams) (int64, error){
 
 	linhasAfetadas,err:= f.q.DeletarFuncionario(ctx, arg)
@@ -7,11 +7,20 @@ package repository
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addFuncionario = `-- name: AddFuncionario :exec
-INSERT INTO funcionario (tenant_id, nome, matricula, IdDepartamento, IdFuncao) 
-VALUES ($1, $2, $3, $4, $5)
+WITH novo AS (
+    INSERT INTO funcionario (tenant_id, nome, matricula, IdDepartamento, IdFuncao, data_admissao, cpf, data_nascimento, email, telefone) 
+    VALUES ($1, $2, $3, $4, $5, COALESCE($6::date, CURRENT_DATE),
+            $7, $8, $9, $10)
+    RETURNING id, tenant_id, IdDepartamento, IdFuncao, data_admissao
+)
+INSERT INTO funcionario_cargo_historico (tenant_id, IdFuncionario, IdDepartamento, IdFuncao, data_inicio)
+SELECT tenant_id, id, IdDepartamento, IdFuncao, data_admissao
+FROM novo
 `
 
 type AddFuncionarioParams struct {
@@ -20,8 +29,14 @@ type AddFuncionarioParams struct {
 	Matricula      string
 	Iddepartamento int32
 	Idfuncao       int32
+	DataAdmissao   pgtype.Date
+	Cpf            pgtype.Text
+	DataNascimento pgtype.Date
+	Email          pgtype.Text
+	Telefone       pgtype.Text
 }
 
+// Já abre o primeiro cargo no histórico, valendo desde a admissão
 func (q *Queries) AddFuncionario(ctx context.Context, arg AddFuncionarioParams) error {
 	_, err := q.db.Exec(ctx, addFuncionario,
 		arg.TenantID,
@@ -29,6 +44,11 @@ func (q *Queries) AddFuncionario(ctx context.Context, arg AddFuncionarioParams)
 		arg.Matricula,
 		arg.Iddepartamento,
 		arg.Idfuncao,
+		arg.DataAdmissao,
+		arg.Cpf,
+		arg.DataNascimento,
+		arg.Email,
+		arg.Telefone,
 	)
 	return err
 }
@@ -41,13 +61,17 @@ SELECT
     fn.IdDepartamento, 
     d.nome as departamento_nome,
     fn.IdFuncao, 
-    f.nome as funcao_nome
+    f.nome as funcao_nome,
+    fn.ativo,
+    fn.data_admissao,
+    fn.data_desligamento,
+    fn.motivo_desligamento,
+    fn.cpf
 FROM funcionario fn
 INNER JOIN departamento d ON fn.IdDepartamento = d.id
 INNER JOIN funcao f ON fn.IdFuncao = f.id
 WHERE fn.matricula = $1 
-  AND fn.tenant_id = $2 -- IMPORTANTE: Matrícula só é única dentro do tenant
-  AND fn.ativo = TRUE
+  AND fn.tenant_id = $2
 `
 
 type BuscaFuncionarioParams struct {
@@ -56,15 +80,21 @@ type BuscaFuncionarioParams struct {
 }
 
 type BuscaFuncionarioRow struct {
-	ID               int32
-	Nome             string
-	Matricula        string
-	Iddepartamento   int32
-	DepartamentoNome string
-	Idfuncao         int32
-	FuncaoNome       string
+	ID                 int32
+	Nome               string
+	Matricula          string
+	Iddepartamento     int32
+	DepartamentoNome   string
+	Idfuncao           int32
+	FuncaoNome         string
+	Ativo              bool
+	DataAdmissao       pgtype.Date
+	DataDesligamento   pgtype.Date
+	MotivoDesligamento pgtype.Text
+	Cpf                pgtype.Text
 }
 
+// Traz também os desligados: o histórico do funcionario continua consultável pela matrícula
 func (q *Queries) BuscaFuncionario(ctx context.Context, arg BuscaFuncionarioParams) (BuscaFuncionarioRow, error) {
 	row := q.db.QueryRow(ctx, buscaFuncionario, arg.Matricula, arg.TenantID)
 	var i BuscaFuncionarioRow
@@ -76,6 +106,11 @@ func (q *Queries) BuscaFuncionario(ctx context.Context, arg BuscaFuncionarioPara
 		&i.DepartamentoNome,
 		&i.Idfuncao,
 		&i.FuncaoNome,
+		&i.Ativo,
+		&i.DataAdmissao,
+		&i.DataDesligamento,
+		&i.MotivoDesligamento,
+		&i.Cpf,
 	)
 	return i, err
 }
@@ -127,6 +162,53 @@ func (q *Queries) BuscaFuncionarioPorId(ctx context.Context, arg BuscaFuncionari
 	return i, err
 }
 
+const buscarDadosPessoaisFuncionario = `-- name: BuscarDadosPessoaisFuncionario :one
+SELECT
+    fn.id,
+    fn.nome,
+    fn.matricula,
+    fn.cpf,
+    fn.data_nascimento,
+    fn.data_admissao,
+    fn.email,
+    fn.telefone
+FROM funcionario fn
+WHERE fn.id = $1
+  AND fn.tenant_id = $2
+`
+
+type BuscarDadosPessoaisFuncionarioParams struct {
+	ID       int32
+	TenantID int32
+}
+
+type BuscarDadosPessoaisFuncionarioRow struct {
+	ID             int32
+	Nome           string
+	Matricula      string
+	Cpf            pgtype.Text
+	DataNascimento pgtype.Date
+	DataAdmissao   pgtype.Date
+	Email          pgtype.Text
+	Telefone       pgtype.Text
+}
+
+func (q *Queries) BuscarDadosPessoaisFuncionario(ctx context.Context, arg BuscarDadosPessoaisFuncionarioParams) (BuscarDadosPessoaisFuncionarioRow, error) {
+	row := q.db.QueryRow(ctx, buscarDadosPessoaisFuncionario, arg.ID, arg.TenantID)
+	var i BuscarDadosPessoaisFuncionarioRow
+	err := row.Scan(
+		&i.ID,
+		&i.Nome,
+		&i.Matricula,
+		&i.Cpf,
+		&i.DataNascimento,
+		&i.DataAdmissao,
+		&i.Email,
+		&i.Telefone,
+	)
+	return i, err
+}
+
 const buscarTodosFuncionarios = `-- name: BuscarTodosFuncionarios :many
 SELECT 
     fn.id, 
@@ -135,26 +217,79 @@ SELECT
     fn.IdDepartamento, 
     d.nome as departamento_nome,
     fn.IdFuncao, 
-    f.nome as funcao_nome
+    f.nome as funcao_nome,
+    fn.ativo,
+    fn.data_admissao,
+    fn.data_desligamento,
+    fn.motivo_desligamento,
+    fn.cpf
 FROM funcionario fn
 INNER JOIN departamento d ON fn.IdDepartamento = d.id
 INNER JOIN funcao f ON fn.IdFuncao = f.id
 WHERE fn.tenant_id = $1 -- SEGURANÇA: Só lista funcionários da empresa atual
-  AND fn.ativo = TRUE
+  AND (
+    ($2::text = 'ativos' AND fn.ativo = TRUE) OR
+    ($2::text = 'desligados' AND fn.ativo = FALSE) OR
+    $2::text = 'todos'
+  )
+  AND ($3::int IS NULL OR fn.IdDepartamento = $3::int)
+  AND ($4::int IS NULL OR fn.IdFuncao = $4::int)
+  AND ($5::text IS NULL OR unaccent(fn.nome) ILIKE '%' || unaccent($5::text) || '%')
+  AND ($6::text IS NULL OR fn.matricula ILIKE '%' || $6::text || '%')
+ORDER BY
+    CASE WHEN $7::text = 'nome' AND NOT $8::boolean THEN fn.nome END ASC,
+    CASE WHEN $7::text = 'nome' AND $8::boolean THEN fn.nome END DESC,
+    CASE WHEN $7::text = 'matricula' AND NOT $8::boolean THEN fn.matricula END ASC,
+    CASE WHEN $7::text = 'matricula' AND $8::boolean THEN fn.matricula END DESC,
+    CASE WHEN $7::text = 'departamento' AND NOT $8::boolean THEN d.nome END ASC,
+    CASE WHEN $7::text = 'departamento' AND $8::boolean THEN d.nome END DESC,
+    CASE WHEN $7::text = 'funcao' AND NOT $8::boolean THEN f.nome END ASC,
+    CASE WHEN $7::text = 'funcao' AND $8::boolean THEN f.nome END DESC,
+    fn.id
+LIMIT $9 OFFSET $10
 `
 
+type BuscarTodosFuncionariosParams struct {
+	TenantID       int32
+	Situacao       string
+	IDDepartamento pgtype.Int4
+	IDFuncao       pgtype.Int4
+	Nome           pgtype.Text
+	Matricula      pgtype.Text
+	Ordenar        string
+	Decrescente    bool
+	Limit          int32
+	Offset         int32
+}
+
 type BuscarTodosFuncionariosRow struct {
-	ID               int32
-	Nome             string
-	Matricula        string
-	Iddepartamento   int32
-	DepartamentoNome string
-	Idfuncao         int32
-	FuncaoNome       string
+	ID                 int32
+	Nome               string
+	Matricula          string
+	Iddepartamento     int32
+	DepartamentoNome   string
+	Idfuncao           int32
+	FuncaoNome         string
+	Ativo              bool
+	DataAdmissao       pgtype.Date
+	DataDesligamento   pgtype.Date
+	MotivoDesligamento pgtype.Text
+	Cpf                pgtype.Text
 }
 
-func (q *Queries) BuscarTodosFuncionarios(ctx context.Context, tenantID int32) ([]BuscarTodosFuncionariosRow, error) {
-	rows, err := q.db.Query(ctx, buscarTodosFuncionarios, tenantID)
+func (q *Queries) BuscarTodosFuncionarios(ctx context.Context, arg BuscarTodosFuncionariosParams) ([]BuscarTodosFuncionariosRow, error) {
+	rows, err := q.db.Query(ctx, buscarTodosFuncionarios,
+		arg.TenantID,
+		arg.Situacao,
+		arg.IDDepartamento,
+		arg.IDFuncao,
+		arg.Nome,
+		arg.Matricula,
+		arg.Ordenar,
+		arg.Decrescente,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -170,6 +305,11 @@ func (q *Queries) BuscarTodosFuncionarios(ctx context.Context, tenantID int32) (
 			&i.DepartamentoNome,
 			&i.Idfuncao,
 			&i.FuncaoNome,
+			&i.Ativo,
+			&i.DataAdmissao,
+			&i.DataDesligamento,
+			&i.MotivoDesligamento,
+			&i.Cpf,
 		); err != nil {
 			return nil, err
 		}
@@ -181,10 +321,49 @@ func (q *Queries) BuscarTodosFuncionarios(ctx context.Context, tenantID int32) (
 	return items, nil
 }
 
+const contarFuncionariosFiltrados = `-- name: ContarFuncionariosFiltrados :one
+SELECT COUNT(*)
+FROM funcionario fn
+WHERE fn.tenant_id = $1 -- SEGURANÇA
+  AND (
+    ($2::text = 'ativos' AND fn.ativo = TRUE) OR
+    ($2::text = 'desligados' AND fn.ativo = FALSE) OR
+    $2::text = 'todos'
+  )
+  AND ($3::int IS NULL OR fn.IdDepartamento = $3::int)
+  AND ($4::int IS NULL OR fn.IdFuncao = $4::int)
+  AND ($5::text IS NULL OR unaccent(fn.nome) ILIKE '%' || unaccent($5::text) || '%')
+  AND ($6::text IS NULL OR fn.matricula ILIKE '%' || $6::text || '%')
+`
+
+type ContarFuncionariosFiltradosParams struct {
+	TenantID       int32
+	Situacao       string
+	IDDepartamento pgtype.Int4
+	IDFuncao       pgtype.Int4
+	Nome           pgtype.Text
+	Matricula      pgtype.Text
+}
+
+func (q *Queries) ContarFuncionariosFiltrados(ctx context.Context, arg ContarFuncionariosFiltradosParams) (int64, error) {
+	row := q.db.QueryRow(ctx, contarFuncionariosFiltrados,
+		arg.TenantID,
+		arg.Situacao,
+		arg.IDDepartamento,
+		arg.IDFuncao,
+		arg.Nome,
+		arg.Matricula,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const deletarFuncionario = `-- name: DeletarFuncionario :execrows
 UPDATE funcionario
 SET ativo = FALSE,
-    deletado_em = NOW()
+    deletado_em = NOW(),
+    data_desligamento = CURRENT_DATE
 WHERE id = $1 
   AND tenant_id = $2 -- SEGURANÇA
   AND ativo = TRUE
@@ -203,6 +382,154 @@ func (q *Queries) DeletarFuncionario(ctx context.Context, arg DeletarFuncionario
 	return result.RowsAffected(), nil
 }
 
+const desligarFuncionario = `-- name: DesligarFuncionario :execrows
+UPDATE funcionario
+SET ativo = FALSE,
+    deletado_em = NOW(),
+    data_desligamento = $1,
+    motivo_desligamento = $2,
+    IdUsuarioDesligamento = $3
+WHERE id = $4
+  AND tenant_id = $5 -- SEGURANÇA
+  AND ativo = TRUE
+`
+
+type DesligarFuncionarioParams struct {
+	DataDesligamento   pgtype.Date
+	MotivoDesligamento pgtype.Text
+	IDUsuario          pgtype.Int4
+	ID                 int32
+	TenantID           int32
+}
+
+func (q *Queries) DesligarFuncionario(ctx context.Context, arg DesligarFuncionarioParams) (int64, error) {
+	result, err := q.db.Exec(ctx, desligarFuncionario,
+		arg.DataDesligamento,
+		arg.MotivoDesligamento,
+		arg.IDUsuario,
+		arg.ID,
+		arg.TenantID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const existeCpfFuncionario = `-- name: ExisteCpfFuncionario :one
+SELECT EXISTS (
+    SELECT 1
+    FROM funcionario
+    WHERE tenant_id = $1
+      AND cpf = $2
+      AND id <> $3::int
+)
+`
+
+type ExisteCpfFuncionarioParams struct {
+	TenantID  int32
+	Cpf       pgtype.Text
+	IDIgnorar int32
+}
+
+// id_ignorar é o próprio funcionario na atualização (0 no cadastro)
+func (q *Queries) ExisteCpfFuncionario(ctx context.Context, arg ExisteCpfFuncionarioParams) (bool, error) {
+	row := q.db.QueryRow(ctx, existeCpfFuncionario, arg.TenantID, arg.Cpf, arg.IDIgnorar)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listarIdsFuncionariosPorGrupo = `-- name: ListarIdsFuncionariosPorGrupo :many
+SELECT id
+FROM funcionario
+WHERE tenant_id = $1 -- SEGURANÇA
+  AND ativo = TRUE
+  AND ($2::int IS NULL OR IdDepartamento = $2::int)
+  AND ($3::int IS NULL OR IdFuncao = $3::int)
+ORDER BY nome
+`
+
+type ListarIdsFuncionariosPorGrupoParams struct {
+	TenantID       int32
+	IDDepartamento pgtype.Int4
+	IDFuncao       pgtype.Int4
+}
+
+func (q *Queries) ListarIdsFuncionariosPorGrupo(ctx context.Context, arg ListarIdsFuncionariosPorGrupoParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listarIdsFuncionariosPorGrupo, arg.TenantID, arg.IDDepartamento, arg.IDFuncao)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const registrarAcessoDadosPessoais = `-- name: RegistrarAcessoDadosPessoais :exec
+INSERT INTO acesso_dados_pessoais (tenant_id, IdFuncionario, IdUsuario)
+VALUES ($1, $2, $3)
+`
+
+type RegistrarAcessoDadosPessoaisParams struct {
+	TenantID      int32
+	Idfuncionario int32
+	Idusuario     int32
+}
+
+func (q *Queries) RegistrarAcessoDadosPessoais(ctx context.Context, arg RegistrarAcessoDadosPessoaisParams) error {
+	_, err := q.db.Exec(ctx, registrarAcessoDadosPessoais, arg.TenantID, arg.Idfuncionario, arg.Idusuario)
+	return err
+}
+
+const updateFuncionarioDadosPessoais = `-- name: UpdateFuncionarioDadosPessoais :execrows
+UPDATE funcionario
+SET cpf = CASE WHEN $1::bool THEN NULL ELSE COALESCE($2, cpf) END,
+    data_nascimento = COALESCE($3, data_nascimento),
+    email = COALESCE($4, email),
+    telefone = COALESCE($5, telefone)
+WHERE id = $6
+  AND tenant_id = $7 -- SEGURANÇA
+  AND ativo = TRUE
+`
+
+type UpdateFuncionarioDadosPessoaisParams struct {
+	LimparCpf      bool
+	Cpf            pgtype.Text
+	DataNascimento pgtype.Date
+	Email          pgtype.Text
+	Telefone       pgtype.Text
+	ID             int32
+	TenantID       int32
+}
+
+// Campo nulo mantém o valor atual; limpar_cpf apaga o CPF (NULL, fora do índice único)
+func (q *Queries) UpdateFuncionarioDadosPessoais(ctx context.Context, arg UpdateFuncionarioDadosPessoaisParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateFuncionarioDadosPessoais,
+		arg.LimparCpf,
+		arg.Cpf,
+		arg.DataNascimento,
+		arg.Email,
+		arg.Telefone,
+		arg.ID,
+		arg.TenantID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateFuncionarioDepartamento = `-- name: UpdateFuncionarioDepartamento :execrows
 UPDATE funcionario
 SET IdDepartamento = $2
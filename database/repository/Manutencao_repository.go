@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ManutencaoRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewManutencaoRepository(pool *pgxpool.Pool) *ManutencaoRepository {
+
+	return &ManutencaoRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+// BuscarParaConclusao devolve o erro cru para o service conseguir identificar o pgx.ErrNoRows
+func (m *ManutencaoRepository) BuscarParaConclusao(ctx context.Context, qtx *Queries, arg BuscarOrdemParaConclusaoParams) (BuscarOrdemParaConclusaoRow, error) {
+
+	return qtx.BuscarOrdemParaConclusao(ctx, arg)
+}
+
+func (m *ManutencaoRepository) Concluir(ctx context.Context, qtx *Queries, arg ConcluirOrdemManutencaoParams) error {
+
+	if err := qtx.ConcluirOrdemManutencao(ctx, arg); err != nil {
+		return helper.TraduzErroPostgres(err)
+	}
+
+	return nil
+}
+
+func (m *ManutencaoRepository) Listar(ctx context.Context, arg ListarOrdensManutencaoParams) ([]ListarOrdensManutencaoRow, error) {
+
+	ordens, err := m.q.ListarOrdensManutencao(ctx, arg)
+	if err != nil {
+		return []ListarOrdensManutencaoRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return ordens, nil
+}
+
+func (m *ManutencaoRepository) Relatorio(ctx context.Context, tenantId int32) ([]RelatorioManutencaoRow, error) {
+
+	itens, err := m.q.RelatorioManutencao(ctx, tenantId)
+	if err != nil {
+		return []RelatorioManutencaoRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return itens, nil
+}
@@ -10,22 +10,33 @@ import (
 )
 
 const addMotivoDevolucao = `-- name: AddMotivoDevolucao :exec
-INSERT INTO motivo_devolucao (tenant_id, motivo) 
-VALUES ($1, $2)
+INSERT INTO motivo_devolucao (tenant_id, motivo, volta_estoque, descarte, higienizacao, exige_inspecao) 
+VALUES ($1, $2, $3, $4, $5, $6)
 `
 
 type AddMotivoDevolucaoParams struct {
-	TenantID int32
-	Motivo   string
+	TenantID      int32
+	Motivo        string
+	VoltaEstoque  bool
+	Descarte      bool
+	Higienizacao  bool
+	ExigeInspecao bool
 }
 
 func (q *Queries) AddMotivoDevolucao(ctx context.Context, arg AddMotivoDevolucaoParams) error {
-	_, err := q.db.Exec(ctx, addMotivoDevolucao, arg.TenantID, arg.Motivo)
+	_, err := q.db.Exec(ctx, addMotivoDevolucao,
+		arg.TenantID,
+		arg.Motivo,
+		arg.VoltaEstoque,
+		arg.Descarte,
+		arg.Higienizacao,
+		arg.ExigeInspecao,
+	)
 	return err
 }
 
 const buscaMotivoDevolucao = `-- name: BuscaMotivoDevolucao :one
-SELECT id, motivo 
+SELECT id, motivo, volta_estoque, descarte, higienizacao, exige_inspecao 
 FROM motivo_devolucao 
 WHERE id = $1 
   AND tenant_id = $2 -- SEGURANÇA
@@ -39,19 +50,30 @@ type BuscaMotivoDevolucaoParams struct {
 }
 
 type BuscaMotivoDevolucaoRow struct {
-	ID     int32
-	Motivo string
+	ID            int32
+	Motivo        string
+	VoltaEstoque  bool
+	Descarte      bool
+	Higienizacao  bool
+	ExigeInspecao bool
 }
 
 func (q *Queries) BuscaMotivoDevolucao(ctx context.Context, arg BuscaMotivoDevolucaoParams) (BuscaMotivoDevolucaoRow, error) {
 	row := q.db.QueryRow(ctx, buscaMotivoDevolucao, arg.ID, arg.TenantID)
 	var i BuscaMotivoDevolucaoRow
-	err := row.Scan(&i.ID, &i.Motivo)
+	err := row.Scan(
+		&i.ID,
+		&i.Motivo,
+		&i.VoltaEstoque,
+		&i.Descarte,
+		&i.Higienizacao,
+		&i.ExigeInspecao,
+	)
 	return i, err
 }
 
 const buscaTodosMotivosDevolucao = `-- name: BuscaTodosMotivosDevolucao :many
-SELECT id, motivo 
+SELECT id, motivo, volta_estoque, descarte, higienizacao, exige_inspecao 
 FROM motivo_devolucao 
 WHERE tenant_id = $1 -- SEGURANÇA: Lista apenas os motivos desta empresa
   AND ativo = TRUE
@@ -59,8 +81,12 @@ ORDER BY motivo ASC
 `
 
 type BuscaTodosMotivosDevolucaoRow struct {
-	ID     int32
-	Motivo string
+	ID            int32
+	Motivo        string
+	VoltaEstoque  bool
+	Descarte      bool
+	Higienizacao  bool
+	ExigeInspecao bool
 }
 
 func (q *Queries) BuscaTodosMotivosDevolucao(ctx context.Context, tenantID int32) ([]BuscaTodosMotivosDevolucaoRow, error) {
@@ -72,7 +98,14 @@ func (q *Queries) BuscaTodosMotivosDevolucao(ctx context.Context, tenantID int32
 	var items []BuscaTodosMotivosDevolucaoRow
 	for rows.Next() {
 		var i BuscaTodosMotivosDevolucaoRow
-		if err := rows.Scan(&i.ID, &i.Motivo); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Motivo,
+			&i.VoltaEstoque,
+			&i.Descarte,
+			&i.Higienizacao,
+			&i.ExigeInspecao,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -104,3 +137,41 @@ func (q *Queries) DeleteMotivoDevolucao(ctx context.Context, arg DeleteMotivoDev
 	}
 	return result.RowsAffected(), nil
 }
+
+const updateMotivoDevolucao = `-- name: UpdateMotivoDevolucao :execrows
+UPDATE motivo_devolucao
+SET motivo = $3,
+    volta_estoque = $4,
+    descarte = $5,
+    higienizacao = $6,
+    exige_inspecao = $7
+WHERE id = $1
+  AND tenant_id = $2 -- SEGURANÇA
+  AND ativo = TRUE
+`
+
+type UpdateMotivoDevolucaoParams struct {
+	ID            int32
+	TenantID      int32
+	Motivo        string
+	VoltaEstoque  bool
+	Descarte      bool
+	Higienizacao  bool
+	ExigeInspecao bool
+}
+
+func (q *Queries) UpdateMotivoDevolucao(ctx context.Context, arg UpdateMotivoDevolucaoParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateMotivoDevolucao,
+		arg.ID,
+		arg.TenantID,
+		arg.Motivo,
+		arg.VoltaEstoque,
+		arg.Descarte,
+		arg.Higienizacao,
+		arg.ExigeInspecao,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
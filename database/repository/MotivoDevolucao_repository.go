@@ -33,15 +33,10 @@ func (m *MotivoDevolucaoRepository) Adicionar(ctx context.Context, motivo AddMot
 	return nil
 }
 
+// ListarMotivo devolve o erro cru: o service precisa enxergar o pgx.ErrNoRows
 func (m *MotivoDevolucaoRepository) ListarMotivo(ctx context.Context, arg BuscaMotivoDevolucaoParams) (BuscaMotivoDevolucaoRow, error){
 
-	motivo, err:= m.q.BuscaMotivoDevolucao(ctx, arg)
-	if err != nil {
-
-		 return BuscaMotivoDevolucaoRow{},helper.TraduzErroPostgres(err)
-	}
-
-	return  motivo, err
+	return m.q.BuscaMotivoDevolucao(ctx, arg)
 }
 
 func (m *MotivoDevolucaoRepository) ListarMotivos(ctx context.Context, tenantId int32) ([]BuscaTodosMotivosDevolucaoRow, error){
@@ -55,6 +50,17 @@ func (m *MotivoDevolucaoRepository) ListarMotivos(ctx context.Context, tenantId
 	return  motivos, err
 }
 
+func (m *MotivoDevolucaoRepository) Atualizar(ctx context.Context, arg UpdateMotivoDevolucaoParams) (int64, error) {
+
+	linhasAfetadas, err := m.q.UpdateMotivoDevolucao(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhasAfetadas, nil
+}
+
 func (m *MotivoDevolucaoRepository) CancelarMotivoDevolucao(ctx context.Context, arg DeleteMotivoDevolucaoParams) (int64, error) {
 
 	linhasAfetadas,err:= m.q.DeleteMotivoDevolucao(ctx, arg)
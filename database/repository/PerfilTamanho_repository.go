@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PerfilTamanhoRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewPerfilTamanhoRepository(pool *pgxpool.Pool) *PerfilTamanhoRepository {
+
+	return &PerfilTamanhoRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+func (p *PerfilTamanhoRepository) Definir(ctx context.Context, arg DefinirPerfilTamanhoParams) (int64, error) {
+
+	linhas, err := p.q.DefinirPerfilTamanho(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhas, nil
+}
+
+func (p *PerfilTamanhoRepository) Listar(ctx context.Context, arg ListarPerfilTamanhoParams) ([]ListarPerfilTamanhoRow, error) {
+
+	perfil, err := p.q.ListarPerfilTamanho(ctx, arg)
+	if err != nil {
+
+		return []ListarPerfilTamanhoRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return perfil, nil
+}
+
+func (p *PerfilTamanhoRepository) Remover(ctx context.Context, arg RemoverPerfilTamanhoParams) (int64, error) {
+
+	linhas, err := p.q.RemoverPerfilTamanho(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhas, nil
+}
+
+func (p *PerfilTamanhoRepository) Sugeridos(ctx context.Context, arg TamanhosSugeridosParams) ([]TamanhosSugeridosRow, error) {
+
+	tamanhos, err := p.q.TamanhosSugeridos(ctx, arg)
+	if err != nil {
+
+		return []TamanhosSugeridosRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return tamanhos, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PortalFuncionarioRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewPortalFuncionarioRepository(pool *pgxpool.Pool) *PortalFuncionarioRepository {
+
+	return &PortalFuncionarioRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+// BuscarParaLogin devolve o erro cru: o service precisa enxergar o pgx.ErrNoRows
+func (p *PortalFuncionarioRepository) BuscarParaLogin(ctx context.Context, arg BuscarFuncionarioParaLoginParams) (BuscarFuncionarioParaLoginRow, error) {
+
+	return p.q.BuscarFuncionarioParaLogin(ctx, arg)
+}
+
+func (p *PortalFuncionarioRepository) RegistrarFalhaPin(ctx context.Context, arg RegistrarFalhaPinParams) (int32, error) {
+
+	tentativas, err := p.q.RegistrarFalhaPin(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return tentativas, nil
+}
+
+func (p *PortalFuncionarioRepository) BloquearPin(ctx context.Context, arg BloquearPinParams) error {
+
+	if err := p.q.BloquearPin(ctx, arg); err != nil {
+
+		return helper.TraduzErroPostgres(err)
+	}
+
+	return nil
+}
+
+func (p *PortalFuncionarioRepository) ZerarTentativasPin(ctx context.Context, arg ZerarTentativasPinParams) error {
+
+	if err := p.q.ZerarTentativasPin(ctx, arg); err != nil {
+
+		return helper.TraduzErroPostgres(err)
+	}
+
+	return nil
+}
+
+func (p *PortalFuncionarioRepository) DefinirPin(ctx context.Context, arg DefinirPinFuncionarioParams) (int64, error) {
+
+	linhasAfetadas, err := p.q.DefinirPinFuncionario(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhasAfetadas, nil
+}
+
+// BuscarPerfil devolve o erro cru: o service precisa enxergar o pgx.ErrNoRows
+func (p *PortalFuncionarioRepository) BuscarPerfil(ctx context.Context, arg BuscaFuncionarioPorIdParams) (BuscaFuncionarioPorIdRow, error) {
+
+	return p.q.BuscaFuncionarioPorId(ctx, arg)
+}
+
+func (p *PortalFuncionarioRepository) AssinaturasPendentes(ctx context.Context, arg ListarAssinaturasPendentesParams) ([]ListarAssinaturasPendentesRow, error) {
+
+	pendentes, err := p.q.ListarAssinaturasPendentes(ctx, arg)
+	if err != nil {
+
+		return []ListarAssinaturasPendentesRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return pendentes, nil
+}
+
+func (p *PortalFuncionarioRepository) ConfirmarCiencia(ctx context.Context, arg ConfirmarCienciaEntregaParams) (int64, error) {
+
+	linhasAfetadas, err := p.q.ConfirmarCienciaEntrega(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhasAfetadas, nil
+}
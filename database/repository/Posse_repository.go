@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PosseRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewPosseRepository(pool *pgxpool.Pool) *PosseRepository {
+
+	return &PosseRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+// BuscarFuncionario devolve o erro cru: o service precisa enxergar o pgx.ErrNoRows
+func (p *PosseRepository) BuscarFuncionario(ctx context.Context, arg BuscaFuncionarioPorIdParams) (BuscaFuncionarioPorIdRow, error) {
+
+	return p.q.BuscaFuncionarioPorId(ctx, arg)
+}
+
+func (p *PosseRepository) ItensEntregues(ctx context.Context, arg ListarItensEntreguesFuncionarioParams) ([]ListarItensEntreguesFuncionarioRow, error) {
+
+	itens, err := p.q.ListarItensEntreguesFuncionario(ctx, arg)
+	if err != nil {
+		return []ListarItensEntreguesFuncionarioRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return itens, nil
+}
+
+func (p *PosseRepository) Devolvido(ctx context.Context, arg ListarDevolvidoFuncionarioParams) ([]ListarDevolvidoFuncionarioRow, error) {
+
+	devolvido, err := p.q.ListarDevolvidoFuncionario(ctx, arg)
+	if err != nil {
+		return []ListarDevolvidoFuncionarioRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return devolvido, nil
+}
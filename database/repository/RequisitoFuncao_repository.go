@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RequisitoFuncaoRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewRequisitoFuncaoRepository(pool *pgxpool.Pool) *RequisitoFuncaoRepository {
+
+	return &RequisitoFuncaoRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+func (r *RequisitoFuncaoRepository) Adicionar(ctx context.Context, arg AddRequisitoFuncaoParams) (int32, error) {
+
+	id, err := r.q.AddRequisitoFuncao(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return id, nil
+}
+
+func (r *RequisitoFuncaoRepository) ListarPorFuncao(ctx context.Context, arg BuscarRequisitosPorFuncaoParams) ([]BuscarRequisitosPorFuncaoRow, error) {
+
+	requisitos, err := r.q.BuscarRequisitosPorFuncao(ctx, arg)
+	if err != nil {
+
+		return []BuscarRequisitosPorFuncaoRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return requisitos, nil
+}
+
+func (r *RequisitoFuncaoRepository) Atualizar(ctx context.Context, arg UpdateRequisitoFuncaoParams) (int64, error) {
+
+	linhasAfetadas, err := r.q.UpdateRequisitoFuncao(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhasAfetadas, nil
+}
+
+func (r *RequisitoFuncaoRepository) Cancelar(ctx context.Context, arg DeletarRequisitoFuncaoParams) (int64, error) {
+
+	linhasAfetadas, err := r.q.DeletarRequisitoFuncao(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhasAfetadas, nil
+}
+
+func (r *RequisitoFuncaoRepository) SugestaoEntrega(ctx context.Context, arg BuscarSugestaoEntregaParams) ([]BuscarSugestaoEntregaRow, error) {
+
+	sugestao, err := r.q.BuscarSugestaoEntrega(ctx, arg)
+	if err != nil {
+
+		return []BuscarSugestaoEntregaRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return sugestao, nil
+}
+
+func (r *RequisitoFuncaoRepository) RelatorioConformidade(ctx context.Context, arg BuscarRelatorioConformidadeParams) ([]BuscarRelatorioConformidadeRow, error) {
+
+	linhas, err := r.q.BuscarRelatorioConformidade(ctx, arg)
+	if err != nil {
+
+		return []BuscarRelatorioConformidadeRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return linhas, nil
+}
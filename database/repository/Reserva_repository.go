@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReservaRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewReservaRepository(pool *pgxpool.Pool) *ReservaRepository {
+
+	return &ReservaRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+func (r *ReservaRepository) Adicionar(ctx context.Context, qtx *Queries, arg AddReservaParams) (int32, error) {
+
+	id, err := qtx.AddReserva(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return id, nil
+}
+
+func (r *ReservaRepository) Cancelar(ctx context.Context, arg CancelarReservaParams) (int64, error) {
+
+	linhasAfetadas, err := r.q.CancelarReserva(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhasAfetadas, nil
+}
+
+func (r *ReservaRepository) Listar(ctx context.Context, arg ListarReservasParams) ([]ListarReservasRow, error) {
+
+	reservas, err := r.q.ListarReservas(ctx, arg)
+	if err != nil {
+
+		return []ListarReservasRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return reservas, nil
+}
+
+func (r *ReservaRepository) Saldo(ctx context.Context, arg SaldoDisponivelParams) (SaldoDisponivelRow, error) {
+
+	saldo, err := r.q.SaldoDisponivel(ctx, arg)
+	if err != nil {
+
+		return SaldoDisponivelRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return saldo, nil
+}
+
+func (r *ReservaRepository) QuantidadeReservadaSemLote(ctx context.Context, qtx *Queries, arg QuantidadeReservadaSemLoteParams) (int32, error) {
+
+	quantidade, err := qtx.QuantidadeReservadaSemLote(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return quantidade, nil
+}
+
+func (r *ReservaRepository) ListarDaSolicitacao(ctx context.Context, qtx *Queries, arg ListarReservasSolicitacaoParams) ([]ListarReservasSolicitacaoRow, error) {
+
+	reservas, err := qtx.ListarReservasSolicitacao(ctx, arg)
+	if err != nil {
+
+		return []ListarReservasSolicitacaoRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return reservas, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SolicitacaoRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewSolicitacaoRepository(pool *pgxpool.Pool) *SolicitacaoRepository {
+
+	return &SolicitacaoRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+func (s *SolicitacaoRepository) Adicionar(ctx context.Context, qtx *Queries, arg AddSolicitacaoParams) (int32, error) {
+
+	id, err := qtx.AddSolicitacao(ctx, arg)
+	if err != nil {
+
+		// o insert só devolve linha para funcionario ativo da empresa
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, helper.ErrFuncionarioInativo
+		}
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return id, nil
+}
+
+func (s *SolicitacaoRepository) AdicionarItem(ctx context.Context, qtx *Queries, arg AddItemSolicitacaoParams) error {
+
+	err := qtx.AddItemSolicitacao(ctx, arg)
+	if err != nil {
+
+		return helper.TraduzErroPostgres(err)
+	}
+
+	return nil
+}
+
+func (s *SolicitacaoRepository) Listar(ctx context.Context, arg ListarSolicitacoesParams) ([]ListarSolicitacoesRow, error) {
+
+	solicitacoes, err := s.q.ListarSolicitacoes(ctx, arg)
+	if err != nil {
+
+		return []ListarSolicitacoesRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return solicitacoes, nil
+}
+
+func (s *SolicitacaoRepository) ListarItens(ctx context.Context, arg ListarItensDasSolicitacoesParams) ([]ListarItensDasSolicitacoesRow, error) {
+
+	itens, err := s.q.ListarItensDasSolicitacoes(ctx, arg)
+	if err != nil {
+
+		return []ListarItensDasSolicitacoesRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return itens, nil
+}
+
+// BuscarParaAnalise devolve o erro cru para o service conseguir identificar o pgx.ErrNoRows
+func (s *SolicitacaoRepository) BuscarParaAnalise(ctx context.Context, qtx *Queries, arg BuscarSolicitacaoParaAnaliseParams) (BuscarSolicitacaoParaAnaliseRow, error) {
+
+	return qtx.BuscarSolicitacaoParaAnalise(ctx, arg)
+}
+
+func (s *SolicitacaoRepository) ItensDaSolicitacao(ctx context.Context, qtx *Queries, arg ListarItensSolicitacaoParams) ([]ListarItensSolicitacaoRow, error) {
+
+	itens, err := qtx.ListarItensSolicitacao(ctx, arg)
+	if err != nil {
+
+		return []ListarItensSolicitacaoRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return itens, nil
+}
+
+func (s *SolicitacaoRepository) Aprovar(ctx context.Context, qtx *Queries, arg AprovarSolicitacaoParams) (int64, error) {
+
+	linhasAfetadas, err := qtx.AprovarSolicitacao(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhasAfetadas, nil
+}
+
+func (s *SolicitacaoRepository) Rejeitar(ctx context.Context, qtx *Queries, arg RejeitarSolicitacaoParams) (int64, error) {
+
+	linhasAfetadas, err := qtx.RejeitarSolicitacao(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhasAfetadas, nil
+}
+
+func (s *SolicitacaoRepository) Atender(ctx context.Context, qtx *Queries, arg AtenderSolicitacaoParams) (int64, error) {
+
+	linhasAfetadas, err := qtx.AtenderSolicitacao(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhasAfetadas, nil
+}
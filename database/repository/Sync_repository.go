@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SyncRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewSyncRepository(pool *pgxpool.Pool) *SyncRepository {
+
+	return &SyncRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+// ReservarOperacao devolve o erro cru: pgx.ErrNoRows indica UUID já aplicado
+func (s *SyncRepository) ReservarOperacao(ctx context.Context, qtx *Queries, arg ReservarOperacaoSyncParams) (int32, error) {
+
+	return qtx.ReservarOperacaoSync(ctx, arg)
+}
+
+func (s *SyncRepository) ConcluirOperacao(ctx context.Context, qtx *Queries, arg ConcluirOperacaoSyncParams) error {
+
+	if err := qtx.ConcluirOperacaoSync(ctx, arg); err != nil {
+
+		return helper.TraduzErroPostgres(err)
+	}
+
+	return nil
+}
+
+func (s *SyncRepository) RegistrarConflito(ctx context.Context, arg RegistrarConflitoSyncParams) error {
+
+	if err := s.q.RegistrarConflitoSync(ctx, arg); err != nil {
+
+		return helper.TraduzErroPostgres(err)
+	}
+
+	return nil
+}
+
+// BuscarOperacao devolve o erro cru: o service precisa enxergar o pgx.ErrNoRows
+func (s *SyncRepository) BuscarOperacao(ctx context.Context, arg BuscarOperacaoSyncParams) (BuscarOperacaoSyncRow, error) {
+
+	return s.q.BuscarOperacaoSync(ctx, arg)
+}
+
+func (s *SyncRepository) CatalogoEpis(ctx context.Context, tenantId int32) ([]CatalogoEpisSyncRow, error) {
+
+	epis, err := s.q.CatalogoEpisSync(ctx, tenantId)
+	if err != nil {
+
+		return []CatalogoEpisSyncRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return epis, nil
+}
+
+func (s *SyncRepository) CatalogoTamanhosEpis(ctx context.Context, tenantId int32) ([]BuscarTodosTamanhosAgrupadosRow, error) {
+
+	tamanhos, err := s.q.BuscarTodosTamanhosAgrupados(ctx, tenantId)
+	if err != nil {
+
+		return []BuscarTodosTamanhosAgrupadosRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return tamanhos, nil
+}
+
+func (s *SyncRepository) CatalogoTamanhos(ctx context.Context, tenantId int32) ([]BuscarTodosTamanhosRow, error) {
+
+	tamanhos, err := s.q.BuscarTodosTamanhos(ctx, tenantId)
+	if err != nil {
+
+		return []BuscarTodosTamanhosRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return tamanhos, nil
+}
+
+func (s *SyncRepository) CatalogoFuncionarios(ctx context.Context, tenantId int32) ([]CatalogoFuncionariosSyncRow, error) {
+
+	funcionarios, err := s.q.CatalogoFuncionariosSync(ctx, tenantId)
+	if err != nil {
+
+		return []CatalogoFuncionariosSyncRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return funcionarios, nil
+}
+
+func (s *SyncRepository) CatalogoSaldoLotes(ctx context.Context, tenantId int32) ([]CatalogoSaldoLotesSyncRow, error) {
+
+	lotes, err := s.q.CatalogoSaldoLotesSync(ctx, tenantId)
+	if err != nil {
+
+		return []CatalogoSaldoLotesSyncRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return lotes, nil
+}
@@ -7,29 +7,33 @@ package repository
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addProtecao = `-- name: AddProtecao :exec
-INSERT INTO tipo_protecao (tenant_id, nome) 
-VALUES ($1, $2)
+INSERT INTO tipo_protecao (tenant_id, nome, IdCategoria) 
+VALUES ($1, $2, $3)
 `
 
 type AddProtecaoParams struct {
-	TenantID int32
-	Nome     string
+	TenantID    int32
+	Nome        string
+	Idcategoria pgtype.Int4
 }
 
 func (q *Queries) AddProtecao(ctx context.Context, arg AddProtecaoParams) error {
-	_, err := q.db.Exec(ctx, addProtecao, arg.TenantID, arg.Nome)
+	_, err := q.db.Exec(ctx, addProtecao, arg.TenantID, arg.Nome, arg.Idcategoria)
 	return err
 }
 
 const buscarProtecao = `-- name: BuscarProtecao :one
-SELECT id, nome 
-FROM tipo_protecao 
-WHERE id = $1 
-  AND tenant_id = $2 -- SEGURANÇA
-  AND ativo = TRUE 
+SELECT tp.id, tp.nome, tp.IdCategoria, c.codigo as categoria_codigo, c.nome as categoria_nome
+FROM tipo_protecao tp
+LEFT JOIN categoria_protecao c ON tp.IdCategoria = c.id
+WHERE tp.id = $1 
+  AND tp.tenant_id = $2 -- SEGURANÇA
+  AND tp.ativo = TRUE 
 LIMIT 1
 `
 
@@ -39,28 +43,41 @@ type BuscarProtecaoParams struct {
 }
 
 type BuscarProtecaoRow struct {
-	ID   int32
-	Nome string
+	ID              int32
+	Nome            string
+	Idcategoria     pgtype.Int4
+	CategoriaCodigo pgtype.Text
+	CategoriaNome   pgtype.Text
 }
 
 func (q *Queries) BuscarProtecao(ctx context.Context, arg BuscarProtecaoParams) (BuscarProtecaoRow, error) {
 	row := q.db.QueryRow(ctx, buscarProtecao, arg.ID, arg.TenantID)
 	var i BuscarProtecaoRow
-	err := row.Scan(&i.ID, &i.Nome)
+	err := row.Scan(
+		&i.ID,
+		&i.Nome,
+		&i.Idcategoria,
+		&i.CategoriaCodigo,
+		&i.CategoriaNome,
+	)
 	return i, err
 }
 
 const buscarTodasProtecoes = `-- name: BuscarTodasProtecoes :many
-SELECT id, nome 
-FROM tipo_protecao 
-WHERE tenant_id = $1 -- SEGURANÇA: Lista apenas do cliente logado
-  AND ativo = TRUE
-ORDER BY nome ASC
+SELECT tp.id, tp.nome, tp.IdCategoria, c.codigo as categoria_codigo, c.nome as categoria_nome
+FROM tipo_protecao tp
+LEFT JOIN categoria_protecao c ON tp.IdCategoria = c.id
+WHERE tp.tenant_id = $1 -- SEGURANÇA: Lista apenas do cliente logado
+  AND tp.ativo = TRUE
+ORDER BY tp.nome ASC
 `
 
 type BuscarTodasProtecoesRow struct {
-	ID   int32
-	Nome string
+	ID              int32
+	Nome            string
+	Idcategoria     pgtype.Int4
+	CategoriaCodigo pgtype.Text
+	CategoriaNome   pgtype.Text
 }
 
 func (q *Queries) BuscarTodasProtecoes(ctx context.Context, tenantID int32) ([]BuscarTodasProtecoesRow, error) {
@@ -72,7 +89,13 @@ func (q *Queries) BuscarTodasProtecoes(ctx context.Context, tenantID int32) ([]B
 	var items []BuscarTodasProtecoesRow
 	for rows.Next() {
 		var i BuscarTodasProtecoesRow
-		if err := rows.Scan(&i.ID, &i.Nome); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Nome,
+			&i.Idcategoria,
+			&i.CategoriaCodigo,
+			&i.CategoriaNome,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -83,6 +106,28 @@ func (q *Queries) BuscarTodasProtecoes(ctx context.Context, tenantID int32) ([]B
 	return items, nil
 }
 
+const definirCategoriaProtecao = `-- name: DefinirCategoriaProtecao :execrows
+UPDATE tipo_protecao
+SET IdCategoria = $2
+WHERE id = $1 
+  AND tenant_id = $3 -- SEGURANÇA
+  AND ativo = TRUE
+`
+
+type DefinirCategoriaProtecaoParams struct {
+	ID          int32
+	Idcategoria pgtype.Int4
+	TenantID    int32
+}
+
+func (q *Queries) DefinirCategoriaProtecao(ctx context.Context, arg DefinirCategoriaProtecaoParams) (int64, error) {
+	result, err := q.db.Exec(ctx, definirCategoriaProtecao, arg.ID, arg.Idcategoria, arg.TenantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deletarProtecao = `-- name: DeletarProtecao :execrows
 UPDATE tipo_protecao
 SET ativo = FALSE,
@@ -105,6 +150,33 @@ func (q *Queries) DeletarProtecao(ctx context.Context, arg DeletarProtecaoParams
 	return result.RowsAffected(), nil
 }
 
+const listarCategoriasProtecao = `-- name: ListarCategoriasProtecao :many
+SELECT id, codigo, nome
+FROM categoria_protecao
+ORDER BY codigo
+`
+
+// Categorias da NR-6, iguais para todas as empresas
+func (q *Queries) ListarCategoriasProtecao(ctx context.Context) ([]CategoriaProtecao, error) {
+	rows, err := q.db.Query(ctx, listarCategoriasProtecao)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CategoriaProtecao
+	for rows.Next() {
+		var i CategoriaProtecao
+		if err := rows.Scan(&i.ID, &i.Codigo, &i.Nome); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateProtecao = `-- name: UpdateProtecao :execrows
 UPDATE tipo_protecao
 SET nome = $2
@@ -61,3 +61,24 @@ func (p *ProtecaoRepository) CancelarProtecao(ctx context.Context, arg DeletarPr
 	return linhasAfetadas, nil
 }
 
+func (p *ProtecaoRepository) DefinirCategoria(ctx context.Context, arg DefinirCategoriaProtecaoParams) (int64, error) {
+
+	linhasAfetadas, err := p.q.DefinirCategoriaProtecao(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhasAfetadas, nil
+}
+
+func (p *ProtecaoRepository) ListarCategorias(ctx context.Context) ([]CategoriaProtecao, error) {
+
+	categorias, err := p.q.ListarCategoriasProtecao(ctx)
+	if err != nil {
+
+		return []CategoriaProtecao{}, helper.TraduzErroPostgres(err)
+	}
+
+	return categorias, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TreinamentoRepository struct {
+	q  *Queries
+	db *pgxpool.Pool
+}
+
+func NewTreinamentoRepository(pool *pgxpool.Pool) *TreinamentoRepository {
+
+	return &TreinamentoRepository{
+		q:  New(pool),
+		db: pool,
+	}
+}
+
+func (t *TreinamentoRepository) Adicionar(ctx context.Context, qtx *Queries, arg AddTreinamentoParams) (int32, error) {
+
+	id, err := qtx.AddTreinamento(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return id, nil
+}
+
+func (t *TreinamentoRepository) AdicionarParticipante(ctx context.Context, qtx *Queries, arg AddParticipanteTreinamentoParams) (int64, error) {
+
+	linhas, err := qtx.AddParticipanteTreinamento(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhas, nil
+}
+
+func (t *TreinamentoRepository) Listar(ctx context.Context, arg ListarTreinamentosParams) ([]ListarTreinamentosRow, error) {
+
+	treinamentos, err := t.q.ListarTreinamentos(ctx, arg)
+	if err != nil {
+
+		return []ListarTreinamentosRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return treinamentos, nil
+}
+
+// Buscar devolve o erro cru para o service conseguir identificar o pgx.ErrNoRows
+func (t *TreinamentoRepository) Buscar(ctx context.Context, arg BuscarTreinamentoParams) (BuscarTreinamentoRow, error) {
+
+	return t.q.BuscarTreinamento(ctx, arg)
+}
+
+func (t *TreinamentoRepository) Participantes(ctx context.Context, arg ListarParticipantesTreinamentoParams) ([]ListarParticipantesTreinamentoRow, error) {
+
+	participantes, err := t.q.ListarParticipantesTreinamento(ctx, arg)
+	if err != nil {
+
+		return []ListarParticipantesTreinamentoRow{}, helper.TraduzErroPostgres(err)
+	}
+
+	return participantes, nil
+}
+
+func (t *TreinamentoRepository) Deletar(ctx context.Context, arg DeletarTreinamentoParams) (int64, error) {
+
+	linhas, err := t.q.DeletarTreinamento(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhas, nil
+}
+
+func (t *TreinamentoRepository) DefinirRegra(ctx context.Context, arg DefinirRegraTreinamentoParams) (int64, error) {
+
+	linhas, err := t.q.DefinirRegraTreinamento(ctx, arg)
+	if err != nil {
+
+		return 0, helper.TraduzErroPostgres(err)
+	}
+
+	return linhas, nil
+}
@@ -8,6 +8,84 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AnexoEpi struct {
+	ID          int32
+	TenantID    int32
+	Idepi       int32
+	Tipo        string
+	NomeArquivo string
+	Mime        string
+	Tamanho     int64
+	Chave       string
+	Principal   bool
+	Idusuario   pgtype.Int4
+	CriadoEm    pgtype.Timestamp
+}
+
+type BaixaPosse struct {
+	ID            int32
+	TenantID      int32
+	Idfuncionario int32
+	Idepi         int32
+	Idtamanho     int32
+	Quantidade    int32
+	Motivo        string
+	DataBaixa     pgtype.Timestamp
+	Idusuario     pgtype.Int4
+}
+
+type AcessoDadosPessoai struct {
+	ID            int32
+	TenantID      int32
+	Idfuncionario int32
+	Idusuario     int32
+	AcessadoEm    pgtype.Timestamp
+}
+
+type AlertaCa struct {
+	ID               int32
+	TenantID         int32
+	Idepi            int32
+	Idimportacao     int32
+	Tipo             string
+	Situacao         string
+	ValidadeAnterior pgtype.Date
+	ValidadeNova     pgtype.Date
+	CriadoEm         pgtype.Timestamp
+	ResolvidoEm      pgtype.Timestamp
+}
+
+type CatalogoCa struct {
+	Ca                    string
+	Situacao              string
+	Validade              pgtype.Date
+	CnpjFabricante        pgtype.Text
+	Fabricante            string
+	Equipamento           string
+	Descricao             string
+	SituacaoAnterior      pgtype.Text
+	ValidadeAnterior      pgtype.Date
+	Idimportacao          int32
+	Idimportacaoalteracao pgtype.Int4
+}
+
+type CatalogoCaCarga struct {
+	Idimportacao   int32
+	Ca             string
+	Situacao       string
+	Validade       pgtype.Date
+	CnpjFabricante pgtype.Text
+	Fabricante     string
+	Equipamento    string
+	Descricao      string
+}
+
+type CategoriaProtecao struct {
+	ID     int32
+	Codigo string
+	Nome   string
+}
+
 type Departamento struct {
 	ID         int32
 	TenantID   int32
@@ -34,16 +112,34 @@ type Devolucao struct {
 	IDUsuarioCancelamento          pgtype.Int4
 	IDUsuarioDevolucaoCancelamento pgtype.Int4
 	TokenValidacao                 pgtype.Text
+	Iddocumento                    pgtype.Int4
+	Destino                        pgtype.Text
+	Identradaestoque               pgtype.Int4
+}
+
+type DevolucaoDocumento struct {
+	ID                    int32
+	TenantID              int32
+	Idfuncionario         int32
+	DataDevolucao         pgtype.Date
+	AssinaturaDigital     string
+	TokenValidacao        pgtype.Text
+	Identregatroca        pgtype.Int4
+	Idusuario             pgtype.Int4
+	CriadoEm              pgtype.Timestamp
+	CanceladaEm           pgtype.Timestamp
+	IDUsuarioCancelamento pgtype.Int4
 }
 
 type Empresa struct {
-	ID           int32
-	NomeFantasia string
-	RazaoSocial  string
-	Cnpj         string
-	Subdominio   string
-	Ativo        bool
-	CriadoEm     pgtype.Timestamp
+	ID                      int32
+	NomeFantasia            string
+	RazaoSocial             string
+	Cnpj                    string
+	Subdominio              string
+	Ativo                   bool
+	CriadoEm                pgtype.Timestamp
+	RegraTreinamentoEntrega string
 }
 
 type EntradaEpi struct {
@@ -79,6 +175,8 @@ type EntregaEpi struct {
 	TokenValidacao               pgtype.Text
 	IDUsuarioEntrega             pgtype.Int4
 	IDUsuarioEntregaCancelamento pgtype.Int4
+	CienteEm                     pgtype.Timestamp
+	PendenciaTreinamento         bool
 }
 
 type Epi struct {
@@ -127,23 +225,182 @@ type Funcao struct {
 	DeletadoEm     pgtype.Timestamp
 }
 
+type FuncaoEsocial struct {
+	Idfuncao           int32
+	TenantID           int32
+	CodAgenteNocivo    string
+	DescricaoAtividade string
+	AtualizadoEm       pgtype.Timestamp
+}
+
 type Funcionario struct {
+	ID                    int32
+	TenantID              int32
+	Nome                  string
+	Matricula             string
+	Idfuncao              int32
+	Iddepartamento        int32
+	Ativo                 bool
+	DeletadoEm            pgtype.Timestamp
+	PinHash               pgtype.Text
+	TentativasPin         int32
+	PinBloqueadoAte       pgtype.Timestamp
+	DataAdmissao          pgtype.Date
+	DataDesligamento      pgtype.Date
+	MotivoDesligamento    pgtype.Text
+	Idusuariodesligamento pgtype.Int4
+	Cpf                   pgtype.Text
+	DataNascimento        pgtype.Date
+	Email                 pgtype.Text
+	Telefone              pgtype.Text
+	PinVersao             int32
+}
+
+type FuncionarioCargoHistorico struct {
 	ID             int32
 	TenantID       int32
-	Nome           string
-	Matricula      string
-	Idfuncao       int32
+	Idfuncionario  int32
 	Iddepartamento int32
-	Ativo          bool
-	DeletadoEm     pgtype.Timestamp
+	Idfuncao       int32
+	DataInicio     pgtype.Date
+	DataFim        pgtype.Date
+	CriadoEm       pgtype.Timestamp
+}
+
+type ImportacaoCaepi struct {
+	ID             int32
+	Arquivo        string
+	TotalRegistros int32
+	Novos          int32
+	Alterados      int32
+	ImportadoEm    pgtype.Timestamp
+	SinalizadaEm   pgtype.Timestamp
+}
+
+type ItemSolicitacao struct {
+	ID            int32
+	TenantID      int32
+	Idsolicitacao int32
+	Idepi         int32
+	Idtamanho     int32
+	Quantidade    int32
 }
 
 type MotivoDevolucao struct {
+	ID            int32
+	TenantID      int32
+	Motivo        string
+	Ativo         bool
+	DeletadoEm    pgtype.Timestamp
+	VoltaEstoque  bool
+	Descarte      bool
+	Higienizacao  bool
+	ExigeInspecao bool
+}
+
+type OrdemManutencao struct {
+	ID                 int32
+	TenantID           int32
+	Idepi              int32
+	Idtamanho          int32
+	Quantidade         int32
+	Iddevolucao        pgtype.Int4
+	Status             string
+	ServicoRealizado   pgtype.Text
+	Observacao         pgtype.Text
+	AbertaEm           pgtype.Timestamp
+	ConcluidaEm        pgtype.Timestamp
+	Idusuarioconclusao pgtype.Int4
+	Tipo               string
+}
+
+type PerfilTamanho struct {
+	ID             int32
+	TenantID       int32
+	Idfuncionario  int32
+	Idepi          pgtype.Int4
+	Idtipoprotecao pgtype.Int4
+	Idtamanho      int32
+	Origem         string
+	AtualizadoEm   pgtype.Timestamp
+}
+
+type ReservaEstoque struct {
+	ID                    int32
+	TenantID              int32
+	Idepi                 int32
+	Idtamanho             int32
+	Identrada             pgtype.Int4
+	Quantidade            int32
+	ExpiraEm              pgtype.Timestamp
+	Idsolicitacao         pgtype.Int4
+	Observacao            pgtype.Text
+	Idusuario             pgtype.Int4
+	CriadaEm              pgtype.Timestamp
+	Identrega             pgtype.Int4
+	ConsumidaEm           pgtype.Timestamp
+	CanceladaEm           pgtype.Timestamp
+	IDUsuarioCancelamento pgtype.Int4
+}
+
+type RequisicaoIdempotente struct {
+	ID             int32
+	TenantID       int32
+	Chave          string
+	HashRequisicao string
+	StatusResposta pgtype.Int4
+	CorpoResposta  pgtype.Text
+	CriadoEm       pgtype.Timestamp
+	ConcluidaEm    pgtype.Timestamp
+}
+
+type RequisitoFuncao struct {
+	ID                int32
+	TenantID          int32
+	Idfuncao          int32
+	Idepi             pgtype.Int4
+	Idtipoprotecao    pgtype.Int4
+	Quantidade        int32
+	PeriodicidadeDias pgtype.Int4
+	Ativo             bool
+	DeletadoEm        pgtype.Timestamp
+}
+
+type SaidaPosse struct {
+	TenantID      int32
+	Idfuncionario int32
+	Idepi         int32
+	Idtamanho     int32
+	Quantidade    int32
+	DataSaida     pgtype.Date
+}
+
+type SolicitacaoEpi struct {
+	ID                   int32
+	TenantID             int32
+	Idfuncionario        int32
+	Idusuariosolicitante pgtype.Int4
+	Justificativa        string
+	Status               string
+	Idusuarioanalise     pgtype.Int4
+	MotivoRejeicao       pgtype.Text
+	AnalisadaEm          pgtype.Timestamp
+	Identrega            pgtype.Int4
+	AtendidaEm           pgtype.Timestamp
+	CriadaEm             pgtype.Timestamp
+	Origem               string
+}
+
+type SyncOperacao struct {
 	ID         int32
 	TenantID   int32
-	Motivo     string
-	Ativo      bool
-	DeletadoEm pgtype.Timestamp
+	Uuid       pgtype.UUID
+	Tipo       string
+	Status     string
+	IDRegistro pgtype.Int4
+	Erro       pgtype.Text
+	Idusuario  pgtype.Int4
+	RecebidaEm pgtype.Timestamp
 }
 
 type Tamanho struct {
@@ -164,11 +421,33 @@ type TamanhosEpi struct {
 }
 
 type TipoProtecao struct {
-	ID         int32
-	TenantID   int32
-	Nome       string
-	Ativo      bool
-	DeletadoEm pgtype.Timestamp
+	ID          int32
+	TenantID    int32
+	Nome        string
+	Ativo       bool
+	DeletadoEm  pgtype.Timestamp
+	Idcategoria pgtype.Int4
+}
+
+type Treinamento struct {
+	ID             int32
+	TenantID       int32
+	Titulo         string
+	Idepi          pgtype.Int4
+	Idtipoprotecao pgtype.Int4
+	DataRealizacao pgtype.Date
+	Instrutor      string
+	DataValidade   pgtype.Date
+	Ativo          bool
+	CriadoEm       pgtype.Timestamp
+	DeletadoEm     pgtype.Timestamp
+}
+
+type TreinamentoParticipante struct {
+	ID            int32
+	TenantID      int32
+	Idtreinamento int32
+	Idfuncionario int32
 }
 
 type Usuario struct {
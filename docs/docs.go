@@ -553,29 +553,35 @@ const docTemplate = `{
             }
         },
         "/funcionario/{id}": {
-            "delete": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove (ou inativa) um funcionario pelo ID",
+                "description": "Retorna os detalhes de um único funcionario",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "funcionarios"
                 ],
-                "summary": "Deletar funcionario",
+                "summary": "Buscar por matricula",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID do funcionario",
+                        "description": "matricula do funcionario",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Sem Conteúdo (Sucesso)"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Funcionario_Dto"
+                        }
                     },
                     "400": {
                         "description": "ID inválido",
@@ -597,18 +603,17 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
-                "description": "Atualiza os dados de um funcionario existente",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Remove (ou inativa) um funcionario pelo ID",
                 "tags": [
                     "funcionarios"
                 ],
-                "summary": "Atualizar funcionario",
+                "summary": "Deletar funcionario",
                 "parameters": [
                     {
                         "type": "integer",
@@ -616,29 +621,14 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "funcionario novos dados",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.UpdateFuncionarioRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Sucesso",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                    "204": {
+                        "description": "Sem Conteúdo (Sucesso)"
                     },
                     "400": {
-                        "description": "Erro de validação (ID ou Nome curto)",
+                        "description": "ID inválido",
                         "schema": {
                             "$ref": "#/definitions/helper.HTTPError"
                         }
@@ -656,41 +646,49 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/funcionario/{matricula}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            },
+            "patch": {
+                "description": "Atualiza os dados de um funcionario existente",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retorna os detalhes de um único funcionario",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "funcionarios"
                 ],
-                "summary": "Buscar por matricula",
+                "summary": "Atualizar funcionario",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "matricula do funcionario",
+                        "description": "ID do funcionario",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "funcionario novos dados",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateFuncionarioRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Sucesso",
                         "schema": {
-                            "$ref": "#/definitions/model.Funcionario_Dto"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
-                        "description": "ID inválido",
+                        "description": "Erro de validação (ID ou Nome curto)",
                         "schema": {
                             "$ref": "#/definitions/helper.HTTPError"
                         }
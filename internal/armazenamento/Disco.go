@@ -0,0 +1,107 @@
+package armazenamento
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// diretorioPadrao é usado quando ARQUIVOS_DIR não está definida
+const diretorioPadrao = "arquivos"
+
+var errChaveInvalida = errors.New("chave de arquivo invalida")
+
+// Disco guarda os arquivos numa pasta local. Para trocar por um bucket (S3, GCS...) basta outra
+// implementação com os mesmos métodos, os services só dependem da interface deles
+type Disco struct {
+	raiz string
+}
+
+// NewDisco usa a pasta informada ou, se vazia, a variável ARQUIVOS_DIR
+func NewDisco(raiz string) *Disco {
+
+	if raiz == "" {
+		raiz = os.Getenv("ARQUIVOS_DIR")
+	}
+	if raiz == "" {
+		raiz = diretorioPadrao
+	}
+
+	return &Disco{raiz: raiz}
+}
+
+// Salvar escreve num arquivo temporário e só renomeia no final, assim um upload interrompido
+// nunca deixa um arquivo pela metade no lugar da chave
+func (d *Disco) Salvar(ctx context.Context, chave string, conteudo io.Reader) error {
+
+	destino, err := d.caminho(chave)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destino), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(destino), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, conteudo); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), destino)
+}
+
+// Abrir devolve os.ErrNotExist (via errors.Is) quando o arquivo não está mais lá
+func (d *Disco) Abrir(ctx context.Context, chave string) (io.ReadCloser, error) {
+
+	origem, err := d.caminho(chave)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(origem)
+}
+
+// Remover não reclama se o arquivo já não existir
+func (d *Disco) Remover(ctx context.Context, chave string) error {
+
+	alvo, err := d.caminho(chave)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(alvo); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// caminho impede que uma chave com ".." ou caminho absoluto saia da pasta raiz
+func (d *Disco) caminho(chave string) (string, error) {
+
+	limpa := filepath.Clean(filepath.FromSlash(chave))
+	if chave == "" || filepath.IsAbs(limpa) || limpa == ".." || strings.HasPrefix(limpa, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %q", errChaveInvalida, chave)
+	}
+
+	return filepath.Join(d.raiz, limpa), nil
+}
@@ -0,0 +1,44 @@
+package armazenamento
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisco(t *testing.T) {
+
+	ctx := context.Background()
+	disco := NewDisco(t.TempDir())
+
+	require.NoError(t, disco.Salvar(ctx, "epis/1/2/foto.png", strings.NewReader("conteudo")))
+
+	arquivo, err := disco.Abrir(ctx, "epis/1/2/foto.png")
+	require.NoError(t, err)
+	lido, err := io.ReadAll(arquivo)
+	arquivo.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "conteudo", string(lido))
+
+	require.NoError(t, disco.Remover(ctx, "epis/1/2/foto.png"))
+	require.NoError(t, disco.Remover(ctx, "epis/1/2/foto.png"))
+
+	_, err = disco.Abrir(ctx, "epis/1/2/foto.png")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestDiscoChaveForaDaRaiz(t *testing.T) {
+
+	disco := NewDisco(t.TempDir())
+
+	for _, chave := range []string{"", "../fora.txt", "epis/../../fora.txt", "/etc/passwd"} {
+		err := disco.Salvar(context.Background(), chave, strings.NewReader("x"))
+		assert.ErrorIs(t, err, errChaveInvalida, chave)
+	}
+}
@@ -0,0 +1,59 @@
+package helper
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Tipos de anexo aceitos no cadastro do epi
+const (
+	AnexoFoto          = "foto"
+	AnexoCertificadoCa = "certificado_ca"
+	AnexoManual        = "manual"
+	AnexoFichaTecnica  = "ficha_tecnica"
+)
+
+// TamanhoMaximoAnexo é o limite de cada arquivo (fotos de celular e pdfs de manual cabem com folga)
+const TamanhoMaximoAnexo = 10 << 20
+
+var mimesFoto = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+var mimesDocumento = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// ValidarAnexoEpi confere o tipo do anexo e detecta o mime pelo começo do arquivo (não confia na extensão
+// nem no Content-Type que o cliente mandou). Devolve o mime e a extensão usada no armazenamento
+func ValidarAnexoEpi(tipo string, cabecalho []byte) (string, string, error) {
+
+	var aceitos map[string]string
+	switch tipo {
+	case AnexoFoto:
+		aceitos = mimesFoto
+	case AnexoCertificadoCa, AnexoManual, AnexoFichaTecnica:
+		aceitos = mimesDocumento
+	default:
+		return "", "", fmt.Errorf("%w: tipo deve ser foto, certificado_ca, manual ou ficha_tecnica", ErrAnexoInvalido)
+	}
+
+	if len(cabecalho) == 0 {
+		return "", "", fmt.Errorf("%w: arquivo vazio", ErrAnexoInvalido)
+	}
+
+	mime := http.DetectContentType(cabecalho)
+	extensao, ok := aceitos[mime]
+	if !ok {
+		if tipo == AnexoFoto {
+			return "", "", fmt.Errorf("%w: a foto deve ser jpeg, png ou webp (recebido %s)", ErrAnexoInvalido, mime)
+		}
+		return "", "", fmt.Errorf("%w: o documento deve ser pdf, jpeg ou png (recebido %s)", ErrAnexoInvalido, mime)
+	}
+
+	return mime, extensao, nil
+}
@@ -0,0 +1,38 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidarAnexoEpi(t *testing.T) {
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	pdf := []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	mime, extensao, err := ValidarAnexoEpi(AnexoFoto, png)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", mime)
+	assert.Equal(t, ".png", extensao)
+
+	mime, extensao, err = ValidarAnexoEpi(AnexoCertificadoCa, pdf)
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", mime)
+	assert.Equal(t, ".pdf", extensao)
+
+	// pdf não serve como foto
+	_, _, err = ValidarAnexoEpi(AnexoFoto, pdf)
+	assert.ErrorIs(t, err, ErrAnexoInvalido)
+
+	// texto com extensão trocada continua sendo texto
+	_, _, err = ValidarAnexoEpi(AnexoManual, []byte("<html><script>alert(1)</script>"))
+	assert.ErrorIs(t, err, ErrAnexoInvalido)
+
+	_, _, err = ValidarAnexoEpi("contrato", pdf)
+	assert.ErrorIs(t, err, ErrAnexoInvalido)
+
+	_, _, err = ValidarAnexoEpi(AnexoFoto, nil)
+	assert.ErrorIs(t, err, ErrAnexoInvalido)
+}
@@ -0,0 +1,52 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCPF(t *testing.T) {
+
+	testCases := []struct {
+		nome     string
+		cpf      string
+		esperado bool
+	}{
+		// --- CENÁRIOS DE SUCESSO (expected: true) ---
+		{"Válido formatado", "123.456.789-09", true},
+		{"Válido sem formatação", "12345678909", true},
+		{"Válido com primeiro dígito zero", "529.982.247-25", true},
+		{"Válido outro exemplo limpo", "11144477735", true},
+
+		// --- CENÁRIOS DE FALHA (expected: false) ---
+		{"Inválido - Primeiro dígito errado", "123.456.789-19", false},
+		{"Inválido - Segundo dígito errado", "123.456.789-08", false},
+		{"Inválido - Faltando números (menor que 11)", "1234567890", false},
+		{"Inválido - Sobrando números (maior que 11)", "123456789090", false},
+		{"Inválido - String vazia", "", false},
+		{"Inválido - Contém letras", "123.ABC.789-09", false},
+
+		// --- CENÁRIOS DA "LISTA NEGRA" (expected: false) ---
+		{"Inválido - Tudo Zero", "000.000.000-00", false},
+		{"Inválido - Tudo Um", "11111111111", false},
+		{"Inválido - Tudo Nove", "99999999999", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.nome, func(t *testing.T) {
+
+			resultado := IsCPF(tc.cpf)
+
+			assert.Equal(t, tc.esperado, resultado, "Falhou no cenário: %s", tc.nome)
+		})
+	}
+}
+
+func TestMascararCPF(t *testing.T) {
+
+	assert.Equal(t, "***.456.789-**", MascararCPF("123.456.789-09"))
+	assert.Equal(t, "***.456.789-**", MascararCPF("12345678909"))
+	assert.Equal(t, "", MascararCPF(""))
+	assert.Equal(t, "123.456.789-09", FormatarCPF("12345678909"))
+}
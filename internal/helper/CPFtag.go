@@ -0,0 +1,80 @@
+package helper
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var naoDigito = regexp.MustCompile("[^0-9]")
+
+// SomenteDigitosCPF tira pontos, traço e espaços; o CPF é gravado assim no banco
+func SomenteDigitosCPF(cpf string) string {
+
+	return naoDigito.ReplaceAllString(cpf, "")
+}
+
+func IsCPF(cpf string) bool {
+
+	// 1. Remove caracteres não numéricos
+	cpf = SomenteDigitosCPF(cpf)
+
+	// 2. Valida tamanho
+	if len(cpf) != 11 {
+		return false
+	}
+
+	// 3. Elimina inválidos conhecidos (todos os dígitos iguais passam no cálculo)
+	if strings.Count(cpf, cpf[:1]) == 11 {
+		return false
+	}
+
+	// 4. Valida Dígitos: pesos 10..2 para o primeiro, 11..2 para o segundo
+	for tamanho := 9; tamanho <= 10; tamanho++ {
+
+		soma := 0
+		for i := 0; i < tamanho; i++ {
+			soma += int(cpf[i]-'0') * (tamanho + 1 - i)
+		}
+
+		resultado := soma * 10 % 11
+		if resultado == 10 {
+			resultado = 0
+		}
+
+		if resultado != int(cpf[tamanho]-'0') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MascararCPF esconde o CPF nas listagens (LGPD): só os dígitos do meio ficam visíveis, ***.456.789-**
+func MascararCPF(cpf string) string {
+
+	cpf = SomenteDigitosCPF(cpf)
+	if len(cpf) != 11 {
+		return ""
+	}
+
+	return "***." + cpf[3:6] + "." + cpf[6:9] + "-**"
+}
+
+// FormatarCPF devolve o CPF completo com pontuação, para quem tem acesso aos dados pessoais
+func FormatarCPF(cpf string) string {
+
+	cpf = SomenteDigitosCPF(cpf)
+	if len(cpf) != 11 {
+		return cpf
+	}
+
+	return cpf[0:3] + "." + cpf[3:6] + "." + cpf[6:9] + "-" + cpf[9:]
+}
+
+// ValidateCPF é a função que o Gin vai chamar
+func ValidateCPF(fl validator.FieldLevel) bool {
+
+	return IsCPF(fl.Field().String())
+}
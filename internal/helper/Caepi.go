@@ -0,0 +1,222 @@
+package helper
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// RegistroCaepi é uma linha do arquivo CAEPI já normalizada
+type RegistroCaepi struct {
+	CA             string
+	Situacao       string // VALIDO, VENCIDO, CANCELADO, SUSPENSO... (maiúsculo e sem acento)
+	Validade       time.Time
+	CnpjFabricante string
+	Fabricante     string
+	Equipamento    string
+	Descricao      string
+}
+
+// colunas do cabeçalho do CAEPI, já normalizadas, e os apelidos aceitos para cada uma
+var colunasCaepi = map[string][]string{
+	"ca":          {"nrregistroca", "registroca", "ca"},
+	"validade":    {"datavalidade", "validade"},
+	"situacao":    {"situacao"},
+	"cnpj":        {"cnpj"},
+	"fabricante":  {"razaosocial", "fabricante"},
+	"equipamento": {"nomeequipamento", "equipamento"},
+	"descricao":   {"descricaoequipamento", "descricao"},
+}
+
+var colunasCaepiObrigatorias = []string{"ca", "validade", "situacao", "fabricante", "equipamento"}
+
+// LerCaepi lê o arquivo do CAEPI do Ministério do Trabalho (o .txt separado por "|" ou o .zip em que
+// ele é publicado). O arquivo vem em latin-1; CA repetido (uma linha por laudo) vale só a primeira vez.
+// Linhas sem CA ou com data inválida são contadas em ignoradas e ficam de fora
+func LerCaepi(nomeArquivo string, r io.Reader) (registros []RegistroCaepi, ignoradas int, err error) {
+
+	conteudo, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch strings.ToLower(path.Ext(nomeArquivo)) {
+	case ".zip":
+		conteudo, err = extrairCaepi(conteudo)
+		if err != nil {
+			return nil, 0, err
+		}
+	case ".txt", ".csv":
+	default:
+		return nil, 0, fmt.Errorf("%w: envie o .txt ou o .zip do CAEPI", ErrArquivoCaepi)
+	}
+
+	conteudo = bytes.TrimPrefix(conteudo, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(conteudo) {
+		conteudo = latin1ParaUtf8(conteudo)
+	}
+
+	leitor := bufio.NewScanner(bytes.NewReader(conteudo))
+	leitor.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var posicao map[string]int
+	vistos := make(map[string]bool)
+
+	for leitor.Scan() {
+
+		linha := strings.TrimRight(leitor.Text(), "\r")
+		if strings.TrimSpace(linha) == "" {
+			continue
+		}
+		campos := strings.Split(linha, "|")
+
+		if posicao == nil {
+			posicao, err = cabecalhoCaepi(campos)
+			if err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+
+		campo := func(nome string) string {
+			i, ok := posicao[nome]
+			if !ok || i >= len(campos) {
+				return ""
+			}
+			return strings.Join(strings.Fields(campos[i]), " ")
+		}
+
+		ca := naoDigito.ReplaceAllString(campo("ca"), "")
+		if ca == "" {
+			ignoradas++
+			continue
+		}
+
+		if vistos[ca] {
+			continue
+		}
+
+		var validade time.Time
+		if v := campo("validade"); v != "" {
+			validade, err = time.Parse("02/01/2006", v)
+			if err != nil {
+				ignoradas++
+				continue
+			}
+		}
+
+		vistos[ca] = true
+		registros = append(registros, RegistroCaepi{
+			CA:             ca,
+			Situacao:       normalizarSituacaoCaepi(campo("situacao")),
+			Validade:       validade,
+			CnpjFabricante: naoDigito.ReplaceAllString(campo("cnpj"), ""),
+			Fabricante:     campo("fabricante"),
+			Equipamento:    campo("equipamento"),
+			Descricao:      campo("descricao"),
+		})
+	}
+
+	if err := leitor.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrArquivoCaepi, err)
+	}
+
+	if posicao == nil {
+		return nil, 0, fmt.Errorf("%w: arquivo vazio", ErrArquivoCaepi)
+	}
+
+	return registros, ignoradas, nil
+}
+
+func extrairCaepi(conteudo []byte) ([]byte, error) {
+
+	arquivo, err := zip.NewReader(bytes.NewReader(conteudo), int64(len(conteudo)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrArquivoCaepi, err)
+	}
+
+	for _, f := range arquivo.File {
+
+		ext := strings.ToLower(path.Ext(f.Name))
+		if ext != ".txt" && ext != ".csv" {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrArquivoCaepi, err)
+		}
+		defer rc.Close()
+
+		return io.ReadAll(rc)
+	}
+
+	return nil, fmt.Errorf("%w: o zip não tem o .txt do CAEPI", ErrArquivoCaepi)
+}
+
+func cabecalhoCaepi(campos []string) (map[string]int, error) {
+
+	normalizados := make(map[string]int, len(campos))
+	for i, c := range campos {
+		normalizados[chaveColunaCaepi(c)] = i
+	}
+
+	posicao := make(map[string]int, len(colunasCaepi))
+	for coluna, apelidos := range colunasCaepi {
+		for _, apelido := range apelidos {
+			if i, ok := normalizados[apelido]; ok {
+				posicao[coluna] = i
+				break
+			}
+		}
+	}
+
+	for _, coluna := range colunasCaepiObrigatorias {
+		if _, ok := posicao[coluna]; !ok {
+			return nil, fmt.Errorf("%w: coluna %s não encontrada no cabeçalho", ErrArquivoCaepi, coluna)
+		}
+	}
+
+	return posicao, nil
+}
+
+var semAcentoCaepi = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ü", "u", "ç", "c",
+)
+
+// chaveColunaCaepi: "#NR Registro CA" -> "nrregistroca"
+func chaveColunaCaepi(s string) string {
+
+	s = semAcentoCaepi.Replace(strings.ToLower(s))
+
+	var b strings.Builder
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func normalizarSituacaoCaepi(s string) string {
+
+	return strings.ToUpper(semAcentoCaepi.Replace(strings.ToLower(strings.TrimSpace(s))))
+}
+
+// latin1ParaUtf8: no latin-1 cada byte é o próprio code point
+func latin1ParaUtf8(b []byte) []byte {
+
+	var buf bytes.Buffer
+	buf.Grow(len(b) + len(b)/8)
+	for _, c := range b {
+		buf.WriteRune(rune(c))
+	}
+	return buf.Bytes()
+}
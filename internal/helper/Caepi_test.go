@@ -0,0 +1,72 @@
+package helper
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cabecalhoCaepiTeste = "#NRRegistroCA|DataValidade|Situacao|NRProcesso|CNPJ|RazaoSocial|Natureza|NomeEquipamento|DescricaoEquipamento|MarcaCA\n"
+
+func TestLerCaepi(t *testing.T) {
+
+	conteudo := cabecalhoCaepiTeste +
+		"12345|10/05/2030|VÁLIDO|1|12.345.678/0001-90|Luvas Brasil  Ltda|Nacional|LUVA|Luva de segurança nitrílica|etiqueta\n" +
+		"12345|10/05/2030|VÁLIDO|1|12.345.678/0001-90|Luvas Brasil Ltda|Nacional|LUVA|Luva (outro laudo)|etiqueta\n" +
+		"|10/05/2030|VÁLIDO|1|||Nacional|SEM CA||\n" +
+		"777|31/02/2030|VÁLIDO|1||Fabricante|Nacional|BOTA||\n" +
+		"888||CANCELADO|1||Fabricante|Nacional|CAPACETE||\n"
+
+	registros, ignoradas, err := LerCaepi("tgg_export_caepi.txt", strings.NewReader(conteudo))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, ignoradas)
+	require.Len(t, registros, 2)
+
+	assert.Equal(t, RegistroCaepi{
+		CA:             "12345",
+		Situacao:       "VALIDO",
+		Validade:       time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC),
+		CnpjFabricante: "12345678000190",
+		Fabricante:     "Luvas Brasil Ltda",
+		Equipamento:    "LUVA",
+		Descricao:      "Luva de segurança nitrílica",
+	}, registros[0])
+
+	assert.Equal(t, "888", registros[1].CA)
+	assert.Equal(t, "CANCELADO", registros[1].Situacao)
+	assert.True(t, registros[1].Validade.IsZero())
+}
+
+func TestLerCaepiLatin1NoZip(t *testing.T) {
+
+	// "Proteção" em latin-1
+	linha := []byte("1|01/01/2031|VALIDO|1||Fabricante|Nacional|Prote\xe7\xe3o auditiva||\n")
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("tgg_export_caepi.txt")
+	require.NoError(t, err)
+	_, err = w.Write(append([]byte(cabecalhoCaepiTeste), linha...))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	registros, _, err := LerCaepi("caepi.ZIP", &buf)
+	require.NoError(t, err)
+	require.Len(t, registros, 1)
+	assert.Equal(t, "Proteção auditiva", registros[0].Equipamento)
+}
+
+func TestLerCaepiInvalido(t *testing.T) {
+
+	_, _, err := LerCaepi("caepi.pdf", strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrArquivoCaepi)
+
+	_, _, err = LerCaepi("caepi.txt", strings.NewReader("ca|nome\n1|x\n"))
+	assert.ErrorIs(t, err, ErrArquivoCaepi)
+}
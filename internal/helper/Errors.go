@@ -21,6 +21,36 @@ var (
 	ErrDataIgual           = errors.New("data de fabricacao e validade não podem ser iguais")
 	ErrDataMenor           = errors.New("A data de entrada não pode ser menor que hoje")
 	ErrDataMenorValidade   = errors.New("A data de validade não pode ser menor que a data de fabricação")
+	ErrRequisitoAlvo       = errors.New("informe apenas um epi ou um tipo de protecao para o requisito")
+	ErrLoteSemDestino      = errors.New("informe um departamento, uma funcao ou a lista de funcionarios")
+	ErrStatusSolicitacao   = errors.New("a solicitação não está no status esperado para esta operação")
+	ErrReservaInvalida     = errors.New("reserva não encontrada, vencida, já consumida ou de outro epi/tamanho/funcionario")
+	ErrReservaExpirada     = errors.New("a data de expiração da reserva não pode ser menor que hoje")
+	ErrCredenciaisPortal   = errors.New("matrícula ou PIN inválidos")
+	ErrPinBloqueado        = errors.New("acesso bloqueado por excesso de tentativas, tente novamente mais tarde")
+	ErrDestinoMotivo       = errors.New("o motivo deve ter um único destino: estoque, descarte ou higienização")
+	ErrDevolucaoSemPosse   = errors.New("o funcionario não tem em posse a quantidade devolvida deste epi/tamanho")
+	ErrOrdemEncerrada      = errors.New("a ordem de manutenção já foi encerrada")
+	ErrFormatoPlanilha     = errors.New("formato de planilha não suportado, envie .csv ou .xlsx")
+	ErrImportacaoInvalida  = errors.New("a planilha tem linhas inválidas, nada foi importado")
+	ErrPosseDesligamento   = errors.New("o funcionario ainda tem epis em posse: registre a devolução ou a baixa antes de desligar")
+	ErrDataDesligamento    = errors.New("a data de desligamento não pode ser anterior à admissão")
+	ErrCpfDuplicado        = errors.New("cpf ja cadastrado para outro funcionario")
+	ErrPeriodoInvalido     = errors.New("periodo invalido: informe as duas datas e a final não pode ser anterior à inicial")
+	ErrExportacaoEsocial   = errors.New("há funcionarios com dados incompletos para o S-2240, nada foi exportado")
+	ErrXsdEsocial          = errors.New("não foi possível validar o S-2240 contra o xsd do eSocial, nada foi exportado")
+	ErrSemTreinamento      = errors.New("o funcionario não tem treinamento válido para o epi entregue")
+	ErrTreinamentoAlvo     = errors.New("informe apenas um epi ou um tipo de protecao para o treinamento")
+	ErrValidadeTreinamento = errors.New("a validade do treinamento não pode ser anterior à data de realização")
+	ErrTamanhoSemPerfil    = errors.New("o funcionario não tem tamanho no perfil para o epi, informe o id_tamanho")
+	ErrPerfilTamanhoAlvo   = errors.New("informe apenas um epi ou um tipo de protecao para o tamanho")
+	ErrArquivoCaepi        = errors.New("arquivo do CAEPI inválido")
+	ErrCaCancelado         = errors.New("o CA está cancelado ou suspenso no cadastro do Ministério do Trabalho")
+	ErrDataCargo           = errors.New("a data da transferência não pode ser futura nem anterior ao início do cargo atual")
+	ErrAnexoInvalido       = errors.New("anexo inválido")
+	ErrOrdemConcluida      = errors.New("a devolução tem ordem de higienização/inspeção já concluída e não pode mais ser cancelada")
+	ErrSemLoteDevolucao    = errors.New("não há lote ativo deste epi/tamanho para receber o item de volta no estoque")
+	ErrFuncionarioInativo  = errors.New("funcionario desligado ou não encontrado")
 )
 
 // Códigos de Erro Oficiais do PostgreSQL
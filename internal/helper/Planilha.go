@@ -0,0 +1,244 @@
+package helper
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LerPlanilha devolve as linhas de um .csv ou .xlsx (só a primeira aba), sem nenhuma interpretação do conteúdo.
+// O xlsx é lido direto do zip/xml para não puxar uma biblioteca inteira só para a importação.
+// Linhas vazias voltam como []string{}, então o índice + 1 é sempre o número da linha no arquivo
+func LerPlanilha(nomeArquivo string, r io.Reader) ([][]string, error) {
+
+	conteudo, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(path.Ext(nomeArquivo)) {
+	case ".csv":
+		return lerCsv(conteudo)
+	case ".xlsx":
+		return lerXlsx(conteudo)
+	}
+
+	return nil, ErrFormatoPlanilha
+}
+
+func lerCsv(conteudo []byte) ([][]string, error) {
+
+	conteudo = bytes.TrimPrefix(conteudo, []byte("\xef\xbb\xbf"))
+
+	// o excel em pt-BR exporta csv separado por ponto e vírgula
+	primeiraLinha, _, _ := bytes.Cut(conteudo, []byte("\n"))
+	separador := ','
+	if bytes.Count(primeiraLinha, []byte(";")) > bytes.Count(primeiraLinha, []byte(",")) {
+		separador = ';'
+	}
+
+	leitor := csv.NewReader(bytes.NewReader(conteudo))
+	leitor.Comma = separador
+	leitor.FieldsPerRecord = -1
+	leitor.TrimLeadingSpace = true
+
+	var linhas [][]string
+	for {
+		registro, err := leitor.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv invalido: %w", err)
+		}
+
+		// o leitor pula linhas em branco; a posição do registro mantém a numeração do arquivo
+		numero, _ := leitor.FieldPos(0)
+		for len(linhas) < numero-1 {
+			linhas = append(linhas, []string{})
+		}
+		linhas = append(linhas, registro)
+	}
+
+	return linhas, nil
+}
+
+type xlsxSharedStrings struct {
+	Itens []struct {
+		Texto  string `xml:"t"`
+		Partes []struct {
+			Texto string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+// limites do xlsx: um zip de poucos KB pode descompactar em gigabytes de xml
+const (
+	tamanhoMaximoXmlXlsx = 50 << 20
+	maximoLinhasXlsx     = 1048576 // o próprio excel não passa disso
+	maximoColunasXlsx    = 16384   // coluna XFD
+)
+
+type xlsxPlanilha struct {
+	Linhas []struct {
+		Numero  int `xml:"r,attr"`
+		Celulas []struct {
+			Ref    string `xml:"r,attr"`
+			Tipo   string `xml:"t,attr"`
+			Valor  string `xml:"v"`
+			Inline struct {
+				Texto string `xml:"t"`
+			} `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func lerXlsx(conteudo []byte) ([][]string, error) {
+
+	arquivo, err := zip.NewReader(bytes.NewReader(conteudo), int64(len(conteudo)))
+	if err != nil {
+		return nil, fmt.Errorf("xlsx invalido: %w", err)
+	}
+
+	var compartilhados []string
+	var abas []*zip.File
+
+	for _, f := range arquivo.File {
+
+		switch {
+		case f.Name == "xl/sharedStrings.xml":
+			var sst xlsxSharedStrings
+			if err := decodificarXml(f, &sst); err != nil {
+				return nil, err
+			}
+			for _, si := range sst.Itens {
+				texto := si.Texto
+				for _, parte := range si.Partes {
+					texto += parte.Texto
+				}
+				compartilhados = append(compartilhados, texto)
+			}
+		case strings.HasPrefix(f.Name, "xl/worksheets/sheet") && strings.HasSuffix(f.Name, ".xml"):
+			abas = append(abas, f)
+		}
+	}
+
+	if len(abas) == 0 {
+		return nil, errors.New("xlsx invalido: nenhuma aba encontrada")
+	}
+
+	// sheet1.xml é a primeira aba; sheet10 não pode vir antes de sheet2
+	sort.Slice(abas, func(i, j int) bool {
+		return numeroDaAba(abas[i].Name) < numeroDaAba(abas[j].Name)
+	})
+
+	var aba xlsxPlanilha
+	if err := decodificarXml(abas[0], &aba); err != nil {
+		return nil, err
+	}
+
+	linhas := make([][]string, 0, len(aba.Linhas))
+	for _, row := range aba.Linhas {
+
+		// linhas vazias não aparecem no xml; sem completar, o número da linha no erro sairia errado
+		if row.Numero > maximoLinhasXlsx {
+			return nil, fmt.Errorf("xlsx invalido: linha %d passa do limite de %d linhas", row.Numero, maximoLinhasXlsx)
+		}
+		for len(linhas) < row.Numero-1 {
+			linhas = append(linhas, []string{})
+		}
+
+		linha := []string{}
+		for i, c := range row.Celulas {
+
+			// células vazias não aparecem no xml, a posição vem da referência (B3 -> coluna 1)
+			coluna := i
+			if c.Ref != "" {
+				var err error
+				if coluna, err = indiceColuna(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(linha) <= coluna {
+				linha = append(linha, "")
+			}
+
+			switch c.Tipo {
+			case "s":
+				idx, err := strconv.Atoi(c.Valor)
+				if err != nil || idx < 0 || idx >= len(compartilhados) {
+					return nil, fmt.Errorf("xlsx invalido: texto compartilhado %q inexistente", c.Valor)
+				}
+				linha[coluna] = compartilhados[idx]
+			case "inlineStr":
+				linha[coluna] = c.Inline.Texto
+			default:
+				linha[coluna] = c.Valor
+			}
+		}
+
+		linhas = append(linhas, linha)
+	}
+
+	return linhas, nil
+}
+
+func decodificarXml(f *zip.File, destino any) error {
+
+	if f.UncompressedSize64 > tamanhoMaximoXmlXlsx {
+		return fmt.Errorf("xlsx invalido: %s passa de %dMB descompactado", f.Name, tamanhoMaximoXmlXlsx>>20)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("xlsx invalido: %w", err)
+	}
+	defer rc.Close()
+
+	// o tamanho no cabeçalho do zip pode mentir, então o limite vale também na leitura
+	limitado := &io.LimitedReader{R: rc, N: tamanhoMaximoXmlXlsx + 1}
+	if err := xml.NewDecoder(limitado).Decode(destino); err != nil {
+		if limitado.N <= 0 {
+			return fmt.Errorf("xlsx invalido: %s passa de %dMB descompactado", f.Name, tamanhoMaximoXmlXlsx>>20)
+		}
+		return fmt.Errorf("xlsx invalido: %w", err)
+	}
+
+	return nil
+}
+
+func numeroDaAba(nome string) int {
+
+	n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(nome, "xl/worksheets/sheet"), ".xml"))
+	return n
+}
+
+// indiceColuna tira a coluna da referência da célula (A1 -> 0). Referência sem letra ou depois da XFD
+// é recusada: viraria índice negativo ou uma linha com milhões de colunas
+func indiceColuna(ref string) (int, error) {
+
+	coluna := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		coluna = coluna*26 + int(r-'A'+1)
+		if coluna > maximoColunasXlsx {
+			return 0, fmt.Errorf("%w: a célula %q passa da última coluna do excel (XFD)", ErrFormatoPlanilha, ref)
+		}
+	}
+
+	if coluna == 0 {
+		return 0, fmt.Errorf("%w: referência de célula %q inválida", ErrFormatoPlanilha, ref)
+	}
+
+	return coluna - 1, nil
+}
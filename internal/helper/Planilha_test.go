@@ -0,0 +1,102 @@
+package helper
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLerPlanilhaCsv(t *testing.T) {
+
+	testCases := []struct {
+		nome     string
+		conteudo string
+		esperado [][]string
+	}{
+		{"Separado por vírgula", "nome,matricula\nJoão,1234567\n", [][]string{{"nome", "matricula"}, {"João", "1234567"}}},
+		{"Separado por ponto e vírgula (excel pt-BR)", "nome;matricula\nSilva, Ana;7654321\n", [][]string{{"nome", "matricula"}, {"Silva, Ana", "7654321"}}},
+		{"Com BOM no inicio", "\xef\xbb\xbfnome,matricula\nAna,1111111\n", [][]string{{"nome", "matricula"}, {"Ana", "1111111"}}},
+		{"Linha em branco mantém a numeração", "nome,matricula\n\nAna,1111111\n", [][]string{{"nome", "matricula"}, {}, {"Ana", "1111111"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.nome, func(t *testing.T) {
+
+			linhas, err := LerPlanilha("funcionarios.CSV", strings.NewReader(tc.conteudo))
+			require.NoError(t, err)
+			assert.Equal(t, tc.esperado, linhas)
+		})
+	}
+}
+
+func TestLerPlanilhaXlsx(t *testing.T) {
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	arquivos := map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>nome</t></si><si><r><t>Jo</t></r><r><t>ão</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="inlineStr"><is><t>matricula</t></is></c></row>
+			<row r="2"><c r="A2" t="s"><v>1</v></c><c r="C2"><v>1234567</v></c></row>
+			<row r="4"><c r="A4" t="inlineStr"><is><t>Ana</t></is></c></row>
+		</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData><row r="1"><c r="A1"><v>9</v></c></row></sheetData></worksheet>`,
+	}
+	for nome, conteudo := range arquivos {
+		w, err := zw.Create(nome)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(conteudo))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	linhas, err := LerPlanilha("funcionarios.xlsx", &buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"nome", "matricula"},
+		{"João", "", "1234567"},
+		{}, // linha 3 vazia não vem no xml, mas ocupa a posição
+		{"Ana"},
+	}, linhas)
+}
+
+func TestLerPlanilhaXlsxReferenciaInvalida(t *testing.T) {
+
+	testCases := []struct {
+		nome string
+		ref  string
+	}{
+		{"Coluna em minúscula", "a1"},
+		{"Sem coluna", "1"},
+		{"Depois da coluna XFD", "XFE1"},
+		{"Coluna gigante", "ZZZZZZZZZ1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.nome, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			zw := zip.NewWriter(&buf)
+			w, err := zw.Create("xl/worksheets/sheet1.xml")
+			require.NoError(t, err)
+			_, err = w.Write([]byte(`<worksheet><sheetData><row r="1"><c r="` + tc.ref + `"><v>1</v></c></row></sheetData></worksheet>`))
+			require.NoError(t, err)
+			require.NoError(t, zw.Close())
+
+			_, err = LerPlanilha("funcionarios.xlsx", &buf)
+			assert.ErrorIs(t, err, ErrFormatoPlanilha)
+		})
+	}
+}
+
+func TestLerPlanilhaFormatoInvalido(t *testing.T) {
+
+	_, err := LerPlanilha("funcionarios.xls", strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrFormatoPlanilha)
+}
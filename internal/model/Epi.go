@@ -1,13 +1,17 @@
 package model
 
-import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+import (
+	"time"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+)
 
 type EpiInserir struct {
 	Nome           string         `json:"nome" binding:"required"`
-	Fabricante     string         `json:"fabricante" binding:"required,max=50"`
+	Fabricante     string         `json:"fabricante" binding:"omitempty,max=50"` // vazio = vem do catálogo do CAEPI
 	CA             string         `json:"ca" binding:"required,numeric,min=1,max=6"`
 	Descricao      string         `json:"descricao" binding:"lte=250"`
-	DataValidadeCa configs.DataBr `json:"data_validade_ca" binding:"required"`
+	DataValidadeCa configs.DataBr `json:"data_validade_ca"` // vazio = vem do catálogo do CAEPI
 	Idtamanho      []int          `json:"id_tamanho" binding:"required,min=1"`
 	IDprotecao     int            `json:"id_protecao" binding:"required,numeric"`
 	AlertaMinimo   int            `json:"alerta_minimo" binding:"required,gte=0"`
@@ -22,6 +26,7 @@ type EpiDto struct {
 	Descricao      string          `json:"descricao"`
 	DataValidadeCa configs.DataBr  `json:"data_validadeCa"`
 	Protecao       TipoProtecaoDto `json:"protecao"`
+	FotoPrincipal  *AnexoEpiDto    `json:"foto_principal"`
 }
 
 type UpdateEpiInput struct {
@@ -32,3 +37,20 @@ type UpdateEpiInput struct {
 	ValidadeCa *configs.DataBr `json:"validadeCa"`
 	Tamanhos   []int32         `json:"tamanhos"` // Novos IDs de tamanhos
 }
+
+// AnexoEpiDto: foto, certificado do CA, manual ou ficha técnica do epi. O arquivo é baixado pela Url
+type AnexoEpiDto struct {
+	ID          int        `json:"id"`
+	Tipo        string     `json:"tipo"`
+	NomeArquivo string     `json:"nome_arquivo"`
+	Mime        string     `json:"mime"`
+	Tamanho     int64      `json:"tamanho,omitempty"`
+	Principal   bool       `json:"principal"`
+	CriadoEm    *time.Time `json:"criado_em,omitempty"`
+	Url         string     `json:"url"`
+}
+
+type AnexoEpiInserir struct {
+	Tipo      string `form:"tipo" binding:"required,oneof=foto certificado_ca manual ficha_tecnica"`
+	Principal bool   `form:"principal"`
+}
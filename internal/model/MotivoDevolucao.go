@@ -1,12 +1,20 @@
 package model
 
-
+// MotivoDevolucao define também o destino do epi devolvido: só um entre estoque, descarte e higienização.
+// Sem nenhum destino marcado, o epi volta para o estoque
 type MotivoDevolucao struct {
-	Motivo string `json:"motivo" binding:"required"`
+	Motivo        string `json:"motivo" binding:"required,max=50"`
+	VoltaEstoque  bool   `json:"volta_estoque"`
+	Descarte      bool   `json:"descarte"`
+	Higienizacao  bool   `json:"higienizacao"`
+	ExigeInspecao bool   `json:"exige_inspecao"` // segura o item numa ordem de inspeção; a conclusão decide entre estoque e descarte
 }
 
 type MotivoDevolucaoEpiDto struct {
-	Id     int             `json:"id"`
-	Motivo string `json:"motivo"`
+	Id            int    `json:"id"`
+	Motivo        string `json:"motivo"`
+	VoltaEstoque  bool   `json:"volta_estoque"`
+	Descarte      bool   `json:"descarte"`
+	Higienizacao  bool   `json:"higienizacao"`
+	ExigeInspecao bool   `json:"exige_inspecao"`
 }
-
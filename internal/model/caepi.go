@@ -0,0 +1,34 @@
+package model
+
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
+type ResultadoImportacaoCaepi struct {
+	ID              int    `json:"id"`
+	Arquivo         string `json:"arquivo"`
+	Total           int    `json:"total"`
+	Novos           int    `json:"novos"`
+	Alterados       int    `json:"alterados"`        // situação ou validade diferente da importação anterior
+	Ignoradas       int    `json:"ignoradas"`        // linhas sem CA ou com data inválida
+	EpisSinalizados int64  `json:"epis_sinalizados"` // epis dos tenants marcados pelo job logo após a importação
+}
+
+type CatalogoCaDto struct {
+	CA             string          `json:"ca"`
+	Situacao       string          `json:"situacao"`
+	Validade       *configs.DataBr `json:"validade"`
+	CnpjFabricante string          `json:"cnpj_fabricante"`
+	Fabricante     string          `json:"fabricante"`
+	Equipamento    string          `json:"equipamento"`
+	Descricao      string          `json:"descricao"`
+}
+
+type AlertaCaDto struct {
+	ID               int             `json:"id"`
+	Epi              EpiResumoDto    `json:"epi"`
+	Tipo             string          `json:"tipo"` // "cancelado" ou "renovado"
+	Situacao         string          `json:"situacao"`
+	ValidadeAnterior *configs.DataBr `json:"validade_anterior"`
+	ValidadeNova     *configs.DataBr `json:"validade_nova"`
+	CriadoEm         configs.DataBr  `json:"criado_em"`
+	Resolvido        bool            `json:"resolvido"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/shopspring/decimal"
+)
+
+type ConsumoProtecaoDto struct {
+	Protecao     TipoProtecaoDto `json:"protecao"`
+	Quantidade   int             `json:"quantidade"`
+	ValorTotal   decimal.Decimal `json:"valor_total"`
+	Funcionarios int             `json:"funcionarios"` // funcionarios diferentes que receberam no período
+}
+
+type ConsumoCategoriaDto struct {
+	Categoria     *CategoriaProtecaoDto `json:"categoria"` // null = tipos de proteção ainda sem categoria
+	Quantidade    int                   `json:"quantidade"`
+	ValorTotal    decimal.Decimal       `json:"valor_total"`
+	Subcategorias []ConsumoProtecaoDto  `json:"subcategorias"`
+}
+
+type RelatorioConsumoDto struct {
+	DataInicio configs.DataBr        `json:"data_inicio"`
+	DataFim    configs.DataBr        `json:"data_fim"`
+	Quantidade int                   `json:"quantidade"`
+	ValorTotal decimal.Decimal       `json:"valor_total"`
+	Categorias []ConsumoCategoriaDto `json:"categorias"`
+}
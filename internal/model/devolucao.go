@@ -2,6 +2,14 @@ package model
 
 import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
 
+// destino aplicado ao item devolvido, gravado na devolução
+const (
+	DestinoEstoque      = "estoque"
+	DestinoDescarte     = "descarte"
+	DestinoHigienizacao = "higienizacao"
+	DestinoInspecao     = "inspecao" // fica fora do estoque até a ordem de inspeção ser concluída
+)
+
 type DevolucaoInserir struct {
 	IdFuncionario       int            `json:"id_funcionario" binding:"required"`
 	IdEpi               int            `json:"id_epi" binding:"required"`
@@ -12,7 +20,7 @@ type DevolucaoInserir struct {
 	NovaQuantidade      *int           `json:"nova_quantidade"`
 	IdEpiNovo           *int           `json:"id_novo_epi" `
 	IdTamanhoNovo       *int           `json:"tamanhoEpi_novo"`
-	Troca               bool           `json:"É_troca" binding:"required"`
+	Troca               bool           `json:"É_troca"` // sem required: o validator recusaria o false da devolução simples
 	AssinaturaDigital   string         `json:"assinatura_digital" binding:"required"`
 	IdUser              int            `json:"usuario" binding:"required"`
 }
@@ -30,3 +38,49 @@ type DevolucaoDto struct {
 	Tamanho        *TamanhoDto `json:"tamanho"`
 	NovaQuantidade *int        `json:"quantidade_nova"`
 }
+
+// ItemDevolucaoInserir é uma linha do documento de devolução; Troca vem preenchida quando o item é trocado
+type ItemDevolucaoInserir struct {
+	IdEpi      int              `json:"id_epi" binding:"required"`
+	IdTamanho  int              `json:"id_tamanho" binding:"required"`
+	IdMotivo   int              `json:"id_motivo" binding:"required"`
+	Quantidade int              `json:"quantidade" binding:"required,numeric,gt=0"`
+	Troca      *ItemParaInserir `json:"troca"`
+}
+
+// DevolucaoDocumentoInserir devolve vários itens com uma só assinatura (ex: kit inteiro no desligamento)
+type DevolucaoDocumentoInserir struct {
+	IdFuncionario     int                    `json:"id_funcionario" binding:"required"`
+	DataDevolucao     configs.DataBr         `json:"data_devolucao" binding:"required"`
+	AssinaturaDigital string                 `json:"assinatura_digital" binding:"required"`
+	Itens             []ItemDevolucaoInserir `json:"itens" binding:"required,min=1,dive"`
+}
+
+type ItemTrocaDto struct {
+	IdEpi      int    `json:"id_epi"`
+	Epi        string `json:"epi"`
+	IdTamanho  int    `json:"id_tamanho"`
+	Tamanho    string `json:"tamanho"`
+	Quantidade int    `json:"quantidade"`
+}
+
+type ItemDevolucaoDocumentoDto struct {
+	IdDevolucao int                   `json:"id_devolucao"`
+	IdEpi       int                   `json:"id_epi"`
+	Epi         string                `json:"epi"`
+	IdTamanho   int                   `json:"id_tamanho"`
+	Tamanho     string                `json:"tamanho"`
+	Motivo      MotivoDevolucaoEpiDto `json:"motivo"`
+	Quantidade  int                   `json:"quantidade"`
+	Troca       *ItemTrocaDto         `json:"troca"`
+}
+
+type DevolucaoDocumentoDto struct {
+	Id             int                         `json:"id"`
+	Funcionario    Funcionario_Dto             `json:"funcionario"`
+	DataDevolucao  configs.DataBr              `json:"data_devolucao"`
+	IdEntregaTroca *int                        `json:"id_entrega_troca"`
+	CriadoEm       configs.DataBr              `json:"criado_em"`
+	CanceladaEm    *configs.DataBr             `json:"cancelada_em"`
+	Itens          []ItemDevolucaoDocumentoDto `json:"itens"`
+}
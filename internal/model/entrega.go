@@ -9,6 +9,7 @@ type ItemParaInserir struct {
 	ID_epi     int64 `json:"id_epi" binding:"required"`
 	ID_tamanho int64 `json:"id_tamanho" binding:"required"`
 	Quantidade int   `json:"quantidade" binding:"required,numeric,gt=0"`
+	IdReserva  *int  `json:"id_reserva"` // opcional: a entrega atende essa reserva, conferida no service
 }
 
 type EntregaParaInserir struct {
@@ -28,10 +29,47 @@ type ItemEntregueDto struct {
 }
 
 type EntregaDto struct {
-	Id                 int64             `json:"id"`
-	Id_user            int               `json:"id_user"`
-	Funcionario        Funcionario_Dto   `json:"funcionario"`
-	Data_entrega       configs.DataBr    `json:"data_entrega"`
-	Assinatura_Digital string            `json:"assinatura_digital"`
-	Itens              []ItemEntregueDto `json:"itens"`
+	Id                   int64             `json:"id"`
+	Id_user              int               `json:"id_user"`
+	Funcionario          Funcionario_Dto   `json:"funcionario"`
+	Data_entrega         configs.DataBr    `json:"data_entrega"`
+	Assinatura_Digital   string            `json:"assinatura_digital"`
+	Itens                []ItemEntregueDto `json:"itens"`
+	PendenciaTreinamento bool              `json:"pendencia_treinamento"` // entregue sem treinamento válido, com a regra da empresa em "avisar"
+}
+
+// EntregaLoteInserir entrega os mesmos itens para varios funcionarios de uma vez.
+// O destino é a lista de ids OU o departamento/funcao (que podem ser combinados)
+type EntregaLoteInserir struct {
+	IdDepartamento     *int              `json:"id_departamento"`
+	IdFuncao           *int              `json:"id_funcao"`
+	IdsFuncionarios    []int64           `json:"ids_funcionarios"`
+	Id_user            int               `json:"id_user" binding:"required,numeric"`
+	Data_entrega       configs.DataBr    `json:"data_entrega" binding:"required"`
+	Assinatura_Digital string            `json:"assinatura_digital" binding:"required"`
+	TudoOuNada         bool              `json:"tudo_ou_nada"`
+	Itens              []ItemLoteInserir `json:"itens" binding:"required,min=1,dive"`
+}
+
+// ItemLoteInserir sem id_tamanho usa o tamanho do perfil de cada funcionario
+type ItemLoteInserir struct {
+	ID_epi     int64 `json:"id_epi" binding:"required"`
+	ID_tamanho int64 `json:"id_tamanho"`
+	Quantidade int   `json:"quantidade" binding:"required,numeric,gt=0"`
+	IdReserva  *int  `json:"id_reserva"`
+}
+
+type ResultadoEntregaFuncionario struct {
+	IdFuncionario        int64  `json:"id_funcionario"`
+	Sucesso              bool   `json:"sucesso"`
+	Erro                 string `json:"erro,omitempty"`
+	PendenciaTreinamento bool   `json:"pendencia_treinamento,omitempty"` // entregue sem treinamento válido, com a regra em "avisar"
+}
+
+type ResultadoEntregaLote struct {
+	Total      int                           `json:"total"`
+	Sucessos   int                           `json:"sucessos"`
+	Falhas     int                           `json:"falhas"`
+	Efetivado  bool                          `json:"efetivado"`
+	Resultados []ResultadoEntregaFuncionario `json:"resultados"`
 }
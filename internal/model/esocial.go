@@ -0,0 +1,33 @@
+package model
+
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
+// FuncaoEsocial completa a função com o que o S-2240 exige e o cadastro não tem
+type FuncaoEsocial struct {
+	CodAgenteNocivo    string `json:"cod_agente_nocivo" binding:"required,max=10"` // tabela 24 do eSocial, ex: 01.01.001
+	DescricaoAtividade string `json:"descricao_atividade" binding:"required,max=999"`
+}
+
+// ResponsavelRegistroAmbiental é o profissional (engenheiro/médico do trabalho) que assina os registros do S-2240
+type ResponsavelRegistroAmbiental struct {
+	Cpf   string `json:"cpf" binding:"required,cpf"`
+	IdeOC int    `json:"ide_oc" binding:"required,oneof=1 4 9"` // 1 = CRM, 4 = CREA, 9 = outros
+	DscOC string `json:"dsc_oc" binding:"required_if=IdeOC 9,max=20"`
+	NrOC  string `json:"nr_oc" binding:"required,max=14"`
+	UfOC  string `json:"uf_oc" binding:"required,len=2"`
+}
+
+type ExportacaoS2240 struct {
+	DataInicio   configs.DataBr               `json:"data_inicio"`
+	DataFim      configs.DataBr               `json:"data_fim"`
+	Funcionarios []int                        `json:"funcionarios"`                          // vazio = todos que receberam epi no período
+	Ambiente     int                          `json:"ambiente" binding:"required,oneof=1 2"` // 1 = produção, 2 = produção restrita
+	Responsavel  ResponsavelRegistroAmbiental `json:"responsavel" binding:"required"`
+}
+
+type ErroExportacaoS2240 struct {
+	IdFuncionario int      `json:"id_funcionario"`
+	Nome          string   `json:"nome"`
+	Matricula     string   `json:"matricula"`
+	Erros         []string `json:"erros"`
+}
@@ -1,10 +1,17 @@
 package model
 
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
 type FuncionarioINserir struct {
 	Nome            string `json:"nome" binding:"required,min=3,max=150"`
 	Matricula       string `json:"matricula" binding:"required,min=7,max=7"`
 	ID_departamento int   `json:"id_departamento" binding:"required,min=1"`
 	ID_funcao       int   `json:"id_funcao"  binding:"required,min=1"`
+	DataAdmissao    configs.DataBr `json:"data_admissao"` // vazio = data do cadastro
+	Cpf             string         `json:"cpf" binding:"omitempty,cpf"`
+	DataNascimento  configs.DataBr `json:"data_nascimento"`
+	Email           string         `json:"email" binding:"omitempty,email,max=150"`
+	Telefone        string         `json:"telefone" binding:"omitempty,max=20"`
 }
 
 type Funcionario_Dto struct {
@@ -12,10 +19,73 @@ type Funcionario_Dto struct {
 	Nome         string          `json:"nome"`
 	Matricula    string             `json:"matricula"`
 	Funcao       FuncaoDto       `json:"funcao"`
+	Ativo              bool            `json:"ativo"`
+	DataAdmissao       *configs.DataBr `json:"data_admissao"`
+	DataDesligamento   *configs.DataBr `json:"data_desligamento"`
+	MotivoDesligamento string          `json:"motivo_desligamento,omitempty"`
+	Cpf                string          `json:"cpf,omitempty"` // sempre mascarado, o completo só em /dados-pessoais
 }
 
 type UpdateFuncionarioRequest struct {
     Nome           *string `json:"nome"`            // Ponteiro! Se for nil, não atualiza
     IdDepartamento *int    `json:"id_departamento"` // Ponteiro!
     IdFuncao       *int    `json:"id_funcao"`       // Ponteiro!
-}
\ No newline at end of file
+    DataEfetiva    configs.DataBr `json:"data_efetiva"` // data da transferência, vazio = hoje
+    Cpf            *string `json:"cpf" binding:"omitempty,cpf"`
+    DataNascimento *configs.DataBr `json:"data_nascimento"`
+    Email          *string `json:"email" binding:"omitempty,email,max=150"`
+    Telefone       *string `json:"telefone" binding:"omitempty,max=20"`
+}
+type ImportacaoFuncionarioOpcoes struct {
+	DryRun         bool `form:"dry_run"`         // só valida e devolve o relatório, sem gravar nada
+	CriarCadastros bool `form:"criar_cadastros"` // cria departamentos/funções que não existirem
+}
+
+type ErroLinhaImportacao struct {
+	Linha     int      `json:"linha"` // número da linha na planilha, contando o cabeçalho
+	Matricula string   `json:"matricula"`
+	Erros     []string `json:"erros"`
+}
+
+type ResultadoImportacaoFuncionarios struct {
+	DryRun               bool                  `json:"dry_run"`
+	TotalLinhas          int                   `json:"total_linhas"`
+	Validas              int                   `json:"validas"`
+	Importados           int                   `json:"importados"`
+	DepartamentosCriados []string              `json:"departamentos_criados"`
+	FuncoesCriadas       []string              `json:"funcoes_criadas"`
+	Erros                []ErroLinhaImportacao `json:"erros"`
+}
+
+// BaixaPosseInserir tira da posse um epi que não vai voltar, sem passar pelo estoque
+type BaixaPosseInserir struct {
+	IdEpi      int    `json:"id_epi" binding:"required,min=1"`
+	IdTamanho  int    `json:"id_tamanho" binding:"required,min=1"`
+	Quantidade int    `json:"quantidade" binding:"required,min=1"`
+	Motivo     string `json:"motivo" binding:"required,max=255"`
+}
+
+type DesligamentoFuncionario struct {
+	DataDesligamento configs.DataBr      `json:"data_desligamento"` // vazio = hoje
+	Motivo           string              `json:"motivo" binding:"max=255"`
+	Baixas           []BaixaPosseInserir `json:"baixas" binding:"dive"`
+}
+
+// CargoHistoricoDto é um período do funcionário numa função/departamento; DataFim nil = cargo atual
+type CargoHistoricoDto struct {
+	Funcao     FuncaoDto       `json:"funcao"`
+	DataInicio configs.DataBr  `json:"data_inicio"`
+	DataFim    *configs.DataBr `json:"data_fim"`
+}
+
+// DadosPessoaisFuncionarioDto traz o CPF completo e os contatos; cada consulta fica registrada (LGPD)
+type DadosPessoaisFuncionarioDto struct {
+	ID             int             `json:"id"`
+	Nome           string          `json:"nome"`
+	Matricula      string          `json:"matricula"`
+	Cpf            string          `json:"cpf"`
+	DataNascimento *configs.DataBr `json:"data_nascimento"`
+	DataAdmissao   *configs.DataBr `json:"data_admissao"`
+	Email          string          `json:"email"`
+	Telefone       string          `json:"telefone"`
+}
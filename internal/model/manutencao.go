@@ -0,0 +1,52 @@
+package model
+
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
+const (
+	OrdemAberta     = "aberta"
+	OrdemLiberada   = "liberada"   // voltou para o estoque
+	OrdemDescartada = "descartada" // não tinha conserto
+	OrdemCancelada  = "cancelada"  // a devolução que abriu a ordem foi cancelada
+)
+
+const (
+	OrdemHigienizacao = "higienizacao"
+	OrdemInspecao     = "inspecao" // motivo com exige_inspecao: o destino do item sai da conclusão
+)
+
+// ConcluirOrdemManutencao fecha a ordem: liberado devolve o item ao estoque, descartado tira de circulação
+type ConcluirOrdemManutencao struct {
+	Resultado        string `json:"resultado" binding:"required,oneof=liberada descartada"`
+	ServicoRealizado string `json:"servico_realizado" binding:"required,max=255"`
+	Observacao       string `json:"observacao" binding:"max=255"`
+}
+
+type OrdemManutencaoDto struct {
+	Id               int             `json:"id"`
+	Tipo             string          `json:"tipo"`
+	Status           string          `json:"status"`
+	IdEpi            int             `json:"id_epi"`
+	Epi              string          `json:"epi"`
+	CA               string          `json:"ca"`
+	IdTamanho        int             `json:"id_tamanho"`
+	Tamanho          string          `json:"tamanho"`
+	Quantidade       int             `json:"quantidade"`
+	IdDevolucao      *int            `json:"id_devolucao"`
+	ServicoRealizado *string         `json:"servico_realizado"`
+	Observacao       *string         `json:"observacao"`
+	ConcluidaPor     *string         `json:"concluida_por"`
+	AbertaEm         configs.DataBr  `json:"aberta_em"`
+	ConcluidaEm      *configs.DataBr `json:"concluida_em"`
+}
+
+// ItemEmManutencaoDto é uma linha do relatório do que está parado em higienização/manutenção
+type ItemEmManutencaoDto struct {
+	IdEpi            int            `json:"id_epi"`
+	Epi              string         `json:"epi"`
+	CA               string         `json:"ca"`
+	IdTamanho        int            `json:"id_tamanho"`
+	Tamanho          string         `json:"tamanho"`
+	Quantidade       int            `json:"quantidade"`
+	Ordens           int            `json:"ordens"`
+	AbertaMaisAntiga configs.DataBr `json:"aberta_mais_antiga"`
+}
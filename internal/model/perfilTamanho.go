@@ -0,0 +1,24 @@
+package model
+
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
+// PerfilTamanhoInserir fixa o tamanho do funcionario para um epi OU para todo epi de um tipo de proteção
+type PerfilTamanhoInserir struct {
+	IdEpi          *int `json:"id_epi"`
+	IdTipoProtecao *int `json:"id_tipo_protecao"`
+	IdTamanho      int  `json:"id_tamanho" binding:"required,min=1"`
+}
+
+type PerfilTamanhoDto struct {
+	ID           int              `json:"id"`
+	Epi          *EpiResumoDto    `json:"epi,omitempty"`
+	TipoProtecao *TipoProtecaoDto `json:"tipo_protecao,omitempty"`
+	Tamanho      TamanhoDto       `json:"tamanho"`
+	Origem       string           `json:"origem"` // "entrega" (aprendido) ou "manual"
+	AtualizadoEm configs.DataBr   `json:"atualizado_em"`
+}
+
+type TamanhoSugeridoDto struct {
+	IdEpi   int        `json:"id_epi"`
+	Tamanho TamanhoDto `json:"tamanho"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
+type LoginPortalInput struct {
+	Matricula string `json:"matricula" binding:"required,max=20"`
+	Pin       string `json:"pin" binding:"required,numeric,min=4,max=8"`
+}
+
+// DefinirPin é usado pelo técnico/almoxarifado para liberar (ou trocar) o acesso do funcionario ao portal
+type DefinirPin struct {
+	Pin string `json:"pin" binding:"required,numeric,min=4,max=8"`
+}
+
+type PerfilPortalDto struct {
+	Id           int    `json:"id"`
+	Nome         string `json:"nome"`
+	Matricula    string `json:"matricula"`
+	Funcao       string `json:"funcao"`
+	Departamento string `json:"departamento"`
+}
+
+type EpiEmPosseDto struct {
+	IdEpi         int             `json:"id_epi"`
+	Epi           string          `json:"epi"`
+	CA            string          `json:"ca"`
+	IdTamanho     int             `json:"id_tamanho"`
+	Tamanho       string          `json:"tamanho"`
+	Quantidade    int             `json:"quantidade"`
+	UltimaEntrega *configs.DataBr `json:"ultima_entrega"`
+}
+
+type ItemAssinaturaPendente struct {
+	Epi        string `json:"epi"`
+	Tamanho    string `json:"tamanho"`
+	Quantidade int    `json:"quantidade"`
+}
+
+type AssinaturaPendenteDto struct {
+	IdEntrega   int                      `json:"id_entrega"`
+	DataEntrega configs.DataBr           `json:"data_entrega"`
+	Itens       []ItemAssinaturaPendente `json:"itens"`
+}
+
+// SolicitacaoPortalInserir é o pedido de troca feito pelo próprio funcionario: o id dele vem do token
+type SolicitacaoPortalInserir struct {
+	Justificativa string            `json:"justificativa" binding:"required,max=255"`
+	Itens         []ItemParaInserir `json:"itens" binding:"required,min=1,dive"`
+}
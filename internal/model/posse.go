@@ -0,0 +1,23 @@
+package model
+
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
+// ItemPosseDto é o pedaço de uma entrega que ainda está com o funcionario, com o lote de onde saiu
+type ItemPosseDto struct {
+	IdEntrega    int            `json:"id_entrega"`
+	DataEntrega  configs.DataBr `json:"data_entrega"`
+	IdEntrada    int            `json:"id_entrada"`
+	Lote         string         `json:"lote"`
+	ValidadeLote configs.DataBr `json:"validade_lote"`
+	Quantidade   int            `json:"quantidade"`
+}
+
+type PosseEpiDto struct {
+	IdEpi      int            `json:"id_epi"`
+	Epi        string         `json:"epi"`
+	CA         string         `json:"ca"`
+	IdTamanho  int            `json:"id_tamanho"`
+	Tamanho    string         `json:"tamanho"`
+	Quantidade int            `json:"quantidade"`
+	Entregas   []ItemPosseDto `json:"entregas"`
+}
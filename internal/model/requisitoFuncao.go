@@ -0,0 +1,98 @@
+package model
+
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
+// RequisitoFuncao exige OU um epi especifico OU qualquer epi de um tipo de proteção
+type RequisitoFuncaoInserir struct {
+	IdEpi             *int `json:"id_epi"`
+	IdTipoProtecao    *int `json:"id_tipo_protecao"`
+	Quantidade        int  `json:"quantidade" binding:"required,gt=0"`
+	PeriodicidadeDias *int `json:"periodicidade_dias" binding:"omitempty,gt=0"`
+}
+
+type RequisitoFuncaoAtualizar struct {
+	Quantidade        int  `json:"quantidade" binding:"required,gt=0"`
+	PeriodicidadeDias *int `json:"periodicidade_dias" binding:"omitempty,gt=0"`
+}
+
+type RequisitoFuncaoDto struct {
+	ID                int              `json:"id"`
+	IdFuncao          int              `json:"id_funcao"`
+	Epi               *EpiResumoDto    `json:"epi,omitempty"`
+	TipoProtecao      *TipoProtecaoDto `json:"tipo_protecao,omitempty"`
+	Quantidade        int              `json:"quantidade"`
+	PeriodicidadeDias *int             `json:"periodicidade_dias"`
+}
+
+type EpiResumoDto struct {
+	ID   int    `json:"id"`
+	Nome string `json:"nome"`
+	CA   string `json:"ca,omitempty"`
+}
+
+type ItemSugestaoEntrega struct {
+	IdRequisito        int              `json:"id_requisito"`
+	Epi                *EpiResumoDto    `json:"epi,omitempty"`
+	TipoProtecao       *TipoProtecaoDto `json:"tipo_protecao,omitempty"`
+	QuantidadeExigida  int              `json:"quantidade_exigida"`
+	QuantidadeEmPosse  int              `json:"quantidade_em_posse"`
+	QuantidadeFaltante int              `json:"quantidade_faltante"`
+	UltimaEntrega      *configs.DataBr  `json:"ultima_entrega"`
+	ProximaTroca       *configs.DataBr  `json:"proxima_troca"`
+}
+
+type SugestaoEntregaDto struct {
+	IdFuncionario int                   `json:"id_funcionario"`
+	Itens         []ItemSugestaoEntrega `json:"itens"`
+}
+
+const (
+	SituacaoFaltante = "faltante"
+	SituacaoVencido  = "vencido"
+)
+
+type PendenciaEpiDto struct {
+	IdRequisito       int                   `json:"id_requisito"`
+	Epi               *EpiResumoDto         `json:"epi,omitempty"`
+	TipoProtecao      *TipoProtecaoDto      `json:"tipo_protecao,omitempty"`
+	Categoria         *CategoriaProtecaoDto `json:"categoria,omitempty"`
+	QuantidadeExigida int                   `json:"quantidade_exigida"`
+	QuantidadeEmPosse int                   `json:"quantidade_em_posse"`
+	Situacao          string                `json:"situacao"`
+	UltimaEntrega     *configs.DataBr       `json:"ultima_entrega"`
+}
+
+type ConformidadeFuncionarioDto struct {
+	ID         int               `json:"id"`
+	Nome       string            `json:"nome"`
+	Matricula  string            `json:"matricula"`
+	Funcao     string            `json:"funcao"`
+	Conforme   bool              `json:"conforme"`
+	Pendencias []PendenciaEpiDto `json:"pendencias"`
+}
+
+type ConformidadeDepartamentoDto struct {
+	Departamento           DepartamentoDto              `json:"departamento"`
+	TotalFuncionarios      int                          `json:"total_funcionarios"`
+	FuncionariosConformes  int                          `json:"funcionarios_conformes"`
+	PercentualConformidade float64                      `json:"percentual_conformidade"`
+	Funcionarios           []ConformidadeFuncionarioDto `json:"funcionarios"`
+}
+
+// ConformidadeCategoriaDto conta os requisitos (funcionario x epi exigido) de uma categoria da NR-6
+type ConformidadeCategoriaDto struct {
+	Categoria              *CategoriaProtecaoDto `json:"categoria"` // null = tipo de proteção ainda sem categoria
+	TotalRequisitos        int                   `json:"total_requisitos"`
+	RequisitosAtendidos    int                   `json:"requisitos_atendidos"`
+	Faltantes              int                   `json:"faltantes"`
+	Vencidos               int                   `json:"vencidos"`
+	PercentualConformidade float64               `json:"percentual_conformidade"`
+}
+
+type RelatorioConformidadeDto struct {
+	TotalFuncionarios      int                           `json:"total_funcionarios"`
+	FuncionariosConformes  int                           `json:"funcionarios_conformes"`
+	PercentualConformidade float64                       `json:"percentual_conformidade"`
+	Departamentos          []ConformidadeDepartamentoDto `json:"departamentos"`
+	Categorias             []ConformidadeCategoriaDto    `json:"categorias"`
+}
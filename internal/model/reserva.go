@@ -0,0 +1,34 @@
+package model
+
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
+type ReservaInserir struct {
+	IdEpi      int            `json:"id_epi" binding:"required,min=1"`
+	IdTamanho  int            `json:"id_tamanho" binding:"required,min=1"`
+	IdEntrada  *int           `json:"id_entrada"` // opcional: prende a reserva a um lote
+	Quantidade int            `json:"quantidade" binding:"required,gt=0"`
+	ExpiraEm   configs.DataBr `json:"expira_em" binding:"required"` // vale até o fim do dia informado
+	Observacao string         `json:"observacao" binding:"max=255"`
+}
+
+type ReservaDto struct {
+	Id            int            `json:"id"`
+	IdEpi         int            `json:"id_epi"`
+	Epi           string         `json:"epi"`
+	IdTamanho     int            `json:"id_tamanho"`
+	Tamanho       string         `json:"tamanho"`
+	IdEntrada     *int           `json:"id_entrada"`
+	Quantidade    int            `json:"quantidade"`
+	ExpiraEm      configs.DataBr `json:"expira_em"`
+	IdSolicitacao *int           `json:"id_solicitacao"`
+	Observacao    string         `json:"observacao"`
+	CriadaEm      configs.DataBr `json:"criada_em"`
+}
+
+type SaldoEstoqueDto struct {
+	IdEpi                int `json:"id_epi"`
+	IdTamanho            int `json:"id_tamanho"`
+	QuantidadeAtual      int `json:"quantidade_atual"`
+	QuantidadeReservada  int `json:"quantidade_reservada"`
+	QuantidadeDisponivel int `json:"quantidade_disponivel"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
+const (
+	SolicitacaoPendente  = "pendente"
+	SolicitacaoAprovada  = "aprovada"
+	SolicitacaoRejeitada = "rejeitada"
+	SolicitacaoAtendida  = "atendida"
+)
+
+// origem da solicitação: cadastrada por um usuario do sistema ou pelo próprio funcionario no portal
+const (
+	OrigemSistema = "sistema"
+	OrigemPortal  = "portal"
+)
+
+type SolicitacaoInserir struct {
+	IdFuncionario int               `json:"id_funcionario" binding:"required,min=1"`
+	Justificativa string            `json:"justificativa" binding:"required,max=255"`
+	Itens         []ItemParaInserir `json:"itens" binding:"required,min=1,dive"`
+}
+
+type RejeitarSolicitacao struct {
+	Motivo string `json:"motivo" binding:"required,max=255"`
+}
+
+// AtenderSolicitacao traz o que falta para virar uma entrega: a data e a assinatura do funcionario
+type AtenderSolicitacao struct {
+	Data_entrega       configs.DataBr `json:"data_entrega" binding:"required"`
+	Assinatura_Digital string         `json:"assinatura_digital" binding:"required"`
+}
+
+type ItemSolicitacaoDto struct {
+	Id         int    `json:"id"`
+	IdEpi      int    `json:"id_epi"`
+	Epi        string `json:"epi"`
+	IdTamanho  int    `json:"id_tamanho"`
+	Tamanho    string `json:"tamanho"`
+	Quantidade int    `json:"quantidade"`
+}
+
+type SolicitacaoDto struct {
+	Id             int                  `json:"id"`
+	Status         string               `json:"status"`
+	Origem         string               `json:"origem"`
+	Justificativa  string               `json:"justificativa"`
+	MotivoRejeicao *string              `json:"motivo_rejeicao"`
+	Funcionario    Funcionario_Dto      `json:"funcionario"`
+	Solicitante    *RecuperaUserEntrada `json:"solicitante"` // nil quando veio do portal
+	Analista       *RecuperaUserEntrada `json:"analista"`
+	IdEntrega      *int                 `json:"id_entrega"`
+	CriadaEm       configs.DataBr       `json:"criada_em"`
+	AnalisadaEm    *configs.DataBr      `json:"analisada_em"`
+	AtendidaEm     *configs.DataBr      `json:"atendida_em"`
+	Itens          []ItemSolicitacaoDto `json:"itens"`
+}
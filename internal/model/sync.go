@@ -0,0 +1,92 @@
+package model
+
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
+// tipos de operação que o tablet pode registrar offline
+const (
+	SyncEntrega   = "entrega"
+	SyncDevolucao = "devolucao"
+)
+
+// resultado de cada operação enviada pelo tablet
+const (
+	SyncAplicada  = "aplicada"
+	SyncDuplicada = "duplicada" // UUID já aplicado antes: nada foi feito de novo
+	SyncConflito  = "conflito"  // regra de negócio recusou (ex: sem estoque); pode ser corrigido e reenviado
+	SyncErro      = "erro"      // falha temporaria do servidor; reenviar o mesmo UUID
+)
+
+type FiltroCatalogoSync struct {
+	TokenEpis         string `form:"token_epis"`
+	TokenTamanhos     string `form:"token_tamanhos"`
+	TokenFuncionarios string `form:"token_funcionarios"`
+	TokenEstoque      string `form:"token_estoque"`
+}
+
+// CatalogoSync só traz os itens quando o token do tablet não bate com o atual
+type CatalogoSync struct {
+	Token    string `json:"token"`
+	Alterado bool   `json:"alterado"`
+	Itens    any    `json:"itens,omitempty"`
+}
+
+type CatalogosSyncDto struct {
+	Epis         CatalogoSync `json:"epis"`
+	Tamanhos     CatalogoSync `json:"tamanhos"`
+	Funcionarios CatalogoSync `json:"funcionarios"`
+	Estoque      CatalogoSync `json:"estoque"`
+}
+
+type EpiSyncDto struct {
+	Id             int            `json:"id"`
+	Nome           string         `json:"nome"`
+	Fabricante     string         `json:"fabricante"`
+	CA             string         `json:"ca"`
+	DataValidadeCa configs.DataBr `json:"data_validadeCa"`
+	IdTipoProtecao int            `json:"id_tipo_protecao"`
+	Tamanhos       []TamanhoDto   `json:"tamanhos"`
+}
+
+type FuncionarioSyncDto struct {
+	Id             int    `json:"id"`
+	Nome           string `json:"nome"`
+	Matricula      string `json:"matricula"`
+	IdFuncao       int    `json:"id_funcao"`
+	IdDepartamento int    `json:"id_departamento"`
+}
+
+type SaldoLoteSyncDto struct {
+	IdEntrada    int            `json:"id_entrada"`
+	IdEpi        int            `json:"id_epi"`
+	IdTamanho    int            `json:"id_tamanho"`
+	Lote         string         `json:"lote"`
+	DataValidade configs.DataBr `json:"data_validade"`
+	Quantidade   int            `json:"quantidade"`
+}
+
+type OperacaoSync struct {
+	Uuid      string              `json:"uuid" binding:"required,uuid"`
+	Tipo      string              `json:"tipo" binding:"required,oneof=entrega devolucao"`
+	Entrega   *EntregaParaInserir `json:"entrega" binding:"required_if=Tipo entrega"`
+	Devolucao *DevolucaoInserir   `json:"devolucao" binding:"required_if=Tipo devolucao"`
+}
+
+// EnviarSyncInput chega na ordem em que as operações foram feitas no tablet
+type EnviarSyncInput struct {
+	Operacoes []OperacaoSync `json:"operacoes" binding:"required,min=1,max=100,dive"`
+}
+
+type ResultadoOperacaoSync struct {
+	Uuid       string `json:"uuid"`
+	Status     string `json:"status"`
+	IdRegistro *int   `json:"id_registro"`
+	Erro       string `json:"erro,omitempty"`
+}
+
+type ResultadoSync struct {
+	Aplicadas  int                     `json:"aplicadas"`
+	Duplicadas int                     `json:"duplicadas"`
+	Conflitos  int                     `json:"conflitos"`
+	Erros      int                     `json:"erros"`
+	Operacoes  []ResultadoOperacaoSync `json:"operacoes"`
+}
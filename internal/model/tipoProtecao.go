@@ -1,10 +1,33 @@
 package model
 
 type TipoProtecao struct {
-	Nome string `json:"nome" binding:"required,max=50"`
+	Nome        string `json:"nome" binding:"required,max=50"`
+	IdCategoria int    `json:"id_categoria" binding:"required,gt=0"` // categoria da NR-6 (GET /categorias-protecao)
 }
 
 type TipoProtecaoDto struct {
-	ID   int64  `json:"id"`
-	Nome string `json:"nome"`
+	ID        int64                 `json:"id"`
+	Nome      string                `json:"nome"`
+	Categoria *CategoriaProtecaoDto `json:"categoria,omitempty"`
+}
+
+// CategoriaProtecaoDto é um grupo do Anexo I da NR-6; os tipos de proteção da empresa ficam embaixo dele
+type CategoriaProtecaoDto struct {
+	ID     int    `json:"id"`
+	Codigo string `json:"codigo"` // letra do grupo no Anexo I
+	Nome   string `json:"nome"`
+}
+
+type DefinirCategoriaProtecao struct {
+	IdCategoria int `json:"id_categoria" binding:"required,gt=0"`
+}
+
+type CategoriaComSubcategoriasDto struct {
+	Categoria     CategoriaProtecaoDto `json:"categoria"`
+	Subcategorias []TipoProtecaoDto    `json:"subcategorias"`
+}
+
+type ClassificacaoProtecaoDto struct {
+	Categorias   []CategoriaComSubcategoriasDto `json:"categorias"`
+	SemCategoria []TipoProtecaoDto              `json:"sem_categoria"` // tipos antigos que a migração não conseguiu classificar pelo nome
 }
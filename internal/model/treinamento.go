@@ -0,0 +1,36 @@
+package model
+
+import "github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+
+// TreinamentoInserir vale OU para um epi especifico OU para todo epi de um tipo de proteção
+type TreinamentoInserir struct {
+	Titulo         string         `json:"titulo" binding:"required,max=150"`
+	IdEpi          *int           `json:"id_epi"`
+	IdTipoProtecao *int           `json:"id_tipo_protecao"`
+	DataRealizacao configs.DataBr `json:"data_realizacao" binding:"required"`
+	Instrutor      string         `json:"instrutor" binding:"required,max=150"`
+	DataValidade   configs.DataBr `json:"data_validade"` // vazio = não vence
+	Participantes  []int          `json:"participantes" binding:"required,min=1,dive,min=1"`
+}
+
+type ParticipanteTreinamentoDto struct {
+	ID        int    `json:"id"`
+	Nome      string `json:"nome"`
+	Matricula string `json:"matricula"`
+}
+
+type TreinamentoDto struct {
+	ID                 int                          `json:"id"`
+	Titulo             string                       `json:"titulo"`
+	Epi                *EpiResumoDto                `json:"epi,omitempty"`
+	TipoProtecao       *TipoProtecaoDto             `json:"tipo_protecao,omitempty"`
+	DataRealizacao     configs.DataBr               `json:"data_realizacao"`
+	Instrutor          string                       `json:"instrutor"`
+	DataValidade       *configs.DataBr              `json:"data_validade"`
+	TotalParticipantes int                          `json:"total_participantes"`
+	Participantes      []ParticipanteTreinamentoDto `json:"participantes,omitempty"`
+}
+
+type RegraTreinamentoEntrega struct {
+	Regra string `json:"regra" binding:"required,oneof=desligada avisar bloquear"`
+}
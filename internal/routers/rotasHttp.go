@@ -4,6 +4,7 @@ import (
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/controller"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
 	_ "github.com/davi-fernandesx/sistema-de-gestao-de-epi/docs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/armazenamento"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/service"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
 	"github.com/gin-gonic/gin"
@@ -23,6 +24,21 @@ type Container struct {
 	Entrada      controller.EntradaController
 	Fornecedor   controller.FornecedorController
 	Entrega      controller.EntregaController
+	Requisito    controller.RequisitoFuncaoController
+	Solicitacao  controller.SolicitacaoController
+	Reserva      controller.ReservaController
+	Portal       controller.PortalFuncionarioController
+	Sync         controller.SyncController
+	Devolucao    controller.DevolucaoController
+	Motivo       controller.MotivoDevolucaoController
+	Posse        controller.PosseController
+	Manutencao   controller.ManutencaoController
+	ESocial      controller.ESocialController
+	Treinamento  controller.TreinamentoController
+	Perfil       controller.PerfilTamanhoController
+	Caepi        controller.CaepiController
+	Consumo      controller.ConsumoController
+	AnexoEpi     controller.AnexoEpiController
 }
 
 func NewContainer(db *pgxpool.Pool) *Container {
@@ -37,17 +53,47 @@ func NewContainer(db *pgxpool.Pool) *Container {
 	repoEntrada := repository.NewEntradaRepository(db)
 	repoFornecedor := repository.NewFornecedorRepository(db)
 	repoEntrega := repository.NewEntregaRepository(db)
+	repoRequisito := repository.NewRequisitoFuncaoRepository(db)
+	repoSolicitacao := repository.NewSolicitacaoRepository(db)
+	repoReserva := repository.NewReservaRepository(db)
+	repoPortal := repository.NewPortalFuncionarioRepository(db)
+	repoDevolucao := repository.NewDevolucaoRepository(db)
+	repoSync := repository.NewSyncRepository(db)
+	repoMotivo := repository.NewMotivoDevolucaoRepository(db)
+	repoPosse := repository.NewPosseRepository(db)
+	repoManutencao := repository.NewManutencaoRepository(db)
+	repoESocial := repository.NewESocialRepository(db)
+	repoTreinamento := repository.NewTreinamentoRepository(db)
+	repoPerfil := repository.NewPerfilTamanhoRepository(db)
+	repoCaepi := repository.NewCaepiRepository(db)
+	repoConsumo := repository.NewConsumoRepository(db)
+	repoAnexoEpi := repository.NewAnexoEpiRepository(db)
 
 	serviceUsuario := service.NewUsuarioService(repoUsuario)
 	departamentoService := service.NewDepartamentoService(repoDepartamento)
 	funcaoService := service.NewFuncaoService(repoFuncao)
 	FornecedorService := service.NewFornecedorService(repoFornecedor)
-	funcionarioService := service.NewFuncionarioService(repoFuncionario, db)
+	posseService := service.NewPosseService(repoPosse)
+	funcionarioService := service.NewFuncionarioService(repoFuncionario, db, *posseService)
 	tamanhoService := service.NewTamanhoService(repoTamanho)
 	TipoProtecaoService := service.NewProtecaoService(repoTipoProtecao)
 	epiService := service.NewEpiService(repoEpi, db)
 	entradaService := service.NewEntradaService(repoEntrada)
 	entregaService := service.NewEntregaService(repoEntrega, db)
+	requisitoService := service.NewRequisitoFuncaoService(repoRequisito)
+	reservaService := service.NewReservaService(repoReserva, db)
+	solicitacaoService := service.NewSolicitacaoService(repoSolicitacao, db, *entregaService, *reservaService)
+	portalService := service.NewPortalFuncionarioService(repoPortal, *entregaService, *requisitoService, *solicitacaoService, *posseService)
+	devolucaoService := service.NewDevolucaoService(repoDevolucao, db, *entregaService, *posseService)
+	motivoService := service.NewMotivoDevolucaoRepositoryServe(repoMotivo)
+	manutencaoService := service.NewManutencaoService(repoManutencao, db)
+	esocialService := service.NewESocialService(repoESocial)
+	treinamentoService := service.NewTreinamentoService(repoTreinamento, db)
+	perfilService := service.NewPerfilTamanhoService(repoPerfil)
+	caepiService := service.NewCaepiService(repoCaepi, db)
+	consumoService := service.NewConsumoService(repoConsumo)
+	anexoEpiService := service.NewAnexoEpiService(repoAnexoEpi, armazenamento.NewDisco(""), db)
+	syncService := service.NewSyncService(repoSync, db, *entregaService, *devolucaoService)
 
 	return &Container{
 		Usuario:      *controller.NewLoginController(serviceUsuario),
@@ -60,6 +106,21 @@ func NewContainer(db *pgxpool.Pool) *Container {
 		Entrada:      *controller.NewEntradaController(entradaService),
 		Fornecedor:   *controller.NewFornecedorController(FornecedorService),
 		Entrega:      *controller.NewEntregaController(entregaService),
+		Requisito:    *controller.NewRequisitoFuncaoController(requisitoService),
+		Solicitacao:  *controller.NewSolicitacaoController(solicitacaoService),
+		Reserva:      *controller.NewReservaController(reservaService),
+		Portal:       *controller.NewPortalFuncionarioController(portalService),
+		Sync:         *controller.NewSyncController(syncService),
+		Devolucao:    *controller.NewDevolucaoController(devolucaoService),
+		Motivo:       *controller.NewMotivoDevolucaoController(motivoService),
+		Posse:        *controller.NewPosseController(posseService),
+		Manutencao:   *controller.NewManutencaoController(manutencaoService),
+		ESocial:      *controller.NewESocialController(esocialService),
+		Treinamento:  *controller.NewTreinamentoController(treinamentoService),
+		Perfil:       *controller.NewPerfilTamanhoController(perfilService),
+		Caepi:        *controller.NewCaepiController(caepiService),
+		Consumo:      *controller.NewConsumoController(consumoService),
+		AnexoEpi:     *controller.NewAnexoEpiController(anexoEpiService),
 	}
 }
 func ConfigurarRotas(r *gin.Engine, c *Container, db *pgxpool.Pool) {
@@ -70,6 +131,10 @@ func ConfigurarRotas(r *gin.Engine, c *Container, db *pgxpool.Pool) {
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// --- Operador do sistema ---
+	// O catálogo do CAEPI é de todas as empresas: não passa pelo tenant nem pelo JWT dos usuarios
+	r.POST("/api/importacao-caepi", middleware.AutenticacaoOperador(), c.Caepi.Importar())
+
 	api := r.Group("/api")
 	// --- GRUPO 2: Rotas que precisam do tenentId (SaaS) ---
 	// Precisa do tenant Id para passar
@@ -80,11 +145,32 @@ func ConfigurarRotas(r *gin.Engine, c *Container, db *pgxpool.Pool) {
 		api.POST("/login", c.Usuario.Login())
 	}
 
+	// --- GRUPO 4: Portal do Funcionario ---
+	// Login por matrícula + PIN, com token próprio (não abre as rotas do sistema).
+	// O grupo nasce aqui para herdar só o tenant, e não o JWT de usuario
+	portal := api.Group("/portal")
+	{
+
+		portal.POST("/login", c.Portal.Login())
+
+		logado := portal.Group("", middleware.AutenticacaoFuncionario(queries))
+		logado.GET("/me", c.Portal.Perfil())
+		logado.GET("/epis-em-posse", c.Portal.EpisEmPosse())
+		logado.GET("/entregas", c.Portal.Entregas())
+		logado.GET("/assinaturas-pendentes", c.Portal.AssinaturasPendentes())
+		logado.POST("/entrega/:id/confirmar", c.Portal.ConfirmarCiencia())
+		logado.GET("/proximas-trocas", c.Portal.ProximasTrocas())
+		logado.POST("/solicitacao", c.Portal.SolicitarTroca())
+	}
+
 	// --- GRUPO 3: Rotas Protegidas (SaaS) ---
 	// Precisa do Token JWT para passar
 	api.Use(middleware.AutenticacaoJWT(), middleware.LoggerComUsuario())
 	{
 
+		// criações que os tablets reenviam quando a rede cai
+		idempotente := middleware.Idempotencia(queries)
+
 		api.GET("/me", c.Usuario.VerPerfil())
 		//departamentos
 		api.POST("/cadastro-departamento", c.Departamento.RegistraDepartamento())
@@ -100,12 +186,31 @@ func ConfigurarRotas(r *gin.Engine, c *Container, db *pgxpool.Pool) {
 		api.DELETE("/funcao/:id", c.Funcao.DeletarFuncao())
 		api.PUT("/funcao/:id", c.Funcao.AtualizarFuncao())
 
+		//epis obrigatorios por funcao
+		api.POST("/funcao/:id/requisitos", c.Requisito.AdicionarRequisito())
+		api.GET("/funcao/:id/requisitos", c.Requisito.ListarRequisitos())
+		api.PUT("/requisito-funcao/:id", c.Requisito.AtualizarRequisito())
+		api.DELETE("/requisito-funcao/:id", c.Requisito.DeletarRequisito())
+		api.GET("/sugestao-entrega/:id", c.Requisito.SugestaoEntrega())
+		api.GET("/relatorio-conformidade", c.Requisito.RelatorioConformidade())
+		api.GET("/relatorio-consumo", c.Consumo.Relatorio())
+
 		//funcionario
 		api.POST("/cadastro-funcionario", c.Funcionario.Adicionar())
+		api.POST("/importacao-funcionarios", idempotente, c.Funcionario.ImportarFuncionarios())
 		api.GET("/funcionarios", c.Funcionario.ListarFuncionarios())
-		api.GET("/funcionario/:matricula", c.Funcionario.ListarFuncionarioPorMatricula())
+		api.GET("/funcionario/:id", c.Funcionario.ListarFuncionarioPorMatricula())
 		api.DELETE("/funcionario/:id", c.Funcionario.DeletarFuncionaioId())
 		api.PATCH("/funcionario/:id", c.Funcionario.AtualizaFuncionario())
+		api.GET("/funcionario/:id/epis-em-posse", c.Posse.EpisEmPosse())
+		api.POST("/funcionario/:id/desligamento", c.Funcionario.Desligar())
+		api.GET("/funcionario/:id/historico-cargos", c.Funcionario.HistoricoCargos())
+		api.GET("/funcionario/:id/dados-pessoais", c.Funcionario.DadosPessoais())
+		api.GET("/funcionario/:id/perfil-tamanhos", c.Perfil.Listar())
+		api.GET("/funcionario/:id/tamanhos-sugeridos", c.Perfil.Sugeridos())
+		api.PUT("/funcionario/:id/perfil-tamanhos", c.Perfil.Definir())
+		api.DELETE("/funcionario/:id/perfil-tamanhos/:perfil", c.Perfil.Remover())
+		api.PUT("/funcionario/:id/pin", c.Portal.DefinirPin())
 
 		//tamanhos disponiveis para vincular a um epi
 		api.POST("/cadastro-tamanho", c.Tamanho.Adicionar())
@@ -113,11 +218,13 @@ func ConfigurarRotas(r *gin.Engine, c *Container, db *pgxpool.Pool) {
 		api.GET("/tamanho/:id", c.Tamanho.ListarTamanhoPorId())
 		api.DELETE("/tamanho/:id", c.Tamanho.DeletarTamanho())
 
-		//proteções dedicada a cada epi
+		//proteções dedicada a cada epi (subcategorias da empresa dentro das categorias da NR-6)
 		api.POST("/cadastro-protecao", c.Protecao.AdicionarProtecao())
 		api.GET("/protecoes", c.Protecao.ListarProtecoes())
 		api.GET("/protecao/:id", c.Protecao.ListarProtecaoPorId())
 		api.DELETE("/protecao/:id", c.Protecao.DeletarProtecao())
+		api.PUT("/protecao/:id/categoria", c.Protecao.DefinirCategoria())
+		api.GET("/categorias-protecao", c.Protecao.Classificacao()) // categorias da NR-6 com os tipos da empresa embaixo
 
 		//Epi´s
 		api.POST("/cadastro-epi", c.Epi.AdicionarEpi())
@@ -126,8 +233,19 @@ func ConfigurarRotas(r *gin.Engine, c *Container, db *pgxpool.Pool) {
 		api.DELETE("/epi/:id", c.Epi.DeletarEpi())
 		api.PATCH("/epi/:id", c.Epi.AtualizaEpi())
 
+		//fotos e documentos do epi (certificado do CA, manual, ficha técnica)
+		api.POST("/epi/:id/anexos", idempotente, c.AnexoEpi.Adicionar())
+		api.GET("/epi/:id/anexos", c.AnexoEpi.Listar())
+		api.GET("/epi/:id/anexos/:anexo", c.AnexoEpi.Baixar())
+		api.DELETE("/epi/:id/anexos/:anexo", c.AnexoEpi.Remover())
+
+		//catálogo oficial de CAs (CAEPI) e os alertas de CA cancelado/renovado; a importação é do operador (lá em cima)
+		api.GET("/catalogo-ca/:ca", c.Caepi.BuscarCa())
+		api.GET("/alertas-ca", c.Caepi.ListarAlertas())
+		api.POST("/alerta-ca/:id/resolver", c.Caepi.ResolverAlerta())
+
 		//entradas
-		api.POST("/cadastrar-entrada", c.Entrada.AdicionarEntrada())
+		api.POST("/cadastrar-entrada", idempotente, c.Entrada.AdicionarEntrada())
 		api.GET("/entradas", c.Entrada.ListarEntradas())
 		api.DELETE("/entrada/:id", c.Entrada.CancelarEntrada())
 
@@ -138,7 +256,57 @@ func ConfigurarRotas(r *gin.Engine, c *Container, db *pgxpool.Pool) {
 		api.PATCH("/fornecedor/:id", c.Fornecedor.AtualizaFornecedor())
 
 		//entregas
-		api.POST("/cadastro-entregas", c.Entrega.Adicionar())
+		api.POST("/cadastro-entregas", idempotente, c.Entrega.Adicionar())
+		api.POST("/cadastro-entregas-lote", idempotente, c.Entrega.AdicionarLote())
+
+		//devoluções e trocas
+		api.POST("/cadastro-devolucao", idempotente, c.Devolucao.Adicionar())
+		api.GET("/devolucoes", c.Devolucao.Listar())
+		api.GET("/devolucao/:id", c.Devolucao.Detalhe())
+		api.DELETE("/devolucao/:id", c.Devolucao.Cancelar())
+		api.POST("/cadastro-devolucao-documento", idempotente, c.Devolucao.AdicionarDocumento())
+		api.GET("/devolucao-documento/:id", c.Devolucao.DetalheDocumento())
+		api.DELETE("/devolucao-documento/:id", c.Devolucao.CancelarDocumento())
+
+		//motivos de devolução (cada empresa já nasce com os padrões)
+		api.POST("/cadastro-motivo-devolucao", c.Motivo.Adicionar())
+		api.GET("/motivos-devolucao", c.Motivo.ListarMotivos())
+		api.GET("/motivo-devolucao/:id", c.Motivo.ListarMotivoPorId())
+		api.PUT("/motivo-devolucao/:id", c.Motivo.AtualizarMotivo())
+		api.DELETE("/motivo-devolucao/:id", c.Motivo.DeletarMotivo())
+
+		//higienização/manutenção dos epis devolvidos antes de voltarem ao estoque
+		api.GET("/ordens-manutencao", c.Manutencao.Listar())
+		api.POST("/ordem-manutencao/:id/concluir", c.Manutencao.Concluir())
+		api.GET("/relatorio-manutencao", c.Manutencao.Relatorio())
+
+		//eSocial: S-2240 com os epis entregues (zip para a contabilidade transmitir)
+		api.PUT("/funcao/:id/esocial", c.ESocial.DefinirFuncao())
+		api.POST("/esocial/s2240", c.ESocial.ExportarS2240())
+
+		//treinamentos por epi/tipo de proteção e a regra que vale na entrega
+		api.POST("/cadastro-treinamento", idempotente, c.Treinamento.Adicionar())
+		api.GET("/treinamentos", c.Treinamento.Listar())
+		api.GET("/treinamento/:id", c.Treinamento.Detalhe())
+		api.DELETE("/treinamento/:id", c.Treinamento.Deletar())
+		api.PUT("/regra-treinamento-entrega", c.Treinamento.DefinirRegra())
+
+		//solicitações de epi (supervisor pede, técnico aprova, almoxarifado atende)
+		api.POST("/cadastro-solicitacao", c.Solicitacao.Adicionar())
+		api.GET("/solicitacoes", c.Solicitacao.Listar())
+		api.POST("/solicitacao/:id/aprovar", c.Solicitacao.Aprovar())
+		api.POST("/solicitacao/:id/rejeitar", c.Solicitacao.Rejeitar())
+		api.POST("/solicitacao/:id/atender", idempotente, c.Solicitacao.Atender())
+
+		//reservas de estoque (saldo preso para uma obra, admissão ou solicitação)
+		api.POST("/cadastro-reserva", c.Reserva.Adicionar())
+		api.GET("/reservas", c.Reserva.Listar())
+		api.DELETE("/reserva/:id", c.Reserva.Cancelar())
+		api.GET("/estoque-disponivel", c.Reserva.Saldo())
+
+		//sincronização dos tablets que trabalham offline
+		api.GET("/sync/catalogos", c.Sync.Catalogos())
+		api.POST("/sync/operacoes", c.Sync.Enviar())
 	}
 
 }
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AnexoEpiRepository interface {
+	Listar(ctx context.Context, arg repository.ListarAnexosEpiParams) ([]repository.ListarAnexosEpiRow, error)
+	Buscar(ctx context.Context, arg repository.BuscarAnexoEpiParams) (repository.BuscarAnexoEpiRow, error)
+}
+
+// Armazenamento é onde ficam os arquivos dos anexos; o banco guarda só a chave
+type Armazenamento interface {
+	Salvar(ctx context.Context, chave string, conteudo io.Reader) error
+	Abrir(ctx context.Context, chave string) (io.ReadCloser, error)
+	Remover(ctx context.Context, chave string) error
+}
+
+type AnexoEpiService struct {
+	repo          AnexoEpiRepository
+	armazenamento Armazenamento
+	db            *pgxpool.Pool
+	queries       *repository.Queries
+}
+
+func NewAnexoEpiService(repo AnexoEpiRepository, armazenamento Armazenamento, db *pgxpool.Pool) *AnexoEpiService {
+
+	return &AnexoEpiService{
+		repo:          repo,
+		armazenamento: armazenamento,
+		db:            db,
+		queries:       repository.New(db),
+	}
+}
+
+// Adicionar grava o arquivo e depois o registro. A primeira foto do epi (ou a enviada com principal)
+// vira a foto principal; se o banco falhar o arquivo gravado é apagado
+func (a *AnexoEpiService) Adicionar(ctx context.Context, idEpi int, input model.AnexoEpiInserir, nomeArquivo string, tamanho int64, conteudo io.Reader, idUsuario int, tenantId int32) (model.AnexoEpiDto, error) {
+
+	if idEpi <= 0 {
+		return model.AnexoEpiDto{}, helper.ErrId
+	}
+
+	if input.Principal && input.Tipo != helper.AnexoFoto {
+		return model.AnexoEpiDto{}, fmt.Errorf("%w: apenas foto pode ser a principal", helper.ErrAnexoInvalido)
+	}
+
+	if tamanho > helper.TamanhoMaximoAnexo {
+		return model.AnexoEpiDto{}, fmt.Errorf("%w: o arquivo passa de %dMB", helper.ErrAnexoInvalido, helper.TamanhoMaximoAnexo>>20)
+	}
+
+	cabecalho := make([]byte, 512)
+	n, err := io.ReadFull(conteudo, cabecalho)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return model.AnexoEpiDto{}, err
+	}
+	cabecalho = cabecalho[:n]
+
+	mime, extensao, err := helper.ValidarAnexoEpi(input.Tipo, cabecalho)
+	if err != nil {
+		return model.AnexoEpiDto{}, err
+	}
+
+	if err := a.existeEpi(ctx, idEpi, tenantId); err != nil {
+		return model.AnexoEpiDto{}, err
+	}
+
+	sufixo := make([]byte, 16)
+	if _, err := rand.Read(sufixo); err != nil {
+		return model.AnexoEpiDto{}, err
+	}
+	chave := fmt.Sprintf("epis/%d/%d/%s%s", tenantId, idEpi, hex.EncodeToString(sufixo), extensao)
+
+	if err := a.armazenamento.Salvar(ctx, chave, io.MultiReader(bytes.NewReader(cabecalho), conteudo)); err != nil {
+		return model.AnexoEpiDto{}, fmt.Errorf("erro ao gravar o arquivo, %w", err)
+	}
+
+	anexo, err := a.registrar(ctx, repository.AdicionarAnexoEpiParams{
+		TenantID:    tenantId,
+		Idepi:       int32(idEpi),
+		Tipo:        input.Tipo,
+		NomeArquivo: limitarTexto(nomeSeguro(nomeArquivo, extensao), 255),
+		Mime:        mime,
+		Tamanho:     tamanho,
+		Chave:       chave,
+		Principal:   input.Principal,
+		Idusuario:   pgtype.Int4{Int32: int32(idUsuario), Valid: idUsuario > 0},
+	})
+	if err != nil {
+
+		if errRemover := a.armazenamento.Remover(context.WithoutCancel(ctx), chave); errRemover != nil {
+			log.Printf("erro ao apagar o arquivo %s de um anexo não registrado: %v", chave, errRemover)
+		}
+		return model.AnexoEpiDto{}, err
+	}
+
+	return anexo, nil
+}
+
+func (a *AnexoEpiService) registrar(ctx context.Context, arg repository.AdicionarAnexoEpiParams) (model.AnexoEpiDto, error) {
+
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		return model.AnexoEpiDto{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := a.queries.WithTx(tx)
+
+	if arg.Tipo == helper.AnexoFoto {
+
+		temPrincipal, err := qtx.TemFotoPrincipal(ctx, repository.TemFotoPrincipalParams{
+			Idepi:    arg.Idepi,
+			TenantID: arg.TenantID,
+		})
+		if err != nil {
+			return model.AnexoEpiDto{}, err
+		}
+
+		if !temPrincipal {
+			arg.Principal = true
+		} else if arg.Principal {
+
+			err = qtx.DesmarcarFotoPrincipal(ctx, repository.DesmarcarFotoPrincipalParams{
+				Idepi:    arg.Idepi,
+				TenantID: arg.TenantID,
+			})
+			if err != nil {
+				return model.AnexoEpiDto{}, err
+			}
+		}
+	}
+
+	criado, err := qtx.AdicionarAnexoEpi(ctx, arg)
+	if err != nil {
+		return model.AnexoEpiDto{}, helper.TraduzErroPostgres(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.AnexoEpiDto{}, err
+	}
+
+	return model.AnexoEpiDto{
+		ID:          int(criado.ID),
+		Tipo:        arg.Tipo,
+		NomeArquivo: arg.NomeArquivo,
+		Mime:        arg.Mime,
+		Tamanho:     arg.Tamanho,
+		Principal:   arg.Principal,
+		CriadoEm:    &criado.CriadoEm.Time,
+		Url:         urlAnexoEpi(arg.Idepi, criado.ID),
+	}, nil
+}
+
+func (a *AnexoEpiService) Listar(ctx context.Context, idEpi int, tenantId int32) ([]model.AnexoEpiDto, error) {
+
+	if idEpi <= 0 {
+		return []model.AnexoEpiDto{}, helper.ErrId
+	}
+
+	if err := a.existeEpi(ctx, idEpi, tenantId); err != nil {
+		return []model.AnexoEpiDto{}, err
+	}
+
+	anexos, err := a.repo.Listar(ctx, repository.ListarAnexosEpiParams{
+		Idepi:    int32(idEpi),
+		TenantID: tenantId,
+	})
+	if err != nil {
+		return []model.AnexoEpiDto{}, err
+	}
+
+	dto := make([]model.AnexoEpiDto, 0, len(anexos))
+	for _, anexo := range anexos {
+
+		criadoEm := anexo.CriadoEm.Time
+		dto = append(dto, model.AnexoEpiDto{
+			ID:          int(anexo.ID),
+			Tipo:        anexo.Tipo,
+			NomeArquivo: anexo.NomeArquivo,
+			Mime:        anexo.Mime,
+			Tamanho:     anexo.Tamanho,
+			Principal:   anexo.Principal,
+			CriadoEm:    &criadoEm,
+			Url:         urlAnexoEpi(int32(idEpi), anexo.ID),
+		})
+	}
+
+	return dto, nil
+}
+
+// Abrir devolve os dados do anexo e o arquivo para download; quem chama fecha o arquivo
+func (a *AnexoEpiService) Abrir(ctx context.Context, idEpi, idAnexo int, tenantId int32) (model.AnexoEpiDto, io.ReadCloser, error) {
+
+	if idEpi <= 0 || idAnexo <= 0 {
+		return model.AnexoEpiDto{}, nil, helper.ErrId
+	}
+
+	anexo, err := a.repo.Buscar(ctx, repository.BuscarAnexoEpiParams{
+		ID:       int32(idAnexo),
+		Idepi:    int32(idEpi),
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.AnexoEpiDto{}, nil, helper.ErrNaoEncontrado
+		}
+		return model.AnexoEpiDto{}, nil, err
+	}
+
+	arquivo, err := a.armazenamento.Abrir(ctx, anexo.Chave)
+	if err != nil {
+
+		if errors.Is(err, os.ErrNotExist) {
+			return model.AnexoEpiDto{}, nil, helper.ErrNaoEncontrado
+		}
+		return model.AnexoEpiDto{}, nil, err
+	}
+
+	return model.AnexoEpiDto{
+		ID:          int(anexo.ID),
+		Tipo:        anexo.Tipo,
+		NomeArquivo: anexo.NomeArquivo,
+		Mime:        anexo.Mime,
+		Tamanho:     anexo.Tamanho,
+		Principal:   anexo.Principal,
+		Url:         urlAnexoEpi(int32(idEpi), anexo.ID),
+	}, arquivo, nil
+}
+
+// Remover apaga o registro (promovendo outra foto se a principal saiu) e depois o arquivo.
+// Falha ao apagar o arquivo só vai para o log: o anexo já não aparece mais para ninguém
+func (a *AnexoEpiService) Remover(ctx context.Context, idEpi, idAnexo int, tenantId int32) error {
+
+	if idEpi <= 0 || idAnexo <= 0 {
+		return helper.ErrId
+	}
+
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := a.queries.WithTx(tx)
+
+	removido, err := qtx.RemoverAnexoEpi(ctx, repository.RemoverAnexoEpiParams{
+		ID:       int32(idAnexo),
+		Idepi:    int32(idEpi),
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return helper.ErrNaoEncontrado
+		}
+		return err
+	}
+
+	if removido.Principal {
+
+		err = qtx.PromoverFotoPrincipal(ctx, repository.PromoverFotoPrincipalParams{
+			Idepi:    int32(idEpi),
+			TenantID: tenantId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	if err := a.armazenamento.Remover(context.WithoutCancel(ctx), removido.Chave); err != nil {
+		log.Printf("erro ao apagar o arquivo %s do anexo %d: %v", removido.Chave, idAnexo, err)
+	}
+
+	return nil
+}
+
+func (a *AnexoEpiService) existeEpi(ctx context.Context, idEpi int, tenantId int32) error {
+
+	_, err := a.queries.BuscarEpi(ctx, repository.BuscarEpiParams{
+		ID:       int32(idEpi),
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return helper.ErrNaoEncontrado
+		}
+		return err
+	}
+
+	return nil
+}
+
+// fotoPrincipalDto monta o resumo da foto principal que vem junto na consulta do epi
+func fotoPrincipalDto(idEpi int32, id pgtype.Int4, nome, mime pgtype.Text) *model.AnexoEpiDto {
+
+	if !id.Valid {
+		return nil
+	}
+
+	return &model.AnexoEpiDto{
+		ID:          int(id.Int32),
+		Tipo:        helper.AnexoFoto,
+		NomeArquivo: nome.String,
+		Mime:        mime.String,
+		Principal:   true,
+		Url:         urlAnexoEpi(idEpi, id.Int32),
+	}
+}
+
+func urlAnexoEpi(idEpi, idAnexo int32) string {
+
+	return fmt.Sprintf("/api/epi/%d/anexos/%d", idEpi, idAnexo)
+}
+
+// nomeSeguro tira o caminho que alguns navegadores mandam junto e garante um nome para o download
+func nomeSeguro(nome, extensao string) string {
+
+	nome = strings.TrimSpace(path.Base(strings.ReplaceAll(nome, "\\", "/")))
+	if nome == "" || nome == "." || nome == "/" {
+		return "anexo" + extensao
+	}
+
+	return nome
+}
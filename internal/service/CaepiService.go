@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CaepiRepository interface {
+	BuscarCa(ctx context.Context, ca string) (repository.BuscarCatalogoCaRow, error)
+	ImportacoesPendentes(ctx context.Context) ([]int32, error)
+	ListarAlertas(ctx context.Context, arg repository.ListarAlertasCaParams) ([]repository.ListarAlertasCaRow, error)
+	ResolverAlerta(ctx context.Context, arg repository.ResolverAlertaCaParams) (int64, error)
+}
+
+// CaepiService mantém o catálogo oficial de CAs (compartilhado entre os tenants) e marca
+// os epis dos tenants quando o CA deles é cancelado ou renovado
+type CaepiService struct {
+	repo    CaepiRepository
+	db      *pgxpool.Pool
+	queries *repository.Queries
+}
+
+func NewCaepiService(r CaepiRepository, pool *pgxpool.Pool) *CaepiService {
+	return &CaepiService{repo: r, db: pool, queries: repository.New(pool)}
+}
+
+// Importar troca o catálogo pelo conteúdo do arquivo numa transação só (COPY para a área de carga
+// e depois um merge) e em seguida roda a sinalização dos epis
+func (c *CaepiService) Importar(ctx context.Context, nomeArquivo string, registros []helper.RegistroCaepi, ignoradas int) (model.ResultadoImportacaoCaepi, error) {
+
+	if len(registros) == 0 {
+		return model.ResultadoImportacaoCaepi{}, fmt.Errorf("%w: nenhum CA encontrado no arquivo", helper.ErrArquivoCaepi)
+	}
+
+	tx, err := c.db.Begin(ctx)
+	if err != nil {
+		return model.ResultadoImportacaoCaepi{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.queries.WithTx(tx)
+
+	id, err := qtx.IniciarImportacaoCaepi(ctx, limitarTexto(nomeArquivo, 255))
+	if err != nil {
+		return model.ResultadoImportacaoCaepi{}, err
+	}
+
+	carga := make([]repository.CarregarCatalogoCaParams, 0, len(registros))
+	for _, r := range registros {
+		carga = append(carga, repository.CarregarCatalogoCaParams{
+			Idimportacao:   id,
+			Ca:             limitarTexto(r.CA, 20),
+			Situacao:       limitarTexto(r.Situacao, 30),
+			Validade:       pgtype.Date{Time: r.Validade, Valid: !r.Validade.IsZero()},
+			CnpjFabricante: pgtype.Text{String: limitarTexto(r.CnpjFabricante, 14), Valid: r.CnpjFabricante != ""},
+			Fabricante:     limitarTexto(r.Fabricante, 255),
+			Equipamento:    limitarTexto(r.Equipamento, 255),
+			Descricao:      r.Descricao,
+		})
+	}
+
+	if _, err := qtx.CarregarCatalogoCa(ctx, carga); err != nil {
+		return model.ResultadoImportacaoCaepi{}, fmt.Errorf("erro ao carregar o arquivo do CAEPI, %w", err)
+	}
+
+	mesclados, err := qtx.MesclarCatalogoCa(ctx, id)
+	if err != nil {
+		return model.ResultadoImportacaoCaepi{}, fmt.Errorf("erro ao atualizar o catalogo de CAs, %w", err)
+	}
+
+	if err := qtx.LimparCargaCaepi(ctx, id); err != nil {
+		return model.ResultadoImportacaoCaepi{}, err
+	}
+
+	err = qtx.FinalizarImportacaoCaepi(ctx, repository.FinalizarImportacaoCaepiParams{
+		ID:             id,
+		TotalRegistros: int32(len(registros)),
+		Novos:          int32(mesclados.Novos),
+		Alterados:      int32(mesclados.Alterados),
+	})
+	if err != nil {
+		return model.ResultadoImportacaoCaepi{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.ResultadoImportacaoCaepi{}, err
+	}
+
+	resultado := model.ResultadoImportacaoCaepi{
+		ID:        int(id),
+		Arquivo:   nomeArquivo,
+		Total:     len(registros),
+		Novos:     int(mesclados.Novos),
+		Alterados: int(mesclados.Alterados),
+		Ignoradas: ignoradas,
+	}
+
+	// o catálogo já foi gravado; se a sinalização falhar aqui o job periódico pega a importação depois
+	resultado.EpisSinalizados, err = c.SinalizarPendentes(ctx)
+	if err != nil {
+		log.Printf("erro ao sinalizar epis da importação %d do CAEPI: %v", id, err)
+	}
+
+	return resultado, nil
+}
+
+// SinalizarPendentes é o job: para cada importação ainda não processada, marca os epis ativos
+// de todos os tenants cujo CA foi cancelado/suspenso ou renovado nela
+func (c *CaepiService) SinalizarPendentes(ctx context.Context) (int64, error) {
+
+	pendentes, err := c.repo.ImportacoesPendentes(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, id := range pendentes {
+
+		sinalizados, err := c.sinalizarImportacao(ctx, id)
+		if err != nil {
+			return total, fmt.Errorf("importação %d: %w", id, err)
+		}
+		total += sinalizados
+	}
+
+	return total, nil
+}
+
+func (c *CaepiService) sinalizarImportacao(ctx context.Context, id int32) (int64, error) {
+
+	tx, err := c.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := c.queries.WithTx(tx)
+
+	sinalizados, err := qtx.SinalizarEpisCa(ctx, pgtype.Int4{Int32: id, Valid: true})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := qtx.MarcarImportacaoSinalizada(ctx, id); err != nil {
+		return 0, err
+	}
+
+	return sinalizados, tx.Commit(ctx)
+}
+
+// SinalizarPeriodicamente roda o job até o contexto acabar. Importação feita pelo comando de linha
+// (em outro processo) ou cuja sinalização falhou é pega na volta seguinte
+func (c *CaepiService) SinalizarPeriodicamente(ctx context.Context, intervalo time.Duration) {
+
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for {
+		if sinalizados, err := c.SinalizarPendentes(ctx); err != nil {
+			log.Printf("erro no job de sinalização do CAEPI: %v", err)
+		} else if sinalizados > 0 {
+			log.Printf("job do CAEPI: %d epis sinalizados", sinalizados)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *CaepiService) BuscarCa(ctx context.Context, ca string) (model.CatalogoCaDto, error) {
+
+	registro, err := c.repo.BuscarCa(ctx, ca)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.CatalogoCaDto{}, helper.ErrNaoEncontrado
+		}
+
+		return model.CatalogoCaDto{}, fmt.Errorf("erro ao buscar CA no catalogo, %w", err)
+	}
+
+	dto := model.CatalogoCaDto{
+		CA:             registro.Ca,
+		Situacao:       registro.Situacao,
+		CnpjFabricante: registro.CnpjFabricante.String,
+		Fabricante:     registro.Fabricante,
+		Equipamento:    registro.Equipamento,
+		Descricao:      registro.Descricao,
+	}
+
+	if registro.Validade.Valid {
+		dto.Validade = configs.NewDataBrPtr(registro.Validade.Time)
+	}
+
+	return dto, nil
+}
+
+func (c *CaepiService) ListarAlertas(ctx context.Context, todos bool, tenantId int32) ([]model.AlertaCaDto, error) {
+
+	alertas, err := c.repo.ListarAlertas(ctx, repository.ListarAlertasCaParams{
+		TenantID: tenantId,
+		Todos:    todos,
+	})
+	if err != nil {
+
+		return []model.AlertaCaDto{}, fmt.Errorf("erro ao listar alertas de CA, %w", err)
+	}
+
+	dto := make([]model.AlertaCaDto, 0, len(alertas))
+	for _, a := range alertas {
+
+		item := model.AlertaCaDto{
+			ID:        int(a.ID),
+			Epi:       model.EpiResumoDto{ID: int(a.Idepi), Nome: a.EpiNome, CA: a.EpiCa},
+			Tipo:      a.Tipo,
+			Situacao:  a.Situacao,
+			CriadoEm:  configs.DataBr(a.CriadoEm.Time),
+			Resolvido: a.ResolvidoEm.Valid,
+		}
+
+		if a.ValidadeAnterior.Valid {
+			item.ValidadeAnterior = configs.NewDataBrPtr(a.ValidadeAnterior.Time)
+		}
+
+		if a.ValidadeNova.Valid {
+			item.ValidadeNova = configs.NewDataBrPtr(a.ValidadeNova.Time)
+		}
+
+		dto = append(dto, item)
+	}
+
+	return dto, nil
+}
+
+func (c *CaepiService) ResolverAlerta(ctx context.Context, id int, tenantId int32) error {
+
+	if id <= 0 {
+		return helper.ErrId
+	}
+
+	linha, err := c.repo.ResolverAlerta(ctx, repository.ResolverAlertaCaParams{
+		ID:       int32(id),
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		return fmt.Errorf("erro ao resolver alerta de CA, %w", err)
+	}
+
+	if linha == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+// caCancelado: situações do CAEPI em que o CA não pode mais ser usado
+func caCancelado(situacao string) bool {
+
+	return situacao == "CANCELADO" || situacao == "SUSPENSO"
+}
+
+func limitarTexto(s string, limite int) string {
+
+	r := []rune(s)
+	if len(r) <= limite {
+		return s
+	}
+	return string(r[:limite])
+}
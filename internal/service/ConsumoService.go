@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+type ConsumoRepository interface {
+	Relatorio(ctx context.Context, arg repository.RelatorioConsumoParams) ([]repository.RelatorioConsumoRow, error)
+}
+
+type ConsumoService struct {
+	repo ConsumoRepository
+}
+
+func NewConsumoService(r ConsumoRepository) *ConsumoService {
+	return &ConsumoService{repo: r}
+}
+
+type FiltroConsumo struct {
+	DataInicio   configs.DataBr `form:"data_inicio"`
+	DataFim      configs.DataBr `form:"data_fim"`
+	Departamento int32          `form:"departamento"`
+}
+
+// Relatorio soma o que foi entregue no período, agrupado pelas categorias da NR-6 e, dentro
+// delas, pelos tipos de proteção da empresa
+func (c *ConsumoService) Relatorio(ctx context.Context, f FiltroConsumo, tenantId int32) (model.RelatorioConsumoDto, error) {
+
+	if f.DataInicio.IsZero() || f.DataFim.IsZero() || f.DataFim.Time().Before(f.DataInicio.Time()) {
+		return model.RelatorioConsumoDto{}, helper.ErrPeriodoInvalido
+	}
+
+	linhas, err := c.repo.Relatorio(ctx, repository.RelatorioConsumoParams{
+		TenantID:       tenantId,
+		DataInicio:     pgtype.Date{Time: f.DataInicio.Time(), Valid: true},
+		DataFim:        pgtype.Date{Time: f.DataFim.Time(), Valid: true},
+		IDDepartamento: pgtype.Int4{Int32: f.Departamento, Valid: f.Departamento > 0},
+	})
+	if err != nil {
+
+		return model.RelatorioConsumoDto{}, fmt.Errorf("erro ao gerar relatorio de consumo, %w", err)
+	}
+
+	relatorio := model.RelatorioConsumoDto{
+		DataInicio: f.DataInicio,
+		DataFim:    f.DataFim,
+		ValorTotal: decimal.Zero,
+		Categorias: []model.ConsumoCategoriaDto{},
+	}
+
+	// as linhas vem ordenadas por categoria, então basta acompanhar a quebra
+	var categoria *model.ConsumoCategoriaDto
+	var idCategoria int32
+
+	for _, l := range linhas {
+
+		if categoria == nil || idCategoria != l.CategoriaID.Int32 {
+			relatorio.Categorias = append(relatorio.Categorias, model.ConsumoCategoriaDto{
+				Categoria:     categoriaProtecaoDto(l.CategoriaID, l.CategoriaCodigo, l.CategoriaNome),
+				ValorTotal:    decimal.Zero,
+				Subcategorias: []model.ConsumoProtecaoDto{},
+			})
+			categoria = &relatorio.Categorias[len(relatorio.Categorias)-1]
+			idCategoria = l.CategoriaID.Int32
+		}
+
+		valor := numericParaDecimal(l.ValorTotal)
+
+		categoria.Subcategorias = append(categoria.Subcategorias, model.ConsumoProtecaoDto{
+			Protecao:     model.TipoProtecaoDto{ID: int64(l.ProtecaoID), Nome: l.ProtecaoNome},
+			Quantidade:   int(l.Quantidade),
+			ValorTotal:   valor,
+			Funcionarios: int(l.Funcionarios),
+		})
+
+		categoria.Quantidade += int(l.Quantidade)
+		categoria.ValorTotal = categoria.ValorTotal.Add(valor)
+		relatorio.Quantidade += int(l.Quantidade)
+		relatorio.ValorTotal = relatorio.ValorTotal.Add(valor)
+	}
+
+	return relatorio, nil
+}
+
+func numericParaDecimal(n pgtype.Numeric) decimal.Decimal {
+
+	if !n.Valid || n.Int == nil {
+		return decimal.Zero
+	}
+
+	return decimal.NewFromBigInt(n.Int, n.Exp)
+}
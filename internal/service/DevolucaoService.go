@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 
@@ -18,8 +19,15 @@ type DevolucaoRepository interface {
 	AdicionarDevolucao(ctx context.Context,qtx *repository.Queries ,args repository.AddDevolucaoSimplesParams ) error
 	AdicionarTroca(ctx context.Context,qtx *repository.Queries  ,arg repository.AddTrocaEpiParams) (int32, error)
 	EntregaVinculada(ctx context.Context, qtx *repository.Queries ,arg repository.AddEntregaVinculadaParams) (int32, error)
-	Cancelar(ctx context.Context, qtx *repository.Queries, arg repository.CancelarDevolucaoParams) (int32, error)
+	Cancelar(ctx context.Context, qtx *repository.Queries, arg repository.CancelarDevolucaoParams) (repository.CancelarDevolucaoRow, error)
 	Listar(ctx context.Context, args repository.ListarDevolucoesParams) ([]repository.ListarDevolucoesRow, error)
+	AdicionarDocumento(ctx context.Context, qtx *repository.Queries, arg repository.AddDevolucaoDocumentoParams) (int32, error)
+	VincularAoDocumento(ctx context.Context, qtx *repository.Queries, arg repository.VincularDevolucaoDocumentoParams) error
+	VincularEntregaTroca(ctx context.Context, qtx *repository.Queries, arg repository.VincularEntregaTrocaDocumentoParams) error
+	BuscarDocumento(ctx context.Context, arg repository.BuscarDevolucaoDocumentoParams) (repository.BuscarDevolucaoDocumentoRow, error)
+	ItensDocumento(ctx context.Context, arg repository.ListarItensDevolucaoDocumentoParams) ([]repository.ListarItensDevolucaoDocumentoRow, error)
+	CancelarDocumento(ctx context.Context, qtx *repository.Queries, arg repository.CancelarDevolucaoDocumentoParams) (pgtype.Int4, error)
+	CancelarItensDocumento(ctx context.Context, qtx *repository.Queries, arg repository.CancelarDevolucoesDoDocumentoParams) ([]repository.CancelarDevolucoesDoDocumentoRow, error)
 }
 
 type DevolucaoService struct {
@@ -27,9 +35,10 @@ type DevolucaoService struct {
 	db          *pgxpool.Pool
 	queries     *repository.Queries
 	repoEntrega EntregaService
+	posse       PosseService
 }
 
-func NewDevolucaoService(d DevolucaoRepository, db *pgxpool.Pool, repoEntregaEpi EntregaService) *DevolucaoService {
+func NewDevolucaoService(d DevolucaoRepository, db *pgxpool.Pool, repoEntregaEpi EntregaService, posse PosseService) *DevolucaoService {
 
 	return &DevolucaoService{
 
@@ -37,6 +46,7 @@ func NewDevolucaoService(d DevolucaoRepository, db *pgxpool.Pool, repoEntregaEpi
 		db:          db,
 		queries:     repository.New(db),
 		repoEntrega: repoEntregaEpi,
+		posse:       posse,
 	}
 }
 
@@ -51,23 +61,50 @@ func (d *DevolucaoService) SalvarDevolucao(ctx context.Context, modelDevolucao m
 	defer tx.Rollback(ctx)
 	qtx := d.queries.WithTx(tx)
 
-	funcionario, err := d.queries.BuscaFuncionarioPorId(ctx, repository.BuscaFuncionarioPorIdParams{
+	if _, err := d.RegistrarDevolucao(ctx, qtx, modelDevolucao, tenantId); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RegistrarDevolucao grava a devolução (e a entrega da troca) na transação recebida e devolve o id
+func (d *DevolucaoService) RegistrarDevolucao(ctx context.Context, qtx *repository.Queries, modelDevolucao model.DevolucaoInserir, tenantId int32) (int32, error) {
+
+	return d.registrar(ctx, qtx, modelDevolucao, tenantId, true)
+}
+
+// registrar grava uma devolução. Com entregarTroca falso, o epi novo fica só anotado:
+// quem chama junta as trocas numa entrega só (caso do documento de devolução)
+func (d *DevolucaoService) registrar(ctx context.Context, qtx *repository.Queries, modelDevolucao model.DevolucaoInserir, tenantId int32, entregarTroca bool) (int32, error) {
+
+	funcionario, err := qtx.BuscaFuncionarioPorId(ctx, repository.BuscaFuncionarioPorIdParams{
 		ID: int32(modelDevolucao.IdFuncionario),
 		TenantID: tenantId,
 	})
 	if err != nil {
 
-		return err
+		if err == pgx.ErrNoRows {
+
+			return 0, helper.ErrNaoEncontrado
+		}
+		return 0, err
 	}
+	//só devolve o que de fato foi entregue a ele e ainda não voltou
+	err = d.posse.ValidarDevolucao(ctx, qtx, funcionario.ID, int32(modelDevolucao.IdEpi), int32(modelDevolucao.IdTamanho), modelDevolucao.QuantidadeADevolver, tenantId)
+	if err != nil {
+		return 0, err
+	}
+
 	token := helper.GerarTokenDevolucao(funcionario.Nome,funcionario.FuncaoNome, funcionario.DepartamentoNome,modelDevolucao.DataDevolucao.Time())
 
 	var idEpiNovo, IdTamanhoNovo, IdQuantidadeNova pgtype.Int4 //ponteiros caso item seja uma troca
 	//verifica se a devolucao, tambem é uma troca
 	if modelDevolucao.Troca {
 
-		if modelDevolucao.IdEpiNovo == nil {
+		if modelDevolucao.IdEpiNovo == nil || modelDevolucao.IdTamanhoNovo == nil || modelDevolucao.NovaQuantidade == nil {
 
-			return fmt.Errorf("id do novo epi é orbtigatorio")
+			return 0, fmt.Errorf("%w: novo epi, tamanho e quantidade são obrigatorios na troca", helper.ErrCampoObrigatorio)
 		}
 
 		idEpiNovo = pgtype.Int4{Int32: int32(*modelDevolucao.IdEpiNovo), Valid: true}
@@ -75,23 +112,50 @@ func (d *DevolucaoService) SalvarDevolucao(ctx context.Context, modelDevolucao m
 		IdQuantidadeNova = pgtype.Int4{Int32: int32(*modelDevolucao.NovaQuantidade), Valid: true}
 	}
 
-	//verificando o motivo da devolucao
-	/*caso venha com um desse 3 ids
-	desgaste, dano, vencimento, o epi nao É DEVOLVIDO PARA O ESTOQUE*/
-	EHDescarte := modelDevolucao.IdMotivo == 1 || modelDevolucao.IdMotivo == 2 || modelDevolucao.IdMotivo == 3
+	//o destino do epi devolvido vem configurado no motivo
+	motivo, err := qtx.BuscaMotivoDevolucao(ctx, repository.BuscaMotivoDevolucaoParams{
+		ID:       int32(modelDevolucao.IdMotivo),
+		TenantID: tenantId,
+	})
+	if err != nil {
 
-	//caso NAO SEJA UM DESCARTE
-	if !EHDescarte {
+		if err == pgx.ErrNoRows {
 
-		err := qtx.DevolverItemAoEstoque(ctx, repository.DevolverItemAoEstoqueParams{
+			return 0, fmt.Errorf("%w: motivo de devolução", helper.ErrNaoEncontrado)
+		}
+		return 0, err
+	}
+
+	/*descarte e higienização não voltam para o estoque: o primeiro sai de circulação
+	e o segundo vai para o pool de manutenção (ordem aberta logo abaixo).
+	Motivo que exige inspeção também segura o item fora do estoque até a ordem de inspeção ser concluída*/
+	inspecionar := motivo.ExigeInspecao && !motivo.Higienizacao
+
+	//o destino fica gravado na devolução: o motivo pode mudar depois e o cancelamento precisa desfazer o que foi feito
+	destino := model.DestinoDescarte
+	var idEntradaEstoque pgtype.Int4
+	switch {
+	case motivo.Higienizacao:
+		destino = model.DestinoHigienizacao
+	case inspecionar:
+		destino = model.DestinoInspecao
+	case motivo.VoltaEstoque:
+		destino = model.DestinoEstoque
+
+		idEntrada, err := qtx.DevolverItemAoEstoque(ctx, repository.DevolverItemAoEstoqueParams{
 			Idepi:           int32(modelDevolucao.IdEpi),
 			Idtamanho:       int32(modelDevolucao.IdTamanho),
 			Quantidadeatual: int32(modelDevolucao.QuantidadeADevolver),
 			TenantID: tenantId,
 		})
 		if err != nil {
-			return err
+
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, helper.ErrSemLoteDevolucao
+			}
+			return 0, err
 		}
+		idEntradaEstoque = pgtype.Int4{Int32: idEntrada, Valid: true}
 	}
 
 	arg := repository.AddTrocaEpiParams{
@@ -108,16 +172,39 @@ func (d *DevolucaoService) SalvarDevolucao(ctx context.Context, modelDevolucao m
 		AssinaturaDigital:     modelDevolucao.AssinaturaDigital,
 		IDUsuarioCancelamento: pgtype.Int4{Int32: int32(modelDevolucao.IdUser), Valid: true},
 		TokenValidacao: pgtype.Text{String: token, Valid: true},
-
+		Destino:               pgtype.Text{String: destino, Valid: true},
+		Identradaestoque:      idEntradaEstoque,
 	}
 	/*caso o primeiro if seja falso, quer dizer que é uma devolucao simples, sem troca*/
 	idDevolucao, err := d.repo.AdicionarTroca(ctx, qtx, arg) //add na tabela de devolucao
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	//higienização e inspeção: o item entra no pool de manutenção e só volta ao estoque quando a ordem for concluída
+	//(na higienização a inspeção acontece na própria conclusão da ordem)
+	if motivo.Higienizacao || inspecionar {
+
+		tipo := model.OrdemHigienizacao
+		if inspecionar {
+			tipo = model.OrdemInspecao
+		}
+
+		_, err := qtx.AbrirOrdemManutencao(ctx, repository.AbrirOrdemManutencaoParams{
+			TenantID:    tenantId,
+			Idepi:       int32(modelDevolucao.IdEpi),
+			Idtamanho:   int32(modelDevolucao.IdTamanho),
+			Quantidade:  int32(modelDevolucao.QuantidadeADevolver),
+			Iddevolucao: pgtype.Int4{Int32: idDevolucao, Valid: true},
+			Tipo:        tipo,
+		})
+		if err != nil {
+			return 0, helper.TraduzErroPostgres(err)
+		}
 	}
 
 	//segundo if para realização da entrega do novo epi
-	if modelDevolucao.Troca {
+	if modelDevolucao.Troca && entregarTroca {
 
 		idtrocaConvertido := int(idDevolucao)
 
@@ -135,25 +222,25 @@ func (d *DevolucaoService) SalvarDevolucao(ctx context.Context, modelDevolucao m
 				},
 			},
 		}
-		err := d.repoEntrega.RegistrarEntrega(ctx, qtx, modelentrega, tenantId)
+		_, err := d.repoEntrega.RegistrarEntrega(ctx, qtx, modelentrega, tenantId)
 		if err != nil {
 
-			return err
+			return 0, err
 		}
 	}
 
-	return tx.Commit(ctx)
+	return idDevolucao, nil
 }
 
 type FiltroDevolucao struct {
-	Canceladas           bool
-	EpiID                int32
-	DevolucaoID          int32
-	MatriculaFuncionario string
-	DataInicio           configs.DataBr
-	DataFim              configs.DataBr
-	Pagina               int32
-	Quantidade           int32
+	Canceladas           bool           `form:"canceladas"`
+	EpiID                int32          `form:"epi_id"`
+	DevolucaoID          int32          `form:"devolucao_id"`
+	MatriculaFuncionario string         `form:"matricula"`
+	DataInicio           configs.DataBr `form:"data_inicio"`
+	DataFim              configs.DataBr `form:"data_fim"`
+	Pagina               int32          `form:"pagina"`
+	Quantidade           int32          `form:"quantidade"`
 }
 
 type DevolucaoPaginada struct {
@@ -204,7 +291,7 @@ func (d *DevolucaoService) ListarDevolucoes(ctx context.Context, f FiltroDevoluc
 				Matricula: dev.Matricula,
 				Funcao: model.FuncaoDto{
 					ID:     int(dev.Idfuncao),
-					Funcao: dev.FuncNome,
+					Funcao: dev.FuncaoNome,
 					Departamento: model.DepartamentoDto{
 						ID:           int(dev.Iddepartamento),
 						Departamento: dev.DepNome,
@@ -280,6 +367,32 @@ func (d *DevolucaoService) ListarDevolucoes(ctx context.Context, f FiltroDevoluc
 	}, nil
 }
 
+// BuscarDevolucao traz uma devolução pelo id, esteja ela ativa ou cancelada
+func (d *DevolucaoService) BuscarDevolucao(ctx context.Context, id int, tenantId int32) (model.DevolucaoDto, error) {
+
+	if id <= 0 {
+		return model.DevolucaoDto{}, helper.ErrId
+	}
+
+	for _, canceladas := range []bool{false, true} {
+
+		pagina, err := d.ListarDevolucoes(ctx, FiltroDevolucao{
+			Canceladas:  canceladas,
+			DevolucaoID: int32(id),
+			Quantidade:  1,
+		}, tenantId)
+		if err != nil {
+			return model.DevolucaoDto{}, err
+		}
+
+		if len(pagina.Devolucoes) > 0 {
+			return pagina.Devolucoes[0], nil
+		}
+	}
+
+	return model.DevolucaoDto{}, helper.ErrNaoEncontrado
+}
+
 func (d *DevolucaoService) CancelarDevolucao(ctx context.Context, id, iduser, tenatId int) error {
 
 	if id <= 0 {
@@ -301,7 +414,26 @@ func (d *DevolucaoService) CancelarDevolucao(ctx context.Context, id, iduser, te
 	}
 
 	qtx := d.queries.WithTx(tx)
-	iddevolucao, err := d.repo.Cancelar(ctx, qtx, arg) //cancela a a devolucao e me retorna seu id
+	cancelada, err := d.repo.Cancelar(ctx, qtx, arg) //cancela a a devolucao e me retorna seu id
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return helper.ErrNaoEncontrado // não existe, é de outro tenant ou já foi cancelada
+		}
+		return err
+	}
+	iddevolucao := cancelada.ID
+
+	//o item volta para a posse do funcionario, então sai do lote que o recebeu
+	err = retirarDevolucaoDoEstoque(ctx, qtx, cancelada.Identradaestoque, cancelada.Quantidadeadevolver, arg.TenantID)
+	if err != nil {
+		return err
+	}
+
+	//o que ainda estava em higienização sai do pool junto com a devolução
+	err = cancelarOrdensDaDevolucao(ctx, qtx, repository.CancelarOrdensDaDevolucaoParams{
+		TenantID:    arg.TenantID,
+		IDDevolucao: pgtype.Int4{Int32: iddevolucao, Valid: true},
+	})
 	if err != nil {
 		return err
 	}
@@ -344,11 +476,311 @@ func (d *DevolucaoService) CancelarDevolucao(ctx context.Context, id, iduser, te
 
 		/*caso o erro seja diferente de ErrNorows, quer dizer que é um erro real do banco de dados, ai capturo ele*/
 		return fmt.Errorf("erro ao buscar entrega de troca, %w", err)
-	} else {
-		return fmt.Errorf("erro ao buscar entrega de troca, %w", err)
 	}
 
 	//caso o erro seja ErrNoRows, quer dizer que foi uma troca simples e nao teve uma entrega, entao ignoramos
 
 	return tx.Commit(ctx)
 }
+
+// SalvarDocumento grava um documento de devolução com vários itens numa transação só.
+// As trocas de todos os itens saem numa única entrega, ligada ao documento
+func (d *DevolucaoService) SalvarDocumento(ctx context.Context, doc model.DevolucaoDocumentoInserir, idUsuario int, tenantId int32) (int, error) {
+
+	tx, err := d.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback(ctx)
+	qtx := d.queries.WithTx(tx)
+
+	funcionario, err := qtx.BuscaFuncionarioPorId(ctx, repository.BuscaFuncionarioPorIdParams{
+		ID:       int32(doc.IdFuncionario),
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		if err == pgx.ErrNoRows {
+			return 0, helper.ErrNaoEncontrado
+		}
+		return 0, err
+	}
+
+	token := helper.GerarTokenDevolucao(funcionario.Nome, funcionario.FuncaoNome, funcionario.DepartamentoNome, doc.DataDevolucao.Time())
+
+	idDocumento, err := d.repo.AdicionarDocumento(ctx, qtx, repository.AddDevolucaoDocumentoParams{
+		TenantID:          tenantId,
+		Idfuncionario:     funcionario.ID,
+		DataDevolucao:     pgtype.Date{Time: doc.DataDevolucao.Time(), Valid: true},
+		AssinaturaDigital: doc.AssinaturaDigital,
+		TokenValidacao:    pgtype.Text{String: token, Valid: true},
+		Idusuario:         pgtype.Int4{Int32: int32(idUsuario), Valid: true},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	trocas := make([]model.ItemParaInserir, 0, len(doc.Itens))
+
+	for i, item := range doc.Itens {
+
+		devolucao := model.DevolucaoInserir{
+			IdFuncionario:       doc.IdFuncionario,
+			IdEpi:               item.IdEpi,
+			IdMotivo:            item.IdMotivo,
+			IdTamanho:           item.IdTamanho,
+			DataDevolucao:       doc.DataDevolucao,
+			QuantidadeADevolver: item.Quantidade,
+			AssinaturaDigital:   doc.AssinaturaDigital,
+			IdUser:              idUsuario,
+		}
+
+		if item.Troca != nil {
+
+			idEpiNovo, idTamanhoNovo, quantidadeNova := int(item.Troca.ID_epi), int(item.Troca.ID_tamanho), item.Troca.Quantidade
+			devolucao.Troca = true
+			devolucao.IdEpiNovo = &idEpiNovo
+			devolucao.IdTamanhoNovo = &idTamanhoNovo
+			devolucao.NovaQuantidade = &quantidadeNova
+
+			trocas = append(trocas, *item.Troca)
+		}
+
+		idDevolucao, err := d.registrar(ctx, qtx, devolucao, tenantId, false)
+		if err != nil {
+			return 0, fmt.Errorf("item %d: %w", i+1, err)
+		}
+
+		err = d.repo.VincularAoDocumento(ctx, qtx, repository.VincularDevolucaoDocumentoParams{
+			Iddocumento: pgtype.Int4{Int32: idDocumento, Valid: true},
+			ID:          idDevolucao,
+			TenantID:    tenantId,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if len(trocas) > 0 {
+
+		idEntrega, err := d.repoEntrega.RegistrarEntrega(ctx, qtx, model.EntregaParaInserir{
+			ID_funcionario:     int64(funcionario.ID),
+			Id_user:            idUsuario,
+			Data_entrega:       doc.DataDevolucao,
+			Assinatura_Digital: doc.AssinaturaDigital,
+			Itens:              trocas,
+		}, tenantId)
+		if err != nil {
+			return 0, fmt.Errorf("entrega da troca: %w", err)
+		}
+
+		err = d.repo.VincularEntregaTroca(ctx, qtx, repository.VincularEntregaTrocaDocumentoParams{
+			Identregatroca: pgtype.Int4{Int32: idEntrega, Valid: true},
+			ID:             idDocumento,
+			TenantID:       tenantId,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int(idDocumento), nil
+}
+
+func (d *DevolucaoService) BuscarDocumento(ctx context.Context, id int, tenantId int32) (model.DevolucaoDocumentoDto, error) {
+
+	if id <= 0 {
+		return model.DevolucaoDocumentoDto{}, helper.ErrId
+	}
+
+	doc, err := d.repo.BuscarDocumento(ctx, repository.BuscarDevolucaoDocumentoParams{
+		ID:       int32(id),
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DevolucaoDocumentoDto{}, helper.ErrNaoEncontrado
+		}
+		return model.DevolucaoDocumentoDto{}, err
+	}
+
+	itens, err := d.repo.ItensDocumento(ctx, repository.ListarItensDevolucaoDocumentoParams{
+		Iddocumento: pgtype.Int4{Int32: doc.ID, Valid: true},
+		TenantID:    tenantId,
+	})
+	if err != nil {
+		return model.DevolucaoDocumentoDto{}, err
+	}
+
+	dto := model.DevolucaoDocumentoDto{
+		Id: int(doc.ID),
+		Funcionario: model.Funcionario_Dto{
+			ID:        int(doc.FuncID),
+			Nome:      doc.FuncNome,
+			Matricula: doc.Matricula,
+		},
+		DataDevolucao: configs.DataBr(doc.DataDevolucao.Time),
+		CriadoEm:      configs.DataBr(doc.CriadoEm.Time),
+		Itens:         make([]model.ItemDevolucaoDocumentoDto, 0, len(itens)),
+	}
+
+	if doc.Identregatroca.Valid {
+		idEntrega := int(doc.Identregatroca.Int32)
+		dto.IdEntregaTroca = &idEntrega
+	}
+	if doc.CanceladaEm.Valid {
+		cancelada := configs.DataBr(doc.CanceladaEm.Time)
+		dto.CanceladaEm = &cancelada
+	}
+
+	for _, item := range itens {
+
+		i := model.ItemDevolucaoDocumentoDto{
+			IdDevolucao: int(item.ID),
+			IdEpi:       int(item.EpiID),
+			Epi:         item.EpiNome,
+			IdTamanho:   int(item.TamID),
+			Tamanho:     item.TamNome,
+			Motivo: model.MotivoDevolucaoEpiDto{
+				Id:     int(item.MotivoID),
+				Motivo: item.Motivo,
+			},
+			Quantidade: int(item.Quantidadeadevolver),
+		}
+
+		if item.Idepinovo.Valid {
+			i.Troca = &model.ItemTrocaDto{
+				IdEpi:      int(item.Idepinovo.Int32),
+				Epi:        item.EpiNovoNome.String,
+				IdTamanho:  int(item.Idtamanhonovo.Int32),
+				Tamanho:    item.TamNovoNome.String,
+				Quantidade: int(item.Quantidadenova.Int32),
+			}
+		}
+
+		dto.Itens = append(dto.Itens, i)
+	}
+
+	return dto, nil
+}
+
+// CancelarDocumento cancela todas as devoluções do documento, tira do estoque os itens que tinham voltado a ele
+// e cancela a entrega das trocas, repondo o estoque dela
+func (d *DevolucaoService) CancelarDocumento(ctx context.Context, id, idUsuario int, tenantId int32) error {
+
+	if id <= 0 {
+		return helper.ErrId
+	}
+
+	tx, err := d.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+	qtx := d.queries.WithTx(tx)
+
+	usuario := pgtype.Int4{Int32: int32(idUsuario), Valid: true}
+
+	idEntregaTroca, err := d.repo.CancelarDocumento(ctx, qtx, repository.CancelarDevolucaoDocumentoParams{
+		ID:                    int32(id),
+		IDUsuarioCancelamento: usuario,
+		TenantID:              tenantId,
+	})
+	if err != nil {
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return helper.ErrNaoEncontrado // não existe, é de outro tenant ou já foi cancelado
+		}
+		return err
+	}
+
+	itens, err := d.repo.CancelarItensDocumento(ctx, qtx, repository.CancelarDevolucoesDoDocumentoParams{
+		Iddocumento:                    pgtype.Int4{Int32: int32(id), Valid: true},
+		IDUsuarioDevolucaoCancelamento: usuario,
+		TenantID:                       tenantId,
+	})
+	if err != nil {
+		return err
+	}
+
+	//cada item que tinha voltado ao estoque sai do lote que o recebeu
+	for _, item := range itens {
+
+		err = retirarDevolucaoDoEstoque(ctx, qtx, item.Identradaestoque, item.Quantidadeadevolver, tenantId)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = cancelarOrdensDaDevolucao(ctx, qtx, repository.CancelarOrdensDaDevolucaoParams{
+		TenantID:    tenantId,
+		IDDocumento: pgtype.Int4{Int32: int32(id), Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	if idEntregaTroca.Valid {
+
+		err := d.repoEntrega.RegistrarCancelamento(ctx, qtx, int(tenantId), int(idEntregaTroca.Int32), idUsuario)
+		if err != nil {
+			return fmt.Errorf("erro ao cancelar a entrega da troca, %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// retirarDevolucaoDoEstoque desfaz a volta ao estoque de uma devolução cancelada, no mesmo lote que recebeu o item.
+// Se o lote já não tem a quantidade (o item foi entregue de novo) o cancelamento é recusado
+func retirarDevolucaoDoEstoque(ctx context.Context, qtx *repository.Queries, idEntrada pgtype.Int4, quantidade int32, tenantId int32) error {
+
+	if !idEntrada.Valid {
+		return nil
+	}
+
+	linhas, err := qtx.AbaterEstoqueLote(ctx, repository.AbaterEstoqueLoteParams{
+		Quantidadeatual: quantidade,
+		ID:              idEntrada.Int32,
+		TenantID:        tenantId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if linhas == 0 {
+		return fmt.Errorf("%w: o lote %d já não tem as %d unidades devolvidas", helper.ErrEstoqueInsuficiente, idEntrada.Int32, quantidade)
+	}
+
+	return nil
+}
+
+// cancelarOrdensDaDevolucao tira do pool as ordens ainda abertas. Se alguma já foi concluída o cancelamento é recusado:
+// a liberada já devolveu o item ao estoque e a descartada tirou de circulação, e cancelar a devolução
+// devolveria o item à posse do funcionario (contado duas vezes)
+func cancelarOrdensDaDevolucao(ctx context.Context, qtx *repository.Queries, arg repository.CancelarOrdensDaDevolucaoParams) error {
+
+	ordens, err := qtx.TravarOrdensDaDevolucao(ctx, repository.TravarOrdensDaDevolucaoParams{
+		TenantID:    arg.TenantID,
+		IDDevolucao: arg.IDDevolucao,
+		IDDocumento: arg.IDDocumento,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, ordem := range ordens {
+		if ordem.Status == model.OrdemLiberada || ordem.Status == model.OrdemDescartada {
+			return fmt.Errorf("%w (ordem %d)", helper.ErrOrdemConcluida, ordem.ID)
+		}
+	}
+
+	return qtx.CancelarOrdensDaDevolucao(ctx, arg)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
 	"github.com/stretchr/testify/require"
 )
@@ -21,7 +22,7 @@ func TestSalvarDevolucao(t *testing.T) {
 	repo := repository.NewDevolucaoRepository(db)
 	repoEntregaImpl := repository.NewEntregaRepository(db)
 	servEntrega := NewEntregaService(repoEntregaImpl, db)
-	servDevolucao := NewDevolucaoService(repo, db, *servEntrega)
+	servDevolucao := NewDevolucaoService(repo, db, *servEntrega, *NewPosseService(repository.NewPosseRepository(db)))
 
 	// 3. Criação dos Dados Auxiliares (SaaS: O Tenant vem primeiro)
 	idEmpresa := CreateEmpresa(t, db) // Novo Helper Mestre
@@ -151,7 +152,7 @@ func TestCancelarDevolucao(t *testing.T) {
 	repo := repository.NewDevolucaoRepository(db)
 	repoEntregaImpl := repository.NewEntregaRepository(db)
 	servEntrega := NewEntregaService(repoEntregaImpl, db)
-	servDevolucao := NewDevolucaoService(repo, db, *servEntrega)
+	servDevolucao := NewDevolucaoService(repo, db, *servEntrega, *NewPosseService(repository.NewPosseRepository(db)))
 
 	// 2. Helpers (Cenário SaaS Completo)
 	idEmpresa := CreateEmpresa(t, db) // Tenant Isolation
@@ -241,5 +242,140 @@ func TestCancelarDevolucao(t *testing.T) {
 		require.Equal(t, qtdAntesCancelamento+1, qtdDepoisCancelamento,
 			"O estoque deveria ter sido reposto. Antes: %d, Depois: %d",
 			qtdAntesCancelamento, qtdDepoisCancelamento)
+
+		// o item velho devolvido por "Dano" (volta_estoque) tinha voltado ao lote; o cancelamento tira ele de lá
+		var qtdVelhaDepois int
+		err = db.QueryRow(ctx, "SELECT quantidadeAtual FROM entrada_epi WHERE id = $1 AND tenant_id = $2", idEntradaVelha, idEmpresa).Scan(&qtdVelhaDepois)
+		require.NoError(t, err)
+		require.Equal(t, 100, qtdVelhaDepois, "o item devolvido não deveria continuar contado no estoque depois do cancelamento")
+	})
+}
+
+func TestDevolucaoDocumento(t *testing.T) {
+
+	db := SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	servEntrega := NewEntregaService(repository.NewEntregaRepository(db), db)
+	servPosse := NewPosseService(repository.NewPosseRepository(db))
+	servDevolucao := NewDevolucaoService(repository.NewDevolucaoRepository(db), db, *servEntrega, *servPosse)
+
+	idEmpresa := CreateEmpresa(t, db)
+	iduser := CreateUser(t, db, idEmpresa)
+	iddep := CreateDepartamento(t, db, idEmpresa)
+	IdFuncao := CreateFuncao(t, db, iddep, idEmpresa)
+	idprotec := CreateProtecao(t, db, idEmpresa)
+	idtam := CreateTamanho(t, db, idEmpresa)
+	idfuncionario := CreateFuncionario(t, db, iddep, IdFuncao, idEmpresa)
+
+	idLuva := CreateEpi(t, db, idprotec, idEmpresa)
+	idBota := CreateEpi(t, db, idprotec, idEmpresa)
+
+	Idfornecedor := CreateFornecedor(t, db, idEmpresa)
+	idEntradaLuva := CreateEntradaEpi(t, db, idfuncionario, idLuva, idprotec, idtam, Idfornecedor, iduser, idEmpresa)
+	idEntradaBota := CreateEntradaEpi(t, db, idfuncionario, idBota, idprotec, idtam, Idfornecedor, iduser, idEmpresa)
+
+	// o funcionario está com 10 luvas e 10 botas
+	idEntrega := CreateEntregaEpi(t, db, idfuncionario, iduser, idEmpresa)
+	_ = CreateEpiEntregues(t, db, idEntrega, idEntradaLuva, idLuva, idtam, idEmpresa)
+	_ = CreateEpiEntregues(t, db, idEntrega, idEntradaBota, idBota, idtam, idEmpresa)
+
+	idMotivoDesgaste := CreateMotivoDevolucao(t, db, "Desgaste Natural", idEmpresa)
+	idMotivoDescarte := CreateMotivoDevolucao(t, db, "Dano sem conserto", idEmpresa)
+	_, err := db.Exec(ctx, "UPDATE motivo_devolucao SET volta_estoque = FALSE, descarte = TRUE WHERE id = $1", idMotivoDescarte)
+	require.NoError(t, err)
+
+	estoque := func(idEntrada int64) int {
+
+		var quantidade int
+		err := db.QueryRow(ctx, "SELECT quantidadeAtual FROM entrada_epi WHERE id = $1 AND tenant_id = $2", idEntrada, idEmpresa).Scan(&quantidade)
+		require.NoError(t, err)
+		return quantidade
+	}
+
+	posse := func() map[int]int {
+
+		itens, err := servPosse.Listar(ctx, int(idfuncionario), int32(idEmpresa))
+		require.NoError(t, err)
+
+		porEpi := make(map[int]int, len(itens))
+		for _, item := range itens {
+			porEpi[item.IdEpi] = item.Quantidade
+		}
+		return porEpi
+	}
+
+	var idDocumento int
+
+	t.Run("documento com vários itens aplica o motivo de cada item e entrega as trocas juntas", func(t *testing.T) {
+
+		idDocumento, err = servDevolucao.SalvarDocumento(ctx, model.DevolucaoDocumentoInserir{
+			IdFuncionario:     int(idfuncionario),
+			DataDevolucao:     *configs.NewDataBrPtr(time.Now()),
+			AssinaturaDigital: "assinatura_base64_teste",
+			Itens: []model.ItemDevolucaoInserir{
+				{
+					IdEpi:      int(idLuva),
+					IdTamanho:  int(idtam),
+					IdMotivo:   int(idMotivoDesgaste),
+					Quantidade: 2,
+					Troca:      &model.ItemParaInserir{ID_epi: idBota, ID_tamanho: idtam, Quantidade: 1},
+				},
+				{
+					IdEpi:      int(idBota),
+					IdTamanho:  int(idtam),
+					IdMotivo:   int(idMotivoDescarte),
+					Quantidade: 3,
+				},
+			},
+		}, int(iduser), int32(idEmpresa))
+		require.NoError(t, err)
+
+		var itens int
+		err = db.QueryRow(ctx, "SELECT count(*) FROM devolucao WHERE IdDocumento = $1 AND tenant_id = $2", idDocumento, idEmpresa).Scan(&itens)
+		require.NoError(t, err)
+		require.Equal(t, 2, itens)
+
+		var temEntregaTroca bool
+		err = db.QueryRow(ctx, "SELECT IdEntregaTroca IS NOT NULL FROM devolucao_documento WHERE id = $1 AND tenant_id = $2", idDocumento, idEmpresa).Scan(&temEntregaTroca)
+		require.NoError(t, err)
+		require.True(t, temEntregaTroca, "a troca deveria ter gerado uma entrega ligada ao documento")
+
+		// luva por desgaste volta ao estoque; bota descartada não volta, e a bota da troca sai do estoque
+		require.Equal(t, 102, estoque(idEntradaLuva))
+		require.Equal(t, 99, estoque(idEntradaBota))
+
+		require.Equal(t, map[int]int{int(idLuva): 8, int(idBota): 8}, posse())
+	})
+
+	t.Run("cancelar o documento desfaz as devoluções e a entrega da troca", func(t *testing.T) {
+
+		err := servDevolucao.CancelarDocumento(ctx, idDocumento, int(iduser), int32(idEmpresa))
+		require.NoError(t, err)
+
+		var ativas int
+		err = db.QueryRow(ctx, "SELECT count(*) FROM devolucao WHERE IdDocumento = $1 AND tenant_id = $2 AND cancelada_em IS NULL", idDocumento, idEmpresa).Scan(&ativas)
+		require.NoError(t, err)
+		require.Equal(t, 0, ativas)
+
+		var trocaCancelada bool
+		err = db.QueryRow(ctx, `
+			SELECT e.cancelada_em IS NOT NULL
+			FROM devolucao_documento d
+			INNER JOIN entrega_epi e ON e.id = d.IdEntregaTroca
+			WHERE d.id = $1 AND d.tenant_id = $2`, idDocumento, idEmpresa).Scan(&trocaCancelada)
+		require.NoError(t, err)
+		require.True(t, trocaCancelada)
+
+		require.Equal(t, 100, estoque(idEntradaLuva), "a luva devolvida voltou para a posse e não deveria continuar no estoque")
+		require.Equal(t, 100, estoque(idEntradaBota), "a bota da troca deveria ter voltado ao estoque")
+		require.Equal(t, map[int]int{int(idLuva): 10, int(idBota): 10}, posse())
+	})
+
+	t.Run("ERRO - documento já cancelado não é cancelado de novo", func(t *testing.T) {
+
+		err := servDevolucao.CancelarDocumento(ctx, idDocumento, int(iduser), int32(idEmpresa))
+		require.ErrorIs(t, err, helper.ErrNaoEncontrado)
 	})
 }
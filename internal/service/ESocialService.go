@@ -0,0 +1,476 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ESocialRepository interface {
+	DefinirFuncao(ctx context.Context, arg repository.DefinirFuncaoEsocialParams) (int64, error)
+	Empregador(ctx context.Context, tenantId int32) (repository.BuscarEmpregadorEsocialRow, error)
+	ExposicoesS2240(ctx context.Context, arg repository.ListarExposicoesS2240Params) ([]repository.ListarExposicoesS2240Row, error)
+}
+
+type ESocialService struct {
+	repo ESocialRepository
+	xsd  string // evtExpRisco.xsd do pacote oficial, junto dos xsd que ele importa
+}
+
+// NewESocialService lê o pacote de xsd de ESOCIAL_XSD_DIR ou, se vazia, da pasta esocial/xsd do projeto
+func NewESocialService(r ESocialRepository) *ESocialService {
+
+	dir := os.Getenv("ESOCIAL_XSD_DIR")
+	if dir == "" {
+		dir = diretorioXsdPadrao
+	}
+
+	return &ESocialService{repo: r, xsd: filepath.Join(dir, "evtExpRisco.xsd")}
+}
+
+const (
+	namespaceS2240 = "http://www.esocial.gov.br/schema/evt/evtExpRisco/v_S_01_02_00"
+	versaoProcesso = "gestao-epi-1.0"
+
+	diretorioXsdPadrao = "esocial/xsd"
+
+	// 09.01.001 = ausência de agente nocivo; com ele o leiaute não aceita informar epi
+	agenteAusente = "09.01.001"
+	maximoEpis    = 50
+)
+
+var codigoAgenteNocivo = regexp.MustCompile(`^\d{2}\.\d{2}\.\d{3}$`)
+
+// Estrutura do evtExpRisco (S-2240), só com os grupos que preenchemos a partir das entregas
+type eventoS2240 struct {
+	XMLName xml.Name    `xml:"eSocial"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Evento  evtExpRisco `xml:"evtExpRisco"`
+}
+
+type evtExpRisco struct {
+	Id            string            `xml:"Id,attr"`
+	IdeEvento     ideEventoS2240    `xml:"ideEvento"`
+	IdeEmpregador ideEmpregador     `xml:"ideEmpregador"`
+	IdeVinculo    ideVinculo        `xml:"ideVinculo"`
+	InfoExpRisco  infoExpRiscoS2240 `xml:"infoExpRisco"`
+}
+
+type ideEventoS2240 struct {
+	IndRetif int    `xml:"indRetif"`
+	TpAmb    int    `xml:"tpAmb"`
+	ProcEmi  int    `xml:"procEmi"`
+	VerProc  string `xml:"verProc"`
+}
+
+type ideEmpregador struct {
+	TpInsc int    `xml:"tpInsc"`
+	NrInsc string `xml:"nrInsc"`
+}
+
+type ideVinculo struct {
+	CpfTrab   string `xml:"cpfTrab"`
+	Matricula string `xml:"matricula"`
+}
+
+type infoExpRiscoS2240 struct {
+	DtIniCondicao string   `xml:"dtIniCondicao"`
+	InfoAmb       infoAmb  `xml:"infoAmb"`
+	InfoAtiv      infoAtiv `xml:"infoAtiv"`
+	AgNoc         agNoc    `xml:"agNoc"`
+	RespReg       respReg  `xml:"respReg"`
+}
+
+type infoAmb struct {
+	LocalAmb int    `xml:"localAmb"`
+	DscSetor string `xml:"dscSetor"`
+	TpInsc   int    `xml:"tpInsc"`
+	NrInsc   string `xml:"nrInsc"`
+}
+
+type infoAtiv struct {
+	DscAtivDes string `xml:"dscAtivDes"`
+}
+
+type agNoc struct {
+	CodAgNoc string `xml:"codAgNoc"`
+	TpAval   int    `xml:"tpAval"`
+	EpcEpi   epcEpi `xml:"epcEpi"`
+}
+
+type epcEpi struct {
+	UtilizEPC int        `xml:"utilizEPC"`
+	UtilizEPI int        `xml:"utilizEPI"`
+	EficEpi   string     `xml:"eficEpi"`
+	Epi       []epiS2240 `xml:"epi"`
+	EpiCompl  epiCompl   `xml:"epiCompl"`
+}
+
+type epiS2240 struct {
+	DocAval string `xml:"docAval"`
+}
+
+// epiCompl são as declarações do empregador sobre o uso do epi. O sistema controla validade do CA,
+// periodicidade de troca e higienização, por isso todas saem como S
+type epiCompl struct {
+	MedProtecao   string `xml:"medProtecao"`
+	CondFuncto    string `xml:"condFuncto"`
+	UsoInint      string `xml:"usoInint"`
+	PrzValid      string `xml:"przValid"`
+	PeriodicTroca string `xml:"periodicTroca"`
+	Higienizacao  string `xml:"higienizacao"`
+}
+
+type respReg struct {
+	CpfResp string `xml:"cpfResp"`
+	IdeOC   int    `xml:"ideOC"`
+	DscOC   string `xml:"dscOC,omitempty"`
+	NrOC    string `xml:"nrOC"`
+	UfOC    string `xml:"ufOC"`
+}
+
+func (e *ESocialService) DefinirFuncao(ctx context.Context, idFuncao int, dados model.FuncaoEsocial, tenantId int32) error {
+
+	if idFuncao <= 0 {
+		return helper.ErrId
+	}
+
+	linhas, err := e.repo.DefinirFuncao(ctx, repository.DefinirFuncaoEsocialParams{
+		CodAgenteNocivo:    strings.TrimSpace(dados.CodAgenteNocivo),
+		DescricaoAtividade: strings.TrimSpace(dados.DescricaoAtividade),
+		IDFuncao:           int32(idFuncao),
+		TenantID:           tenantId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if linhas == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+// ExportarS2240 monta um evento por funcionario + função da época com os CAs entregues no período e devolve
+// um zip com os xml. Cada evento passa pela conferência do cadastro e depois pelo xsd oficial;
+// se qualquer um falhar, nada é gerado e a lista de erros volta
+func (e *ESocialService) ExportarS2240(ctx context.Context, req model.ExportacaoS2240, tenantId int32) ([]byte, []model.ErroExportacaoS2240, error) {
+
+	if req.DataInicio.IsZero() || req.DataFim.IsZero() || req.DataFim.Time().Before(req.DataInicio.Time()) {
+		return nil, nil, helper.ErrPeriodoInvalido
+	}
+
+	empregador, err := e.repo.Empregador(ctx, tenantId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, helper.ErrNaoEncontrado
+		}
+		return nil, nil, err
+	}
+
+	var ids []int32
+	for _, id := range req.Funcionarios {
+		ids = append(ids, int32(id))
+	}
+
+	exposicoes, err := e.repo.ExposicoesS2240(ctx, repository.ListarExposicoesS2240Params{
+		TenantID:        tenantId,
+		DataInicio:      pgtype.Date{Time: req.DataInicio.Time(), Valid: true},
+		DataFim:         pgtype.Date{Time: req.DataFim.Time(), Valid: true},
+		IdsFuncionarios: ids,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(exposicoes) == 0 {
+		return nil, nil, helper.ErrNaoEncontrado
+	}
+
+	cnpj := somenteDigitos(empregador.Cnpj)
+	geradoEm := time.Now()
+
+	var eventos []eventoS2240
+	var donos []repository.ListarExposicoesS2240Row
+	var erros []model.ErroExportacaoS2240
+
+	// as linhas vêm ordenadas por funcionario e função, cada troca de par abre um evento novo
+	for i, exp := range exposicoes {
+
+		if i > 0 && exposicoes[i-1].FuncionarioID == exp.FuncionarioID && exposicoes[i-1].FuncaoID == exp.FuncaoID {
+			ultimo := &eventos[len(eventos)-1].Evento.InfoExpRisco
+			ultimo.AgNoc.EpcEpi.Epi = append(ultimo.AgNoc.EpcEpi.Epi, epiS2240{DocAval: strings.TrimSpace(exp.Ca)})
+			if exp.InicioCondicao.Valid && exp.InicioCondicao.Time.Format("2006-01-02") < ultimo.DtIniCondicao {
+				ultimo.DtIniCondicao = exp.InicioCondicao.Time.Format("2006-01-02")
+			}
+			continue
+		}
+
+		eventos = append(eventos, montarS2240(exp, cnpj, req, geradoEm, len(eventos)+1))
+		donos = append(donos, exp)
+	}
+
+	documentos := make([][]byte, len(eventos))
+	for i, evento := range eventos {
+
+		// a conferência do cadastro vem antes: as mensagens dela o usuário consegue corrigir sozinho
+		problemas := validarS2240(evento)
+		if len(problemas) == 0 {
+
+			conteudo, err := xml.MarshalIndent(evento, "", "  ")
+			if err != nil {
+				return nil, nil, err
+			}
+			documentos[i] = append([]byte(xml.Header), conteudo...)
+
+			problemas, err = validarXsd(ctx, e.xsd, documentos[i])
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		if len(problemas) == 0 {
+			continue
+		}
+
+		dono := donos[i]
+		erros = append(erros, model.ErroExportacaoS2240{
+			IdFuncionario: int(dono.FuncionarioID),
+			Nome:          dono.Nome,
+			Matricula:     dono.Matricula,
+			Erros:         problemas,
+		})
+	}
+
+	if len(erros) > 0 {
+		return nil, erros, helper.ErrExportacaoEsocial
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for i, documento := range documentos {
+
+		nome := fmt.Sprintf("S-2240_%s_%03d.xml", donos[i].Matricula, i+1)
+		w, err := zw.Create(nome)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if _, err := w.Write(documento); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	return buf.Bytes(), nil, nil
+}
+
+func somenteDigitos(s string) string {
+
+	return strings.Map(func(r rune) rune {
+		if r < '0' || r > '9' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func montarS2240(exp repository.ListarExposicoesS2240Row, cnpj string, req model.ExportacaoS2240, geradoEm time.Time, sequencia int) eventoS2240 {
+
+	inicio := req.DataInicio.Time()
+	if exp.InicioCondicao.Valid {
+		inicio = exp.InicioCondicao.Time
+	}
+
+	// o empregador é identificado pela raiz do CNPJ (8 dígitos)
+	raiz := cnpj
+	if len(raiz) > 8 {
+		raiz = raiz[:8]
+	}
+	idInscricao := raiz + strings.Repeat("0", 14-len(raiz))
+
+	return eventoS2240{
+		Xmlns: namespaceS2240,
+		Evento: evtExpRisco{
+			// ID + tipo de inscrição + inscrição completada com zeros até 14 posições + data/hora + sequencial
+			Id: fmt.Sprintf("ID1%s%s%05d", idInscricao, geradoEm.Format("20060102150405"), sequencia),
+			IdeEvento: ideEventoS2240{
+				IndRetif: 1,
+				TpAmb:    req.Ambiente,
+				ProcEmi:  1,
+				VerProc:  versaoProcesso,
+			},
+			IdeEmpregador: ideEmpregador{TpInsc: 1, NrInsc: raiz},
+			IdeVinculo: ideVinculo{
+				CpfTrab:   exp.Cpf.String,
+				Matricula: exp.Matricula,
+			},
+			InfoExpRisco: infoExpRiscoS2240{
+				DtIniCondicao: inicio.Format("2006-01-02"),
+				InfoAmb: infoAmb{
+					LocalAmb: 1,
+					DscSetor: exp.DepartamentoNome,
+					TpInsc:   1,
+					NrInsc:   cnpj,
+				},
+				InfoAtiv: infoAtiv{DscAtivDes: exp.DescricaoAtividade.String},
+				AgNoc: agNoc{
+					CodAgNoc: exp.CodAgenteNocivo.String,
+					TpAval:   2,
+					EpcEpi: epcEpi{
+						UtilizEPC: 0,
+						UtilizEPI: 2,
+						EficEpi:   "S",
+						Epi:       []epiS2240{{DocAval: strings.TrimSpace(exp.Ca)}},
+						EpiCompl: epiCompl{
+							MedProtecao:   "S",
+							CondFuncto:    "S",
+							UsoInint:      "S",
+							PrzValid:      "S",
+							PeriodicTroca: "S",
+							Higienizacao:  "S",
+						},
+					},
+				},
+				RespReg: respReg{
+					CpfResp: helper.SomenteDigitosCPF(req.Responsavel.Cpf),
+					IdeOC:   req.Responsavel.IdeOC,
+					DscOC:   strings.TrimSpace(req.Responsavel.DscOC),
+					NrOC:    strings.TrimSpace(req.Responsavel.NrOC),
+					UfOC:    strings.ToUpper(req.Responsavel.UfOC),
+				},
+			},
+		},
+	}
+}
+
+// validarS2240 confere só as regras do leiaute S-1.2 que dependem do cadastro (tamanhos, formatos e
+// obrigatoriedades dos campos que o sistema preenche), com mensagens que o usuário consegue corrigir.
+// A validação completa do leiaute é a do validarXsd, que roda depois dela
+func validarS2240(evento eventoS2240) []string {
+
+	problemas := []string{}
+	ev := evento.Evento
+	info := ev.InfoExpRisco
+
+	tamanho := func(campo, valor string, min, max int) {
+		n := utf8.RuneCountInString(valor)
+		if n < min || n > max {
+			problemas = append(problemas, fmt.Sprintf("%s deve ter entre %d e %d caracteres", campo, min, max))
+		}
+	}
+
+	if !helper.IsCNPJ(info.InfoAmb.NrInsc) {
+		problemas = append(problemas, "CNPJ da empresa inválido")
+	}
+	if ev.IdeVinculo.CpfTrab == "" {
+		problemas = append(problemas, "funcionario sem CPF cadastrado")
+	} else if !helper.IsCPF(ev.IdeVinculo.CpfTrab) {
+		problemas = append(problemas, "CPF do funcionario inválido")
+	}
+	tamanho("matrícula", ev.IdeVinculo.Matricula, 1, 30)
+	tamanho("departamento (dscSetor)", info.InfoAmb.DscSetor, 1, 100)
+
+	if info.InfoAtiv.DscAtivDes == "" || info.AgNoc.CodAgNoc == "" {
+		problemas = append(problemas, "função sem dados do eSocial (agente nocivo e descrição da atividade)")
+	} else {
+		tamanho("descrição da atividade", info.InfoAtiv.DscAtivDes, 1, 999)
+		if !codigoAgenteNocivo.MatchString(info.AgNoc.CodAgNoc) {
+			problemas = append(problemas, "código do agente nocivo fora do formato da tabela 24 (00.00.000)")
+		}
+		if info.AgNoc.CodAgNoc == agenteAusente {
+			problemas = append(problemas, "com o agente 09.01.001 (ausência de agente nocivo) o S-2240 não aceita epi")
+		}
+	}
+
+	if len(info.AgNoc.EpcEpi.Epi) > maximoEpis {
+		problemas = append(problemas, fmt.Sprintf("o leiaute aceita no máximo %d epis por agente nocivo", maximoEpis))
+	}
+	for _, epi := range info.AgNoc.EpcEpi.Epi {
+		if epi.DocAval == "" {
+			problemas = append(problemas, "epi entregue sem CA cadastrado")
+			break
+		}
+		tamanho("CA (docAval)", epi.DocAval, 1, 255)
+	}
+
+	if !helper.IsCPF(info.RespReg.CpfResp) {
+		problemas = append(problemas, "CPF do responsável pelos registros ambientais inválido")
+	}
+	tamanho("número do órgão de classe do responsável", info.RespReg.NrOC, 1, 14)
+	if info.RespReg.IdeOC == 9 {
+		tamanho("descrição do órgão de classe do responsável", info.RespReg.DscOC, 1, 20)
+	}
+	if len(info.RespReg.UfOC) != 2 {
+		problemas = append(problemas, "UF do órgão de classe do responsável inválida")
+	}
+	if len(ev.Id) != 36 {
+		problemas = append(problemas, "identificador do evento com tamanho diferente de 36")
+	}
+
+	return problemas
+}
+
+// validarXsd confere o documento contra o xsd oficial com o xmllint (libxml2). Os erros de schema voltam
+// como problemas do evento; sem o xsd ou sem o xmllint a exportação é recusada, nunca gerada sem validar
+func validarXsd(ctx context.Context, xsd string, documento []byte) ([]string, error) {
+
+	if _, err := os.Stat(xsd); err != nil {
+		return nil, fmt.Errorf("%w: %s não encontrado", helper.ErrXsdEsocial, xsd)
+	}
+
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		return nil, fmt.Errorf("%w: xmllint não instalado", helper.ErrXsdEsocial)
+	}
+
+	var saida bytes.Buffer
+	cmd := exec.CommandContext(ctx, xmllint, "--noout", "--nonet", "--schema", xsd, "-")
+	cmd.Stdin = bytes.NewReader(documento)
+	cmd.Stderr = &saida
+
+	err = cmd.Run()
+	if err == nil {
+		return nil, nil
+	}
+
+	// 3 = documento não passou no schema; qualquer outro código é problema no próprio xsd ou no xmllint
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		return nil, fmt.Errorf("%w: %s", helper.ErrXsdEsocial, strings.TrimSpace(saida.String()))
+	}
+
+	problemas := []string{}
+	for _, linha := range strings.Split(saida.String(), "\n") {
+
+		_, mensagem, ok := strings.Cut(linha, "Schemas validity error : ")
+		if ok {
+			problemas = append(problemas, "xsd: "+strings.TrimSpace(mensagem))
+		}
+	}
+	if len(problemas) == 0 {
+		problemas = append(problemas, "xsd: "+strings.TrimSpace(saida.String()))
+	}
+
+	return problemas, nil
+}
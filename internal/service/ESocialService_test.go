@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportacaoTeste() model.ExportacaoS2240 {
+
+	return model.ExportacaoS2240{
+		DataInicio: configs.DataBr(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+		DataFim:    configs.DataBr(time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)),
+		Ambiente:   2,
+		Responsavel: model.ResponsavelRegistroAmbiental{
+			Cpf:   "529.982.247-25",
+			IdeOC: 4,
+			NrOC:  "123456",
+			UfOC:  "sp",
+		},
+	}
+}
+
+func TestMontarS2240(t *testing.T) {
+
+	exp := repository.ListarExposicoesS2240Row{
+		FuncionarioID:      1,
+		Matricula:          "1234567",
+		Cpf:                pgtype.Text{String: "12345678909", Valid: true},
+		DepartamentoNome:   "Caldeiraria",
+		CodAgenteNocivo:    pgtype.Text{String: "02.01.001", Valid: true},
+		DescricaoAtividade: pgtype.Text{String: "Soldagem de estruturas metálicas", Valid: true},
+		Ca:                 "12345",
+		InicioCondicao:     pgtype.Date{Time: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), Valid: true},
+	}
+
+	evento := montarS2240(exp, "12345678000195", exportacaoTeste(), time.Date(2026, 2, 1, 8, 30, 0, 0, time.UTC), 7)
+
+	assert.Empty(t, validarS2240(evento))
+	assert.Equal(t, "ID1123456780000002026020108300000007", evento.Evento.Id)
+	assert.Equal(t, "12345678", evento.Evento.IdeEmpregador.NrInsc)
+
+	conteudo, err := xml.Marshal(evento)
+	require.NoError(t, err)
+
+	texto := string(conteudo)
+	assert.True(t, strings.HasPrefix(texto, `<eSocial xmlns="`+namespaceS2240+`">`))
+	assert.Contains(t, texto, "<dtIniCondicao>2025-03-10</dtIniCondicao>")
+	assert.Contains(t, texto, "<epi><docAval>12345</docAval></epi>")
+	assert.Contains(t, texto, "<ufOC>SP</ufOC>")
+}
+
+func TestValidarS2240(t *testing.T) {
+
+	exp := repository.ListarExposicoesS2240Row{
+		Matricula:        "1234567",
+		DepartamentoNome: "Almoxarifado",
+		Ca:               "",
+	}
+
+	evento := montarS2240(exp, "12345678000195", exportacaoTeste(), time.Now(), 1)
+
+	problemas := validarS2240(evento)
+	assert.Contains(t, problemas, "funcionario sem CPF cadastrado")
+	assert.Contains(t, problemas, "função sem dados do eSocial (agente nocivo e descrição da atividade)")
+	assert.Contains(t, problemas, "epi entregue sem CA cadastrado")
+
+	exp.Cpf = pgtype.Text{String: "12345678909", Valid: true}
+	exp.CodAgenteNocivo = pgtype.Text{String: agenteAusente, Valid: true}
+	exp.DescricaoAtividade = pgtype.Text{String: "Administrativo", Valid: true}
+	exp.Ca = "999"
+
+	evento = montarS2240(exp, "12345678000195", exportacaoTeste(), time.Now(), 1)
+	assert.Equal(t, []string{"com o agente 09.01.001 (ausência de agente nocivo) o S-2240 não aceita epi"}, validarS2240(evento))
+}
+
+func TestValidarXsd(t *testing.T) {
+
+	if _, err := exec.LookPath("xmllint"); err != nil {
+		t.Skip("xmllint não instalado")
+	}
+
+	// schema mínimo no lugar do pacote oficial, só para conferir como os erros do xmllint voltam
+	xsd := filepath.Join(t.TempDir(), "evtExpRisco.xsd")
+	require.NoError(t, os.WriteFile(xsd, []byte(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:teste" elementFormDefault="qualified">
+	<xs:element name="ideVinculo">
+		<xs:complexType>
+			<xs:sequence>
+				<xs:element name="cpfTrab">
+					<xs:simpleType>
+						<xs:restriction base="xs:string"><xs:pattern value="\d{11}"/></xs:restriction>
+					</xs:simpleType>
+				</xs:element>
+			</xs:sequence>
+		</xs:complexType>
+	</xs:element>
+</xs:schema>`), 0o600))
+
+	ctx := context.Background()
+
+	problemas, err := validarXsd(ctx, xsd, []byte(`<ideVinculo xmlns="urn:teste"><cpfTrab>12345678909</cpfTrab></ideVinculo>`))
+	require.NoError(t, err)
+	assert.Empty(t, problemas)
+
+	problemas, err = validarXsd(ctx, xsd, []byte(`<ideVinculo xmlns="urn:teste"><cpfTrab>123</cpfTrab></ideVinculo>`))
+	require.NoError(t, err)
+	require.Len(t, problemas, 1)
+	assert.True(t, strings.HasPrefix(problemas[0], "xsd: "))
+	assert.Contains(t, problemas[0], "cpfTrab")
+
+	_, err = validarXsd(ctx, filepath.Join(t.TempDir(), "evtExpRisco.xsd"), []byte(`<ideVinculo/>`))
+	assert.ErrorIs(t, err, helper.ErrXsdEsocial)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"slices"
+	"time"
 
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
@@ -41,24 +43,240 @@ func NewEntregaService(r EntregaRepository, pool *pgxpool.Pool) *EntregaService
 	}
 }
 
-func (e *EntregaService) Salvar(ctx context.Context, model model.EntregaParaInserir, tenantid int32) error {
+// Salvar devolve true quando a entrega foi feita sem treinamento válido e a regra da empresa está em "avisar"
+func (e *EntregaService) Salvar(ctx context.Context, model model.EntregaParaInserir, tenantid int32) (bool, error) {
 
 	tx, err := e.db.Begin(ctx)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer tx.Rollback(ctx)
 
 	qtx := e.queries.WithTx(tx)
-	err = e.RegistrarEntrega(ctx, qtx, model, tenantid)
+	_, pendenciaTreinamento, err := e.registrarEntrega(ctx, qtx, model, tenantid)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return tx.Commit(ctx)
+	return pendenciaTreinamento, tx.Commit(ctx)
 }
 
-func (e *EntregaService) RegistrarEntrega(ctx context.Context, qtx *repository.Queries, model model.EntregaParaInserir, tenantId int32) error {
+// SalvarLote registra uma entrega por funcionario usando os mesmos itens.
+// Sem TudoOuNada cada funcionario tem a sua propria transação e uma falha não atrapalha os outros;
+// com TudoOuNada tudo roda numa transação só e a primeira falha desfaz o lote inteiro
+func (e *EntregaService) SalvarLote(ctx context.Context, lote model.EntregaLoteInserir, tenantId int32) (model.ResultadoEntregaLote, error) {
+
+	ids, err := e.destinatariosLote(ctx, lote, tenantId)
+	if err != nil {
+		return model.ResultadoEntregaLote{}, err
+	}
+
+	resultado := model.ResultadoEntregaLote{
+		Total:      len(ids),
+		Resultados: make([]model.ResultadoEntregaFuncionario, 0, len(ids)),
+	}
+
+	if lote.TudoOuNada {
+		return e.salvarLoteTudoOuNada(ctx, lote, ids, resultado, tenantId)
+	}
+
+	for _, id := range ids {
+
+		var pendenciaTreinamento bool
+		entrega, err := e.entregaDoLote(ctx, e.queries, lote, id, tenantId)
+		if err == nil {
+			pendenciaTreinamento, err = e.Salvar(ctx, entrega, tenantId)
+		}
+		if err != nil {
+
+			resultado.Falhas++
+			resultado.Resultados = append(resultado.Resultados, model.ResultadoEntregaFuncionario{
+				IdFuncionario: id,
+				Erro:          err.Error(),
+			})
+			continue
+		}
+
+		resultado.Sucessos++
+		resultado.Resultados = append(resultado.Resultados, model.ResultadoEntregaFuncionario{
+			IdFuncionario:        id,
+			Sucesso:              true,
+			PendenciaTreinamento: pendenciaTreinamento,
+		})
+	}
+
+	resultado.Efetivado = resultado.Sucessos > 0
+
+	return resultado, nil
+}
+
+func (e *EntregaService) salvarLoteTudoOuNada(ctx context.Context, lote model.EntregaLoteInserir, ids []int64, resultado model.ResultadoEntregaLote, tenantId int32) (model.ResultadoEntregaLote, error) {
+
+	tx, err := e.db.Begin(ctx)
+	if err != nil {
+		return model.ResultadoEntregaLote{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := e.queries.WithTx(tx)
+	pendencias := make([]bool, len(ids))
+
+	for i, id := range ids {
+
+		entrega, err := e.entregaDoLote(ctx, qtx, lote, id, tenantId)
+		if err == nil {
+			_, pendencias[i], err = e.registrarEntrega(ctx, qtx, entrega, tenantId)
+		}
+		if err == nil {
+			continue
+		}
+
+		// falhou um, o lote inteiro volta (rollback pelo defer)
+		for j, outro := range ids {
+
+			r := model.ResultadoEntregaFuncionario{IdFuncionario: outro}
+			switch {
+			case j < i:
+				r.Erro = "entrega desfeita, outro funcionario do lote falhou"
+			case j == i:
+				r.Erro = err.Error()
+			default:
+				r.Erro = "entrega não processada, outro funcionario do lote falhou"
+			}
+			resultado.Resultados = append(resultado.Resultados, r)
+		}
+		resultado.Falhas = len(ids)
+
+		return resultado, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.ResultadoEntregaLote{}, err
+	}
+
+	for i, id := range ids {
+		resultado.Resultados = append(resultado.Resultados, model.ResultadoEntregaFuncionario{
+			IdFuncionario:        id,
+			Sucesso:              true,
+			PendenciaTreinamento: pendencias[i],
+		})
+	}
+	resultado.Sucessos = len(ids)
+	resultado.Efetivado = true
+
+	return resultado, nil
+}
+
+// destinatariosLote resolve a lista de funcionarios do lote, sem repetir ninguem
+func (e *EntregaService) destinatariosLote(ctx context.Context, lote model.EntregaLoteInserir, tenantId int32) ([]int64, error) {
+
+	temGrupo := lote.IdDepartamento != nil || lote.IdFuncao != nil
+	if temGrupo == (len(lote.IdsFuncionarios) > 0) {
+		return nil, helper.ErrLoteSemDestino
+	}
+
+	var ids []int64
+	if temGrupo {
+
+		encontrados, err := e.queries.ListarIdsFuncionariosPorGrupo(ctx, repository.ListarIdsFuncionariosPorGrupoParams{
+			TenantID:       tenantId,
+			IDDepartamento: intPtrParaInt4(lote.IdDepartamento),
+			IDFuncao:       intPtrParaInt4(lote.IdFuncao),
+		})
+		if err != nil {
+			return nil, helper.TraduzErroPostgres(err)
+		}
+
+		for _, id := range encontrados {
+			ids = append(ids, int64(id))
+		}
+	} else {
+		ids = lote.IdsFuncionarios
+	}
+
+	vistos := make(map[int64]bool, len(ids))
+	unicos := make([]int64, 0, len(ids))
+	for _, id := range ids {
+
+		if vistos[id] {
+			continue
+		}
+		vistos[id] = true
+		unicos = append(unicos, id)
+	}
+
+	if len(unicos) == 0 {
+		return nil, helper.ErrNaoEncontrado
+	}
+
+	return unicos, nil
+}
+
+// entregaDoLote monta a entrega de um funcionario do lote; item sem tamanho pega o do perfil dele
+func (e *EntregaService) entregaDoLote(ctx context.Context, qtx *repository.Queries, lote model.EntregaLoteInserir, idFuncionario int64, tenantId int32) (model.EntregaParaInserir, error) {
+
+	var semTamanho []int32
+	for _, item := range lote.Itens {
+		if item.ID_tamanho == 0 && !slices.Contains(semTamanho, int32(item.ID_epi)) {
+			semTamanho = append(semTamanho, int32(item.ID_epi))
+		}
+	}
+
+	sugeridos := make(map[int64]int64, len(semTamanho))
+	if len(semTamanho) > 0 {
+
+		tamanhos, err := qtx.TamanhosSugeridos(ctx, repository.TamanhosSugeridosParams{
+			IDFuncionario: int32(idFuncionario),
+			IdsEpis:       semTamanho,
+			TenantID:      tenantId,
+		})
+		if err != nil {
+			return model.EntregaParaInserir{}, err
+		}
+
+		for _, t := range tamanhos {
+			sugeridos[int64(t.IDEpi)] = int64(t.Idtamanho)
+		}
+	}
+
+	itens := make([]model.ItemParaInserir, 0, len(lote.Itens))
+	for _, item := range lote.Itens {
+
+		idTamanho := item.ID_tamanho
+		if idTamanho == 0 {
+
+			sugerido, ok := sugeridos[item.ID_epi]
+			if !ok {
+				return model.EntregaParaInserir{}, fmt.Errorf("%w: EPI ID %d", helper.ErrTamanhoSemPerfil, item.ID_epi)
+			}
+			idTamanho = sugerido
+		}
+
+		itens = append(itens, model.ItemParaInserir{
+			ID_epi:     item.ID_epi,
+			ID_tamanho: idTamanho,
+			Quantidade: item.Quantidade,
+			IdReserva:  item.IdReserva,
+		})
+	}
+
+	return model.EntregaParaInserir{
+		ID_funcionario:     idFuncionario,
+		Id_user:            lote.Id_user,
+		Data_entrega:       lote.Data_entrega,
+		Assinatura_Digital: lote.Assinatura_Digital,
+		Itens:              itens,
+	}, nil
+}
+
+func (e *EntregaService) RegistrarEntrega(ctx context.Context, qtx *repository.Queries, model model.EntregaParaInserir, tenantId int32) (int32, error) {
+
+	id, _, err := e.registrarEntrega(ctx, qtx, model, tenantId)
+	return id, err
+}
+
+// registrarEntrega devolve também se a entrega ficou com pendência de treinamento (regra da empresa em "avisar")
+func (e *EntregaService) registrarEntrega(ctx context.Context, qtx *repository.Queries, model model.EntregaParaInserir, tenantId int32) (int32, bool, error) {
 
 	funcionario, err := qtx.BuscaFuncionarioPorId(ctx, repository.BuscaFuncionarioPorIdParams{
 		ID:       int32(model.ID_funcionario),
@@ -68,9 +286,9 @@ func (e *EntregaService) RegistrarEntrega(ctx context.Context, qtx *repository.Q
 
 		if err == pgx.ErrNoRows {
 
-			return helper.ErrNaoEncontrado
+			return 0, false, helper.ErrNaoEncontrado
 		}
-		return  err
+		return 0, false, err
 	}
 	token := helper.GerarTokenAuditoria(funcionario.Nome, funcionario.FuncaoNome, funcionario.DepartamentoNome, model.Data_entrega.Time())
 	// 1. Cria a variável vazia (Valid: false por padrão)
@@ -83,15 +301,22 @@ func (e *EntregaService) RegistrarEntrega(ctx context.Context, qtx *repository.Q
 			Valid: true,
 		}
 	}
+
+	pendenciaTreinamento, err := e.verificarTreinamento(ctx, qtx, funcionario.ID, model, tenantId)
+	if err != nil {
+		return 0, false, err
+	}
+
 	args := repository.AddEntregaEpiParams{
 
-		Idfuncionario:    int32(model.ID_funcionario),
-		DataEntrega:      pgtype.Date{Time: model.Data_entrega.Time(), Valid: !model.Data_entrega.IsZero()},
-		Assinatura:       model.Assinatura_Digital,
-		TokenValidacao:   pgtype.Text{String: token, Valid: token != ""},
-		IDUsuarioEntrega: pgtype.Int4{Int32: int32(model.Id_user), Valid: int32(model.Id_user) > 0},
-		Idtroca:          idTrocaParaBanco,
-		TenantID:         tenantId,
+		Idfuncionario:        int32(model.ID_funcionario),
+		DataEntrega:          pgtype.Date{Time: model.Data_entrega.Time(), Valid: !model.Data_entrega.IsZero()},
+		Assinatura:           model.Assinatura_Digital,
+		TokenValidacao:       pgtype.Text{String: token, Valid: token != ""},
+		IDUsuarioEntrega:     pgtype.Int4{Int32: int32(model.Id_user), Valid: int32(model.Id_user) > 0},
+		Idtroca:              idTrocaParaBanco,
+		TenantID:             tenantId,
+		PendenciaTreinamento: pendenciaTreinamento,
 	}
 
 	identrega, err := e.repo.AdicionarEntrega(ctx, qtx, args) //salva o "cabeçalho"
@@ -99,9 +324,9 @@ func (e *EntregaService) RegistrarEntrega(ctx context.Context, qtx *repository.Q
 
 		if err == pgx.ErrNoRows {
 
-			return helper.ErrNaoEncontrado
+			return 0, false, helper.ErrNaoEncontrado
 		}
-		return err
+		return 0, false, err
 	}
 
 	//percorre todos os item da lista de itens
@@ -109,7 +334,40 @@ func (e *EntregaService) RegistrarEntrega(ctx context.Context, qtx *repository.Q
 
 		quantidadeNescessaria := item.Quantidade
 
+		// quando a entrega atende uma reserva, o saldo dela não pode ser descontado dela mesma
+		var idReserva pgtype.Int4
+		var loteReservado, quantidadeReservada int32
+		if item.IdReserva != nil {
+
+			reserva, err := qtx.BuscarReservaParaConsumo(ctx, repository.BuscarReservaParaConsumoParams{
+				ID:       int32(*item.IdReserva),
+				TenantID: tenantId,
+			})
+			if err != nil {
+
+				if err == pgx.ErrNoRows {
+
+					return 0, false, helper.ErrReservaInvalida
+				}
+				return 0, false, err
+			}
+
+			if reserva.Idepi != int32(item.ID_epi) || reserva.Idtamanho != int32(item.ID_tamanho) {
+				return 0, false, helper.ErrReservaInvalida
+			}
+
+			// a reserva de uma solicitação só serve para o funcionario que pediu
+			if reserva.IDFuncionario.Valid && reserva.IDFuncionario.Int32 != funcionario.ID {
+				return 0, false, helper.ErrReservaInvalida
+			}
+
+			idReserva = pgtype.Int4{Int32: reserva.ID, Valid: true}
+			loteReservado = reserva.Identrada.Int32
+			quantidadeReservada = reserva.Quantidade
+		}
+
 		lotes := repository.ListarLotesParaConsumoParams{
+			IDReserva: idReserva,
 			Idepi:     int32(item.ID_epi),
 			Idtamanho: int32(item.ID_tamanho),
 			TenantID:  tenantId,
@@ -120,13 +378,50 @@ func (e *EntregaService) RegistrarEntrega(ctx context.Context, qtx *repository.Q
 
 			if err == pgx.ErrNoRows {
 
-				return helper.ErrNaoEncontrado
+				return 0, false, helper.ErrNaoEncontrado
 			}
-			return err
+			return 0, false, err
 		}
 
 		if len(entradaLotes) == 0 {
-			return fmt.Errorf("estoque insuficiente para o EPI ID %d", item.ID_epi)
+			return 0, false, fmt.Errorf("%w: EPI ID %d sem lote disponivel", helper.ErrEstoqueInsuficiente, item.ID_epi)
+		}
+
+		// reservas sem lote seguram saldo de qualquer lote, então saem do total
+		reservadoSemLote, err := qtx.QuantidadeReservadaSemLote(ctx, repository.QuantidadeReservadaSemLoteParams{
+			TenantID:  tenantId,
+			Idepi:     int32(item.ID_epi),
+			Idtamanho: int32(item.ID_tamanho),
+			IDReserva: idReserva,
+		})
+		if err != nil {
+			return 0, false, err
+		}
+
+		disponivel := -reservadoSemLote
+		for _, entradaLote := range entradaLotes {
+			disponivel += max(entradaLote.QuantidadeLivre, 0)
+		}
+
+		// o lote da reserva vai na frente, o resto segue por validade
+		if loteReservado > 0 {
+			ordenados := make([]repository.ListarLotesParaConsumoRow, 0, len(entradaLotes))
+			for _, l := range entradaLotes {
+				if l.ID == loteReservado {
+					ordenados = append(ordenados, l)
+				}
+			}
+			for _, l := range entradaLotes {
+				if l.ID != loteReservado {
+					ordenados = append(ordenados, l)
+				}
+			}
+			entradaLotes = ordenados
+		}
+
+		if disponivel < int32(quantidadeNescessaria) {
+			return 0, false, fmt.Errorf("%w: EPI ID %d (faltam %d unidades)",
+				helper.ErrEstoqueInsuficiente, item.ID_epi, int32(quantidadeNescessaria)-max(disponivel, 0))
 		}
 
 		/*percorre todas as entradas achadas*/
@@ -136,8 +431,12 @@ func (e *EntregaService) RegistrarEntrega(ctx context.Context, qtx *repository.Q
 				break
 			}
 
+			if entradaLote.QuantidadeLivre <= 0 {
+				continue
+			}
+
 			//escolhe o menor valor entre esses parametros
-			quantidadeAbater := min(entradaLote.Quantidadeatual, int32(quantidadeNescessaria))
+			quantidadeAbater := min(entradaLote.QuantidadeLivre, int32(quantidadeNescessaria))
 
 			itemAdd := repository.AddItemEntregueParams{
 				Identrega:  identrega,
@@ -150,7 +449,7 @@ func (e *EntregaService) RegistrarEntrega(ctx context.Context, qtx *repository.Q
 
 			_, err := e.repo.AdicionarEntregaItem(ctx, qtx, itemAdd)
 			if err != nil {
-				return err
+				return 0, false, err
 			}
 
 			_, err = e.repo.AbaterEstoqueEntrada(ctx, qtx, repository.AbaterEstoqueLoteParams{
@@ -159,7 +458,7 @@ func (e *EntregaService) RegistrarEntrega(ctx context.Context, qtx *repository.Q
 				TenantID:        tenantId,
 			})
 			if err != nil {
-				return err
+				return 0, false, err
 			}
 
 			quantidadeNescessaria -= int(quantidadeAbater)
@@ -169,12 +468,93 @@ func (e *EntregaService) RegistrarEntrega(ctx context.Context, qtx *repository.Q
 			// Se sobrou quantidade, significa que percorremos todos os lotes
 			// e ainda não deu o total. Rollback automático pelo defer!
 			
-			return fmt.Errorf("estoque insuficiente para o EPI ID %d (faltam %d unidades)",
-				item.ID_epi, quantidadeNescessaria)
+			return 0, false, fmt.Errorf("%w: EPI ID %d (faltam %d unidades)",
+				helper.ErrEstoqueInsuficiente, item.ID_epi, quantidadeNescessaria)
+		}
+
+		// entregou menos que o reservado: só abate, o resto continua segurado
+		if idReserva.Valid && int32(item.Quantidade) < quantidadeReservada {
+
+			_, err := qtx.AbaterReserva(ctx, repository.AbaterReservaParams{
+				ID:         idReserva.Int32,
+				Quantidade: int32(item.Quantidade),
+				TenantID:   tenantId,
+			})
+			if err != nil {
+				return 0, false, err
+			}
+		} else if idReserva.Valid {
+
+			_, err := qtx.ConsumirReserva(ctx, repository.ConsumirReservaParams{
+				ID:        idReserva.Int32,
+				Identrega: pgtype.Int4{Int32: identrega, Valid: true},
+				TenantID:  tenantId,
+			})
+			if err != nil {
+				return 0, false, err
+			}
+		}
+
+		// o tamanho entregue vira o padrão das próximas entregas (a não ser que tenha sido editado à mão)
+		err = qtx.AprenderPerfilTamanho(ctx, repository.AprenderPerfilTamanhoParams{
+			IDFuncionario: funcionario.ID,
+			IDTamanho:     int32(item.ID_tamanho),
+			IDEpi:         int32(item.ID_epi),
+			TenantID:      tenantId,
+		})
+		if err != nil {
+			return 0, false, err
 		}
 	}
 
-	return nil
+	return identrega, pendenciaTreinamento, nil
+}
+
+// verificarTreinamento aplica a regra da empresa para epi entregue sem treinamento válido (NR-6).
+// Na regra "avisar" a entrega segue, mas sai marcada com pendência; em "bloquear" ela não é feita
+func (e *EntregaService) verificarTreinamento(ctx context.Context, qtx *repository.Queries, idFuncionario int32, model model.EntregaParaInserir, tenantId int32) (bool, error) {
+
+	regra, err := qtx.BuscarRegraTreinamento(ctx, tenantId)
+	if err != nil {
+		return false, err
+	}
+
+	if regra == regraTreinamentoDesligada {
+		return false, nil
+	}
+
+	var idsEpis []int32
+	for _, item := range model.Itens {
+		if !slices.Contains(idsEpis, int32(item.ID_epi)) {
+			idsEpis = append(idsEpis, int32(item.ID_epi))
+		}
+	}
+
+	hoje := time.Now()
+	data := time.Date(hoje.Year(), hoje.Month(), hoje.Day(), 0, 0, 0, 0, time.UTC)
+	if !model.Data_entrega.IsZero() {
+		data = model.Data_entrega.Time()
+	}
+
+	semTreinamento, err := qtx.EpisSemTreinamentoValido(ctx, repository.EpisSemTreinamentoValidoParams{
+		IdsEpis:       idsEpis,
+		TenantID:      tenantId,
+		IDFuncionario: idFuncionario,
+		Data:          pgtype.Date{Time: data, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if len(semTreinamento) == 0 {
+		return false, nil
+	}
+
+	if regra == regraTreinamentoBloquear {
+		return false, fmt.Errorf("%w: EPI ID %v", helper.ErrSemTreinamento, semTreinamento)
+	}
+
+	return true, nil
 }
 
 type FiltroEntregas struct {
@@ -287,10 +667,11 @@ func (e *EntregaService) ListaEntregas(ctx context.Context, f FiltroEntregas, te
 					},
 				},
 			},
-			Data_entrega:       configs.DataBr(entrega.DataEntrega.Time),
-			Assinatura_Digital: entrega.Assinatura,
-			Itens:              itensMap[entrega.EntregaID],
-			Id_user:            int(entrega.IDUsuarioEntrega.Int32),
+			Data_entrega:         configs.DataBr(entrega.DataEntrega.Time),
+			Assinatura_Digital:   entrega.Assinatura,
+			Itens:                itensMap[entrega.EntregaID],
+			Id_user:              int(entrega.IDUsuarioEntrega.Int32),
+			PendenciaTreinamento: entrega.PendenciaTreinamento,
 		}
 
 		dto = append(dto, e)
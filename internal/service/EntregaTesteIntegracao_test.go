@@ -9,6 +9,7 @@ import (
 
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
@@ -163,7 +164,7 @@ func TestEntrega(t *testing.T) {
 		}
 
 		// Passando TenantID (int32)
-		err := serv.Salvar(context.Background(), entregaErro, int32(idEmpresa))
+		_, err := serv.Salvar(context.Background(), entregaErro, int32(idEmpresa))
 		require.Error(t, err)
 		fmt.Println("Erro esperado recebido:", err)
 
@@ -215,7 +216,7 @@ func TestEntrega(t *testing.T) {
 			go func() {
 				defer wg.Done()
 				// Passando TenantID
-				err := serv2.Salvar(ctx, entrega, int32(idEmpresa2))
+				_, err := serv2.Salvar(ctx, entrega, int32(idEmpresa2))
 				if err != nil {
 					fmt.Printf("Falha na goroutine: %v\n", err)
 				} else {
@@ -271,7 +272,7 @@ func TestEntrega(t *testing.T) {
 
 		for i := range 4 {
 
-			err := serv.Salvar(ctx, entregas[0], int32(empresa))
+			_, err := serv.Salvar(ctx, entregas[0], int32(empresa))
 			require.NoError(t, err, "A entrega %d deveria ter funcionado", i+1)
 		}
 
@@ -302,3 +303,172 @@ func TestEntrega(t *testing.T) {
 
 	})
 }
+
+func TestEntregaComReserva(t *testing.T) {
+
+	db := SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	servEntrega := NewEntregaService(repository.NewEntregaRepository(db), db)
+	servReserva := NewReservaService(repository.NewReservaRepository(db), db)
+
+	idEmpresa := CreateEmpresa(t, db)
+	iduser := CreateUser(t, db, idEmpresa)
+	iddep := CreateDepartamento(t, db, idEmpresa)
+	IdFuncao := CreateFuncao(t, db, iddep, idEmpresa)
+	idtam := CreateTamanho(t, db, idEmpresa)
+	idprotec := CreateProtecao(t, db, idEmpresa)
+	idepi := CreateEpi(t, db, idprotec, idEmpresa)
+	idfuncionario := CreateFuncionario(t, db, iddep, IdFuncao, idEmpresa)
+	idfuncionario2 := CreateFuncionario(t, db, iddep, IdFuncao, idEmpresa)
+
+	Idfornecedor := CreateFornecedor(t, db, idEmpresa)
+	idEntrada := CreateEntradaEpi(t, db, idfuncionario, idepi, idprotec, idtam, Idfornecedor, iduser, idEmpresa)
+
+	// o atendimento da solicitação chama RegistrarEntrega dentro da transação dele
+	entregarComReserva := func(idFuncionario int64, quantidade, idReserva int) (int32, error) {
+
+		tx, err := db.Begin(ctx)
+		require.NoError(t, err)
+		defer tx.Rollback(ctx)
+
+		idEntrega, err := servEntrega.RegistrarEntrega(ctx, repository.New(db).WithTx(tx), model.EntregaParaInserir{
+			ID_funcionario:     idFuncionario,
+			Id_user:            int(iduser),
+			Data_entrega:       *configs.NewDataBrPtr(time.Now()),
+			Assinatura_Digital: "assinatura_base64_teste",
+			Itens: []model.ItemParaInserir{
+				{ID_epi: idepi, ID_tamanho: idtam, Quantidade: quantidade, IdReserva: &idReserva},
+			},
+		}, int32(idEmpresa))
+		if err != nil {
+			return 0, err
+		}
+
+		return idEntrega, tx.Commit(ctx)
+	}
+
+	idReserva, err := servReserva.Salvar(ctx, model.ReservaInserir{
+		IdEpi:      int(idepi),
+		IdTamanho:  int(idtam),
+		Quantidade: 10,
+		ExpiraEm:   configs.DataBr(time.Now().AddDate(0, 0, 1)),
+	}, int(iduser), int32(idEmpresa))
+	require.NoError(t, err)
+
+	t.Run("entrega menor que a reserva abate a reserva e deixa o restante preso", func(t *testing.T) {
+
+		_, err := entregarComReserva(idfuncionario, 4, idReserva)
+		require.NoError(t, err)
+
+		var quantidade int32
+		var consumida bool
+		err = db.QueryRow(ctx, "SELECT quantidade, consumida_em IS NOT NULL FROM reserva_estoque WHERE id = $1 AND tenant_id = $2", idReserva, idEmpresa).Scan(&quantidade, &consumida)
+		require.NoError(t, err)
+		require.Equal(t, int32(6), quantidade)
+		require.False(t, consumida)
+
+		var estoque int32
+		err = db.QueryRow(ctx, "SELECT quantidadeAtual FROM entrada_epi WHERE id = $1 AND tenant_id = $2", idEntrada, idEmpresa).Scan(&estoque)
+		require.NoError(t, err)
+		require.Equal(t, int32(96), estoque)
+	})
+
+	t.Run("entrega do restante consome a reserva e liga a entrega a ela", func(t *testing.T) {
+
+		idEntrega, err := entregarComReserva(idfuncionario, 6, idReserva)
+		require.NoError(t, err)
+
+		var consumida bool
+		var idEntregaReserva pgtype.Int4
+		err = db.QueryRow(ctx, "SELECT consumida_em IS NOT NULL, IdEntrega FROM reserva_estoque WHERE id = $1 AND tenant_id = $2", idReserva, idEmpresa).Scan(&consumida, &idEntregaReserva)
+		require.NoError(t, err)
+		require.True(t, consumida)
+		require.Equal(t, idEntrega, idEntregaReserva.Int32)
+
+		var estoque int32
+		err = db.QueryRow(ctx, "SELECT quantidadeAtual FROM entrada_epi WHERE id = $1 AND tenant_id = $2", idEntrada, idEmpresa).Scan(&estoque)
+		require.NoError(t, err)
+		require.Equal(t, int32(90), estoque)
+	})
+
+	t.Run("ERRO - reserva já consumida não é usada de novo", func(t *testing.T) {
+
+		_, err := entregarComReserva(idfuncionario, 1, idReserva)
+		require.ErrorIs(t, err, helper.ErrReservaInvalida)
+	})
+
+	t.Run("ERRO - reserva de uma solicitação não serve para outro funcionario", func(t *testing.T) {
+
+		var idSolicitacao int64
+		err := db.QueryRow(ctx, `
+			INSERT INTO solicitacao_epi (tenant_id, IdFuncionario, IdUsuarioSolicitante, justificativa, status)
+			VALUES ($1, $2, $3, $4, 'aprovada')
+			RETURNING id`, idEmpresa, idfuncionario, iduser, "troca do epi danificado").Scan(&idSolicitacao)
+		require.NoError(t, err)
+
+		idReservaSolicitacao, err := servReserva.Salvar(ctx, model.ReservaInserir{
+			IdEpi:      int(idepi),
+			IdTamanho:  int(idtam),
+			Quantidade: 2,
+			ExpiraEm:   configs.DataBr(time.Now().AddDate(0, 0, 1)),
+		}, int(iduser), int32(idEmpresa))
+		require.NoError(t, err)
+
+		_, err = db.Exec(ctx, "UPDATE reserva_estoque SET IdSolicitacao = $1 WHERE id = $2 AND tenant_id = $3", idSolicitacao, idReservaSolicitacao, idEmpresa)
+		require.NoError(t, err)
+
+		_, err = entregarComReserva(idfuncionario2, 2, idReservaSolicitacao)
+		require.ErrorIs(t, err, helper.ErrReservaInvalida)
+
+		var count int
+		err = db.QueryRow(ctx, "SELECT count(*) FROM entrega_epi WHERE IdFuncionario = $1 AND tenant_id = $2", idfuncionario2, idEmpresa).Scan(&count)
+		require.NoError(t, err)
+		require.Equal(t, 0, count, "a entrega recusada não deveria ter sido salva")
+	})
+
+	t.Run("reserva avulsa é atendida pelo cadastro de entregas", func(t *testing.T) {
+
+		idReservaAvulsa, err := servReserva.Salvar(ctx, model.ReservaInserir{
+			IdEpi:      int(idepi),
+			IdTamanho:  int(idtam),
+			Quantidade: 3,
+			ExpiraEm:   configs.DataBr(time.Now().AddDate(0, 0, 1)),
+		}, int(iduser), int32(idEmpresa))
+		require.NoError(t, err)
+
+		_, err = servEntrega.Salvar(ctx, model.EntregaParaInserir{
+			ID_funcionario:     idfuncionario2,
+			Id_user:            int(iduser),
+			Data_entrega:       *configs.NewDataBrPtr(time.Now()),
+			Assinatura_Digital: "assinatura_base64_teste",
+			Itens: []model.ItemParaInserir{
+				{ID_epi: idepi, ID_tamanho: idtam, Quantidade: 3, IdReserva: &idReservaAvulsa},
+			},
+		}, int32(idEmpresa))
+		require.NoError(t, err)
+
+		var consumida bool
+		err = db.QueryRow(ctx, "SELECT consumida_em IS NOT NULL FROM reserva_estoque WHERE id = $1 AND tenant_id = $2", idReservaAvulsa, idEmpresa).Scan(&consumida)
+		require.NoError(t, err)
+		require.True(t, consumida)
+	})
+
+	t.Run("ERRO - reserva vencida não é atendida", func(t *testing.T) {
+
+		idReservaVencida, err := servReserva.Salvar(ctx, model.ReservaInserir{
+			IdEpi:      int(idepi),
+			IdTamanho:  int(idtam),
+			Quantidade: 1,
+			ExpiraEm:   configs.DataBr(time.Now().AddDate(0, 0, 1)),
+		}, int(iduser), int32(idEmpresa))
+		require.NoError(t, err)
+
+		_, err = db.Exec(ctx, "UPDATE reserva_estoque SET expira_em = NOW() - INTERVAL '1 day' WHERE id = $1 AND tenant_id = $2", idReservaVencida, idEmpresa)
+		require.NoError(t, err)
+
+		_, err = entregarComReserva(idfuncionario, 1, idReservaVencida)
+		require.ErrorIs(t, err, helper.ErrReservaInvalida)
+	})
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"time"
 
@@ -55,6 +56,31 @@ func (e *EpiService) Salvar(ctx context.Context, model model.EpiInserir, tenantI
 	model.CA = strings.TrimSpace(model.CA)
 	qtx := e.queries.WithTx(tx)
 
+	catalogo, achou, err := e.buscarCatalogoCa(ctx, model.CA)
+	if err != nil {
+		return err
+	}
+
+	if achou {
+		if model.Fabricante == "" {
+			model.Fabricante = limitarTexto(catalogo.Fabricante, 100)
+		}
+
+		if model.Descricao == "" {
+			model.Descricao = limitarTexto(descricaoCatalogo(catalogo), 250)
+		}
+
+		if model.DataValidadeCa.Time().IsZero() && catalogo.Validade.Valid {
+			model.DataValidadeCa = configs.DataBr(catalogo.Validade.Time)
+		}
+	}
+
+	// sem os dois o CA precisa estar no catálogo do CAEPI para completar
+	if model.Fabricante == "" || model.DataValidadeCa.Time().IsZero() {
+
+		return fmt.Errorf("%w: fabricante e data_validade_ca (CA %s não encontrado no catalogo do CAEPI)", helper.ErrCampoObrigatorio, model.CA)
+	}
+
 	hoje := time.Now().Truncate(24 * time.Hour)
 
 	if model.DataValidadeCa.Time().Before(hoje) {
@@ -150,6 +176,7 @@ func (e *EpiService) ListarEpis(ctx context.Context, pagina, limite, tenantId in
 				ID:   int64(epi.Idtipoprotecao),
 				Nome: epi.TipoProtecaoNome,
 			},
+			FotoPrincipal: fotoPrincipalDto(epi.ID, epi.FotoID, epi.FotoNome, epi.FotoMime),
 		}
 
 		if e.Tamanho == nil {
@@ -228,6 +255,7 @@ func (e *EpiService) ListarEpi(ctx context.Context, id int, tenantid int32) (mod
 			ID:   int64(epi.Idtipoprotecao),
 			Nome: epi.TipoProtecaoNome,
 		},
+		FotoPrincipal: fotoPrincipalDto(epi.ID, epi.FotoID, epi.FotoNome, epi.FotoMime),
 	}, nil
 }
 
@@ -305,12 +333,40 @@ func (e *EpiService) AtualizaEpi(ctx context.Context, model model.UpdateEpiInput
 		return pgtype.Text{Valid: false} // Ou manter o valor antigo se sua query permitir COALESCE
 	}
 
+	// trocou o CA: o que não veio no corpo é completado pelo catálogo do CAEPI
+	if model.CA != nil {
+
+		ca := strings.TrimSpace(*model.CA)
+		model.CA = &ca
+
+		catalogo, achou, err := e.buscarCatalogoCa(ctx, ca)
+		if err != nil {
+			return err
+		}
+
+		if achou {
+			if model.Fabricante == nil {
+				fabricante := limitarTexto(catalogo.Fabricante, 100)
+				model.Fabricante = &fabricante
+			}
+
+			if model.Descricao == nil {
+				descricao := limitarTexto(descricaoCatalogo(catalogo), 250)
+				model.Descricao = &descricao
+			}
+
+			if model.ValidadeCa == nil && catalogo.Validade.Valid {
+				model.ValidadeCa = configs.NewDataBrPtr(catalogo.Validade.Time)
+			}
+		}
+	}
+
 	// Tratamento seguro para Data
 	var validadeCa pgtype.Date
 	if model.ValidadeCa != nil {
 		hoje := time.Now().Truncate(24 * time.Hour)
 
-		if validadeCa.Time.Before(hoje) {
+		if model.ValidadeCa.Time().Before(hoje) {
 
 			return helper.ErrDataMenor
 		}
@@ -368,3 +424,32 @@ func (e *EpiService) AtualizaEpi(ctx context.Context, model model.UpdateEpiInput
 
 	return tx.Commit(ctx)
 }
+
+// buscarCatalogoCa procura o CA no catálogo do CAEPI; CA fora do catálogo não é erro (achou = false),
+// mas CA cancelado ou suspenso não pode ser cadastrado
+func (e *EpiService) buscarCatalogoCa(ctx context.Context, ca string) (repository.BuscarCatalogoCaRow, bool, error) {
+
+	catalogo, err := e.queries.BuscarCatalogoCa(ctx, ca)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.BuscarCatalogoCaRow{}, false, nil
+		}
+
+		return repository.BuscarCatalogoCaRow{}, false, fmt.Errorf("erro ao buscar CA no catalogo, %w", err)
+	}
+
+	if caCancelado(catalogo.Situacao) {
+		return repository.BuscarCatalogoCaRow{}, false, fmt.Errorf("%w: CA %s (%s)", helper.ErrCaCancelado, ca, catalogo.Situacao)
+	}
+
+	return catalogo, true, nil
+}
+
+// descricaoCatalogo: nem todo CA tem a descrição detalhada; nesse caso fica o nome do equipamento
+func descricaoCatalogo(catalogo repository.BuscarCatalogoCaRow) string {
+
+	if catalogo.Descricao != "" {
+		return catalogo.Descricao
+	}
+	return catalogo.Equipamento
+}
@@ -4,38 +4,55 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type FuncionarioRepository interface {
 	Adicionar(ctx context.Context, args repository.AddFuncionarioParams) error
 	ListarFuncionario(ctx context.Context, arg repository.BuscaFuncionarioParams) (repository.BuscaFuncionarioRow, error)
-	ListarFuncionarios(ctx context.Context, tenantId int32) ([]repository.BuscarTodosFuncionariosRow, error)
-	CancelarFuncionario(ctx context.Context, arg repository.DeletarFuncionarioParams) (int64, error)
+	ListarFuncionarios(ctx context.Context, arg repository.BuscarTodosFuncionariosParams) ([]repository.BuscarTodosFuncionariosRow, error)
+	TotalFuncionarios(ctx context.Context, arg repository.ContarFuncionariosFiltradosParams) (int64, error)
 	AtualizarFuncionarioNome(ctx context.Context, arg repository.UpdateFuncionarioNomeParams, qtx *repository.Queries) (int64, error)
 	AtualizarFuncionarioDepartamento(ctx context.Context, arg repository.UpdateFuncionarioDepartamentoParams, qtx *repository.Queries) (int64, error)
 	AtualizarFuncionarioFuncao(ctx context.Context, arg repository.UpdateFuncionarioFuncaoParams, qtx *repository.Queries) (int64, error)
+	HistoricoCargos(ctx context.Context, arg repository.ListarHistoricoCargoParams) ([]repository.ListarHistoricoCargoRow, error)
 }
 
 type FuncionarioService struct {
 	repo    FuncionarioRepository
 	db      *pgxpool.Pool
 	queries *repository.Queries
+	posse   PosseService
 }
 
-func NewFuncionarioService(f FuncionarioRepository, pool *pgxpool.Pool) *FuncionarioService {
-	return &FuncionarioService{repo: f, db: pool, queries: repository.New(pool)}
+func NewFuncionarioService(f FuncionarioRepository, pool *pgxpool.Pool, posse PosseService) *FuncionarioService {
+	return &FuncionarioService{repo: f, db: pool, queries: repository.New(pool), posse: posse}
 }
 
 func (f *FuncionarioService) SalvarFuncionario(ctx context.Context, model model.FuncionarioINserir, tenantId int32) error {
 
 	model.Nome = strings.TrimSpace(model.Nome)
+	cpf := helper.SomenteDigitosCPF(model.Cpf)
+	email := strings.TrimSpace(model.Email)
+	telefone := strings.TrimSpace(model.Telefone)
+
+	if cpf != "" {
+		err := f.verificaCpfDisponivel(ctx, f.queries, cpf, 0, tenantId)
+		if err != nil {
+			return err
+		}
+	}
 
 	args := repository.AddFuncionarioParams{
 		Nome:           model.Nome,
@@ -43,6 +60,11 @@ func (f *FuncionarioService) SalvarFuncionario(ctx context.Context, model model.
 		Iddepartamento: int32(model.ID_departamento),
 		Idfuncao:       int32(model.ID_funcao),
 		TenantID:       tenantId,
+		DataAdmissao:   pgtype.Date{Time: model.DataAdmissao.Time(), Valid: !model.DataAdmissao.IsZero()},
+		Cpf:            pgtype.Text{String: cpf, Valid: cpf != ""},
+		DataNascimento: pgtype.Date{Time: model.DataNascimento.Time(), Valid: !model.DataNascimento.IsZero()},
+		Email:          pgtype.Text{String: email, Valid: email != ""},
+		Telefone:       pgtype.Text{String: telefone, Valid: telefone != ""},
 	}
 	err := f.repo.Adicionar(ctx, args)
 	if err != nil {
@@ -65,6 +87,25 @@ func (f *FuncionarioService) SalvarFuncionario(ctx context.Context, model model.
 	return nil
 }
 
+// verificaCpfDisponivel dá um erro claro antes do índice único do banco barrar o CPF repetido
+func (f *FuncionarioService) verificaCpfDisponivel(ctx context.Context, qtx *repository.Queries, cpf string, idIgnorar int32, tenantId int32) error {
+
+	existe, err := qtx.ExisteCpfFuncionario(ctx, repository.ExisteCpfFuncionarioParams{
+		TenantID:  tenantId,
+		Cpf:       pgtype.Text{String: cpf, Valid: true},
+		IDIgnorar: idIgnorar,
+	})
+	if err != nil {
+		return err
+	}
+
+	if existe {
+		return helper.ErrCpfDuplicado
+	}
+
+	return nil
+}
+
 func (f *FuncionarioService) ListarFuncionario(ctx context.Context, matricula string, tenantId int32) (model.Funcionario_Dto, error) {
 
 	if matricula <= "" {
@@ -97,17 +138,67 @@ func (f *FuncionarioService) ListarFuncionario(ctx context.Context, matricula st
 			},
 		},
 	}
+	preencherCiclo(&funcDto, funcionario.Ativo, funcionario.DataAdmissao, funcionario.DataDesligamento, funcionario.MotivoDesligamento)
+	funcDto.Cpf = helper.MascararCPF(funcionario.Cpf.String)
 
 	return funcDto, nil
 
 }
 
-func (f *FuncionarioService) ListaTodosFuncionarios(ctx context.Context, tenantId int32) ([]model.Funcionario_Dto, error) {
+type FiltroFuncionarios struct {
+	model.PaginacaoParams
+	DepartamentoID int32  `form:"departamento_id"`
+	FuncaoID       int32  `form:"funcao_id"`
+	Nome           string `form:"nome"`
+	Matricula      string `form:"matricula"`
+	Situacao       string `form:"situacao,default=ativos" binding:"oneof=ativos desligados todos"`
+	Ordenar        string `form:"ordenar,default=nome" binding:"oneof=nome matricula departamento funcao"`
+	Decrescente    bool   `form:"decrescente"`
+}
+
+type FuncionarioPaginado struct {
+	Funcionarios []model.Funcionario_Dto `json:"funcionarios"`
+	Total        int64                   `json:"total"`
+	Pagina       int32                   `json:"pagina"`
+	PaginaFinal  int32                   `json:"pagina_final"`
+}
+
+func (f *FuncionarioService) ListaTodosFuncionarios(ctx context.Context, filtro FiltroFuncionarios, tenantId int32) (FuncionarioPaginado, error) {
+
+	limit := filtro.Limite
+	if limit <= 0 {
+		limit = 10
+	}
+	paginaAtual := filtro.Pagina
+	if paginaAtual <= 0 {
+		paginaAtual = 1
+	}
+	offset := (paginaAtual - 1) * limit
+
+	nome := strings.TrimSpace(filtro.Nome)
+	matricula := strings.TrimSpace(filtro.Matricula)
+	situacao := filtro.Situacao
+	if situacao == "" {
+		situacao = "ativos"
+	}
 
-	funcionarios, err := f.repo.ListarFuncionarios(ctx, tenantId)
+	arg := repository.BuscarTodosFuncionariosParams{
+		TenantID:       tenantId,
+		Situacao:       situacao,
+		IDDepartamento: pgtype.Int4{Int32: filtro.DepartamentoID, Valid: filtro.DepartamentoID > 0},
+		IDFuncao:       pgtype.Int4{Int32: filtro.FuncaoID, Valid: filtro.FuncaoID > 0},
+		Nome:           pgtype.Text{String: nome, Valid: nome != ""},
+		Matricula:      pgtype.Text{String: matricula, Valid: matricula != ""},
+		Ordenar:        filtro.Ordenar,
+		Decrescente:    filtro.Decrescente,
+		Limit:          limit,
+		Offset:         offset,
+	}
+
+	funcionarios, err := f.repo.ListarFuncionarios(ctx, arg)
 	if err != nil {
 
-		return []model.Funcionario_Dto{}, err
+		return FuncionarioPaginado{}, err
 	}
 
 	funcionariosDto := make([]model.Funcionario_Dto, 0, len(funcionarios))
@@ -128,37 +219,141 @@ func (f *FuncionarioService) ListaTodosFuncionarios(ctx context.Context, tenantI
 			},
 		}
 
+		preencherCiclo(&funcDto, funcionario.Ativo, funcionario.DataAdmissao, funcionario.DataDesligamento, funcionario.MotivoDesligamento)
+		funcDto.Cpf = helper.MascararCPF(funcionario.Cpf.String)
+
 		funcionariosDto = append(funcionariosDto, funcDto)
 
 	}
 
-	if funcionariosDto == nil {
+	total, err := f.repo.TotalFuncionarios(ctx, repository.ContarFuncionariosFiltradosParams{
+		TenantID:       tenantId,
+		Situacao:       situacao,
+		IDDepartamento: arg.IDDepartamento,
+		IDFuncao:       arg.IDFuncao,
+		Nome:           arg.Nome,
+		Matricula:      arg.Matricula,
+	})
+	if err != nil {
 
-		return []model.Funcionario_Dto{}, nil
+		return FuncionarioPaginado{}, err
 	}
 
-	return funcionariosDto, nil
+	return FuncionarioPaginado{
+		Funcionarios: funcionariosDto,
+		Total:        total,
+		Pagina:       paginaAtual,
+		PaginaFinal:  int32(math.Ceil(float64(total) / float64(limit))),
+	}, nil
 
 }
 
+// DeletarFuncionario é o desligamento sem motivo nem baixas, na data de hoje: também recusa quem ainda tem epi em posse
 func (f *FuncionarioService) DeletarFuncionario(ctx context.Context, id int, tenantId int32) error {
 
-	linhas, err := f.repo.CancelarFuncionario(ctx, repository.DeletarFuncionarioParams{
+	_, err := f.Desligar(ctx, id, model.DesligamentoFuncionario{}, 0, tenantId)
+	return err
+}
+
+// Desligar registra as baixas informadas e só desliga se depois delas nada ficar em posse do funcionario.
+// Se ficar, devolve o que falta junto com helper.ErrPosseDesligamento e nada é gravado
+func (f *FuncionarioService) Desligar(ctx context.Context, id int, req model.DesligamentoFuncionario, idUsuario int, tenantId int32) ([]model.PosseEpiDto, error) {
+
+	if id <= 0 {
+		return []model.PosseEpiDto{}, helper.ErrId
+	}
+
+	tx, err := f.db.Begin(ctx)
+	if err != nil {
+		return []model.PosseEpiDto{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := f.queries.WithTx(tx)
+
+	err = qtx.TravarFuncionarioPosse(ctx, repository.TravarFuncionarioPosseParams{
 		ID:       int32(id),
 		TenantID: tenantId,
 	})
 	if err != nil {
+		return []model.PosseEpiDto{}, err
+	}
 
-		return fmt.Errorf("erro ao deletar funcionario, %w, funcionario ja pode estar inativo", err)
+	funcionario, err := qtx.BuscaFuncionarioPorId(ctx, repository.BuscaFuncionarioPorIdParams{
+		ID:       int32(id),
+		TenantID: tenantId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []model.PosseEpiDto{}, helper.ErrNaoEncontrado
+		}
+		return []model.PosseEpiDto{}, err
 	}
 
-	if linhas == 0 {
+	// BuscaFuncionarioPorId não traz as datas do ciclo, a busca por matrícula traz
+	ciclo, err := qtx.BuscaFuncionario(ctx, repository.BuscaFuncionarioParams{
+		Matricula: funcionario.Matricula,
+		TenantID:  tenantId,
+	})
+	if err != nil {
+		return []model.PosseEpiDto{}, err
+	}
 
-		return helper.ErrNaoEncontrado
+	hoje := time.Now()
+	dataDesligamento := time.Date(hoje.Year(), hoje.Month(), hoje.Day(), 0, 0, 0, 0, time.UTC)
+	if !req.DataDesligamento.IsZero() {
+		dataDesligamento = req.DataDesligamento.Time()
+	}
+	if ciclo.DataAdmissao.Valid && dataDesligamento.Before(ciclo.DataAdmissao.Time) {
+		return []model.PosseEpiDto{}, helper.ErrDataDesligamento
 	}
 
-	return nil
+	for _, baixa := range req.Baixas {
+
+		err := f.posse.RegistrarBaixa(ctx, qtx, funcionario.ID, baixa, idUsuario, tenantId)
+		if err != nil {
+			return []model.PosseEpiDto{}, err
+		}
+	}
 
+	pendencias, err := f.posse.Pendencias(ctx, qtx, funcionario.ID, tenantId)
+	if err != nil {
+		return []model.PosseEpiDto{}, err
+	}
+	if len(pendencias) > 0 {
+		return pendencias, helper.ErrPosseDesligamento
+	}
+
+	motivo := strings.TrimSpace(req.Motivo)
+
+	linhas, err := qtx.DesligarFuncionario(ctx, repository.DesligarFuncionarioParams{
+		DataDesligamento:   pgtype.Date{Time: dataDesligamento, Valid: true},
+		MotivoDesligamento: pgtype.Text{String: motivo, Valid: motivo != ""},
+		IDUsuario:          pgtype.Int4{Int32: int32(idUsuario), Valid: idUsuario > 0},
+		ID:                 funcionario.ID,
+		TenantID:           tenantId,
+	})
+	if err != nil {
+		return []model.PosseEpiDto{}, helper.TraduzErroPostgres(err)
+	}
+	if linhas == 0 {
+		return []model.PosseEpiDto{}, helper.ErrNaoEncontrado
+	}
+
+	return []model.PosseEpiDto{}, tx.Commit(ctx)
+}
+
+// preencherCiclo copia admissão/desligamento para o dto; data nula sai como null no json
+func preencherCiclo(dto *model.Funcionario_Dto, ativo bool, admissao, desligamento pgtype.Date, motivo pgtype.Text) {
+
+	dto.Ativo = ativo
+	dto.MotivoDesligamento = motivo.String
+	if admissao.Valid {
+		dto.DataAdmissao = configs.NewDataBrPtr(admissao.Time)
+	}
+	if desligamento.Valid {
+		dto.DataDesligamento = configs.NewDataBrPtr(desligamento.Time)
+	}
 }
 
 func (f *FuncionarioService) AtualizaNomeFuncionario(ctx context.Context, id int, nome string, tenantId int32, qtx *repository.Queries) error {
@@ -279,5 +474,466 @@ func (f *FuncionarioService) AtualizarFuncionarioCompleto(ctx context.Context, i
 		}
 	}
 
+	// 4. Atualiza dados pessoais (só os campos enviados)
+	if req.Cpf != nil || req.DataNascimento != nil || req.Email != nil || req.Telefone != nil {
+		err := f.AtualizaDadosPessoais(ctx, int32(id), req, int32(tenantId), qtx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// 5. Transferência fecha o cargo antigo e abre o novo no histórico
+	if req.IdDepartamento != nil || req.IdFuncao != nil {
+		err := f.registrarMudancaCargo(ctx, qtx, int32(id), req.DataEfetiva, int32(tenantId))
+		if err != nil {
+			return err
+		}
+	}
+
 	return tx.Commit(ctx)
 }
+
+func (f *FuncionarioService) AtualizaDadosPessoais(ctx context.Context, id int32, req model.UpdateFuncionarioRequest, tenantId int32, qtx *repository.Queries) error {
+
+	args := repository.UpdateFuncionarioDadosPessoaisParams{
+		ID:       id,
+		TenantID: tenantId,
+	}
+
+	if req.Cpf != nil {
+		cpf := helper.SomenteDigitosCPF(*req.Cpf)
+		if cpf == "" {
+			// CPF vazio apaga o campo; gravar "" faria dois funcionarios sem CPF colidirem no índice único
+			args.LimparCpf = true
+		} else {
+			err := f.verificaCpfDisponivel(ctx, qtx, cpf, id, tenantId)
+			if err != nil {
+				return err
+			}
+			args.Cpf = pgtype.Text{String: cpf, Valid: true}
+		}
+	}
+	if req.DataNascimento != nil && !req.DataNascimento.IsZero() {
+		args.DataNascimento = pgtype.Date{Time: req.DataNascimento.Time(), Valid: true}
+	}
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		args.Email = pgtype.Text{String: email, Valid: email != ""}
+	}
+	if req.Telefone != nil {
+		telefone := strings.TrimSpace(*req.Telefone)
+		args.Telefone = pgtype.Text{String: telefone, Valid: telefone != ""}
+	}
+
+	linhas, err := qtx.UpdateFuncionarioDadosPessoais(ctx, args)
+	if err != nil {
+		return helper.TraduzErroPostgres(err)
+	}
+
+	if linhas == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+// DadosPessoais devolve o CPF completo e os contatos. O acesso é gravado antes de devolver:
+// se não der para registrar quem consultou, os dados não saem
+func (f *FuncionarioService) DadosPessoais(ctx context.Context, id int, idUsuario int, tenantId int32) (model.DadosPessoaisFuncionarioDto, error) {
+
+	if id <= 0 {
+		return model.DadosPessoaisFuncionarioDto{}, helper.ErrId
+	}
+
+	dados, err := f.queries.BuscarDadosPessoaisFuncionario(ctx, repository.BuscarDadosPessoaisFuncionarioParams{
+		ID:       int32(id),
+		TenantID: tenantId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.DadosPessoaisFuncionarioDto{}, helper.ErrNaoEncontrado
+		}
+		return model.DadosPessoaisFuncionarioDto{}, err
+	}
+
+	err = f.queries.RegistrarAcessoDadosPessoais(ctx, repository.RegistrarAcessoDadosPessoaisParams{
+		TenantID:      tenantId,
+		Idfuncionario: dados.ID,
+		Idusuario:     int32(idUsuario),
+	})
+	if err != nil {
+		return model.DadosPessoaisFuncionarioDto{}, fmt.Errorf("erro ao registrar o acesso aos dados pessoais: %w", err)
+	}
+
+	dto := model.DadosPessoaisFuncionarioDto{
+		ID:        int(dados.ID),
+		Nome:      dados.Nome,
+		Matricula: dados.Matricula,
+		Cpf:       helper.FormatarCPF(dados.Cpf.String),
+		Email:     dados.Email.String,
+		Telefone:  dados.Telefone.String,
+	}
+	if dados.DataNascimento.Valid {
+		dto.DataNascimento = configs.NewDataBrPtr(dados.DataNascimento.Time)
+	}
+	if dados.DataAdmissao.Valid {
+		dto.DataAdmissao = configs.NewDataBrPtr(dados.DataAdmissao.Time)
+	}
+
+	return dto, nil
+}
+
+// registrarMudancaCargo compara o cadastro (já atualizado) com o cargo aberto no histórico.
+// Se mudou, o cargo aberto termina na data efetiva e o novo começa nela
+func (f *FuncionarioService) registrarMudancaCargo(ctx context.Context, qtx *repository.Queries, id int32, dataEfetiva configs.DataBr, tenantId int32) error {
+
+	funcionario, err := qtx.BuscaFuncionarioPorId(ctx, repository.BuscaFuncionarioPorIdParams{
+		ID:       id,
+		TenantID: tenantId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return helper.ErrNaoEncontrado
+		}
+		return err
+	}
+
+	hoje := time.Now()
+	data := time.Date(hoje.Year(), hoje.Month(), hoje.Day(), 0, 0, 0, 0, time.UTC)
+	if !dataEfetiva.IsZero() {
+		if dataEfetiva.Time().After(data) {
+			return helper.ErrDataCargo
+		}
+		data = dataEfetiva.Time()
+	}
+
+	atual, err := qtx.BuscarCargoAtual(ctx, repository.BuscarCargoAtualParams{
+		Idfuncionario: id,
+		TenantID:      tenantId,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	if err == nil {
+
+		if atual.Iddepartamento == funcionario.Iddepartamento && atual.Idfuncao == funcionario.Idfuncao {
+			return nil
+		}
+
+		if data.Before(atual.DataInicio.Time) {
+			return helper.ErrDataCargo
+		}
+
+		err = qtx.EncerrarCargo(ctx, repository.EncerrarCargoParams{
+			DataFim:  pgtype.Date{Time: data, Valid: true},
+			ID:       atual.ID,
+			TenantID: tenantId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return qtx.AbrirCargo(ctx, repository.AbrirCargoParams{
+		TenantID:       tenantId,
+		Idfuncionario:  id,
+		Iddepartamento: funcionario.Iddepartamento,
+		Idfuncao:       funcionario.Idfuncao,
+		DataInicio:     pgtype.Date{Time: data, Valid: true},
+	})
+}
+
+func (f *FuncionarioService) HistoricoCargos(ctx context.Context, id int, tenantId int32) ([]model.CargoHistoricoDto, error) {
+
+	if id <= 0 {
+		return []model.CargoHistoricoDto{}, helper.ErrId
+	}
+
+	historico, err := f.repo.HistoricoCargos(ctx, repository.ListarHistoricoCargoParams{
+		Idfuncionario: int32(id),
+		TenantID:      tenantId,
+	})
+	if err != nil {
+		return []model.CargoHistoricoDto{}, err
+	}
+
+	if len(historico) == 0 {
+		return []model.CargoHistoricoDto{}, helper.ErrNaoEncontrado
+	}
+
+	cargos := make([]model.CargoHistoricoDto, 0, len(historico))
+	for _, h := range historico {
+
+		cargo := model.CargoHistoricoDto{
+			Funcao: model.FuncaoDto{
+				ID:     int(h.FuncaoID),
+				Funcao: h.FuncaoNome,
+				Departamento: model.DepartamentoDto{
+					ID:           int(h.DepID),
+					Departamento: h.DepNome,
+				},
+			},
+			DataInicio: configs.DataBr(h.DataInicio.Time),
+		}
+		if h.DataFim.Valid {
+			cargo.DataFim = configs.NewDataBrPtr(h.DataFim.Time)
+		}
+
+		cargos = append(cargos, cargo)
+	}
+
+	return cargos, nil
+}
+
+var colunasImportacao = []string{"nome", "matricula", "departamento", "funcao"}
+
+var semAcento = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ú", "u", "ü", "u", "ç", "c",
+)
+
+// chaveImportacao compara nomes da planilha com o que já está cadastrado ignorando caixa, acento e espaços repetidos
+func chaveImportacao(s string) string {
+
+	return semAcento.Replace(strings.Join(strings.Fields(strings.ToLower(s)), " "))
+}
+
+type linhaImportacao struct {
+	numero       int
+	nome         string
+	matricula    string
+	departamento string
+	funcao       string
+}
+
+// ImportarFuncionarios valida a planilha inteira antes de gravar: se qualquer linha tiver erro nada é importado.
+// Tudo roda numa transação só; no dry-run ela é descartada no final e só o relatório volta
+func (f *FuncionarioService) ImportarFuncionarios(ctx context.Context, linhas [][]string, opcoes model.ImportacaoFuncionarioOpcoes, tenantId int32) (model.ResultadoImportacaoFuncionarios, error) {
+
+	resultado := model.ResultadoImportacaoFuncionarios{
+		DryRun:               opcoes.DryRun,
+		DepartamentosCriados: []string{},
+		FuncoesCriadas:       []string{},
+		Erros:                []model.ErroLinhaImportacao{},
+	}
+
+	// o cabeçalho é a primeira linha que não está em branco
+	cabecalho := 0
+	for cabecalho < len(linhas) && len(linhas[cabecalho]) == 0 {
+		cabecalho++
+	}
+	if cabecalho == len(linhas) {
+		return resultado, fmt.Errorf("%w: a planilha está vazia", helper.ErrCampoObrigatorio)
+	}
+
+	posicao := make(map[string]int, len(colunasImportacao))
+	for i, coluna := range linhas[cabecalho] {
+		posicao[chaveImportacao(coluna)] = i
+	}
+	for _, coluna := range colunasImportacao {
+		if _, ok := posicao[coluna]; !ok {
+			return resultado, fmt.Errorf("%w: coluna %s não encontrada no cabeçalho", helper.ErrCampoObrigatorio, coluna)
+		}
+	}
+
+	celula := func(linha []string, coluna string) string {
+		i := posicao[coluna]
+		if i >= len(linha) {
+			return ""
+		}
+		return strings.Join(strings.Fields(linha[i]), " ")
+	}
+
+	tx, err := f.db.Begin(ctx)
+	if err != nil {
+		return resultado, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := f.queries.WithTx(tx)
+
+	departamentos, err := qtx.BuscarTodosDepartamentos(ctx, tenantId)
+	if err != nil {
+		return resultado, helper.TraduzErroPostgres(err)
+	}
+	idDepartamento := make(map[string]int32, len(departamentos))
+	for _, d := range departamentos {
+		idDepartamento[chaveImportacao(d.Nome)] = d.ID
+	}
+
+	funcoes, err := qtx.BuscarTodasFuncoes(ctx, tenantId)
+	if err != nil {
+		return resultado, helper.TraduzErroPostgres(err)
+	}
+	// a função pertence a um departamento, então a chave é o par departamento/função
+	idFuncao := make(map[[2]string]int32, len(funcoes))
+	for _, fn := range funcoes {
+		idFuncao[[2]string{chaveImportacao(fn.DepartamentoNome), chaveImportacao(fn.Nome)}] = fn.ID
+	}
+
+	cadastradas, err := qtx.ListarMatriculasTenant(ctx, tenantId)
+	if err != nil {
+		return resultado, helper.TraduzErroPostgres(err)
+	}
+	matriculaAtiva := make(map[string]bool, len(cadastradas))
+	for _, m := range cadastradas {
+		matriculaAtiva[m.Matricula] = m.Ativo
+	}
+
+	// mapas para não repetir, slices para criar na ordem em que aparecem na planilha
+	novosDepartamentos := map[string]string{}
+	novasFuncoes := map[[2]string]string{}
+	ordemDepartamentos := []string{}
+	ordemFuncoes := [][2]string{}
+	linhaDaMatricula := map[string]int{}
+	validas := make([]linhaImportacao, 0, len(linhas)-cabecalho-1)
+
+	// o LerPlanilha devolve as linhas em branco também, então linhas[k] é a linha k+1 do arquivo
+	for i, linha := range linhas[cabecalho+1:] {
+
+		l := linhaImportacao{
+			numero:       cabecalho + i + 2,
+			nome:         celula(linha, "nome"),
+			matricula:    celula(linha, "matricula"),
+			departamento: celula(linha, "departamento"),
+			funcao:       celula(linha, "funcao"),
+		}
+
+		if l.nome == "" && l.matricula == "" && l.departamento == "" && l.funcao == "" {
+			continue
+		}
+		resultado.TotalLinhas++
+
+		// mesmas regras do binding de model.FuncionarioINserir
+		var erros []string
+		if n := utf8.RuneCountInString(l.nome); n < 3 || n > 150 {
+			erros = append(erros, "nome deve ter entre 3 e 150 caracteres")
+		}
+		if utf8.RuneCountInString(l.matricula) != 7 {
+			erros = append(erros, "matricula deve ter exatamente 7 caracteres")
+		} else if anterior, repetida := linhaDaMatricula[l.matricula]; repetida {
+			erros = append(erros, fmt.Sprintf("matricula repetida na planilha (linha %d)", anterior))
+		} else if ativa, existe := matriculaAtiva[l.matricula]; existe {
+			if ativa {
+				erros = append(erros, "matricula ja cadastrada")
+			} else {
+				erros = append(erros, "matricula ja usada por um funcionario desligado")
+			}
+		}
+		if _, repetida := linhaDaMatricula[l.matricula]; !repetida && l.matricula != "" {
+			linhaDaMatricula[l.matricula] = l.numero
+		}
+
+		chaveDep := chaveImportacao(l.departamento)
+		chaveFun := [2]string{chaveDep, chaveImportacao(l.funcao)}
+
+		switch {
+		case l.departamento == "":
+			erros = append(erros, "departamento não informado")
+		case utf8.RuneCountInString(l.departamento) > 100:
+			erros = append(erros, "departamento deve ter no máximo 100 caracteres")
+		default:
+			if _, existe := idDepartamento[chaveDep]; !existe {
+				if !opcoes.CriarCadastros {
+					erros = append(erros, fmt.Sprintf("departamento %q não cadastrado", l.departamento))
+				} else if _, pendente := novosDepartamentos[chaveDep]; !pendente {
+					novosDepartamentos[chaveDep] = l.departamento
+					ordemDepartamentos = append(ordemDepartamentos, chaveDep)
+					resultado.DepartamentosCriados = append(resultado.DepartamentosCriados, l.departamento)
+				}
+			}
+		}
+
+		switch {
+		case l.funcao == "":
+			erros = append(erros, "função não informada")
+		case utf8.RuneCountInString(l.funcao) > 100:
+			erros = append(erros, "função deve ter no máximo 100 caracteres")
+		case l.departamento != "":
+			if _, existe := idFuncao[chaveFun]; !existe {
+				if !opcoes.CriarCadastros {
+					erros = append(erros, fmt.Sprintf("função %q não cadastrada no departamento %q", l.funcao, l.departamento))
+				} else if _, pendente := novasFuncoes[chaveFun]; !pendente {
+					novasFuncoes[chaveFun] = l.funcao
+					ordemFuncoes = append(ordemFuncoes, chaveFun)
+					resultado.FuncoesCriadas = append(resultado.FuncoesCriadas, l.departamento+" / "+l.funcao)
+				}
+			}
+		}
+
+		if len(erros) > 0 {
+			resultado.Erros = append(resultado.Erros, model.ErroLinhaImportacao{
+				Linha:     l.numero,
+				Matricula: l.matricula,
+				Erros:     erros,
+			})
+			continue
+		}
+
+		validas = append(validas, l)
+	}
+
+	resultado.Validas = len(validas)
+
+	if resultado.TotalLinhas == 0 {
+		return resultado, fmt.Errorf("%w: a planilha não tem funcionarios", helper.ErrCampoObrigatorio)
+	}
+
+	if opcoes.DryRun {
+		return resultado, nil
+	}
+
+	if len(resultado.Erros) > 0 {
+		return resultado, helper.ErrImportacaoInvalida
+	}
+
+	for _, chave := range ordemDepartamentos {
+
+		id, err := qtx.CriarDepartamentoImportacao(ctx, repository.CriarDepartamentoImportacaoParams{
+			TenantID: tenantId,
+			Nome:     novosDepartamentos[chave],
+		})
+		if err != nil {
+			return resultado, helper.TraduzErroPostgres(err)
+		}
+		idDepartamento[chave] = id
+	}
+
+	for _, chave := range ordemFuncoes {
+
+		id, err := qtx.CriarFuncaoImportacao(ctx, repository.CriarFuncaoImportacaoParams{
+			TenantID:       tenantId,
+			Nome:           novasFuncoes[chave],
+			Iddepartamento: idDepartamento[chave[0]],
+		})
+		if err != nil {
+			return resultado, helper.TraduzErroPostgres(err)
+		}
+		idFuncao[chave] = id
+	}
+
+	for _, l := range validas {
+
+		chaveDep := chaveImportacao(l.departamento)
+
+		err := qtx.AddFuncionario(ctx, repository.AddFuncionarioParams{
+			TenantID:       tenantId,
+			Nome:           l.nome,
+			Matricula:      l.matricula,
+			Iddepartamento: idDepartamento[chaveDep],
+			Idfuncao:       idFuncao[[2]string{chaveDep, chaveImportacao(l.funcao)}],
+		})
+		if err != nil {
+			return resultado, fmt.Errorf("linha %d: %w", l.numero, helper.TraduzErroPostgres(err))
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return resultado, err
+	}
+
+	resultado.Importados = len(validas)
+
+	return resultado, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDesligarFuncionario(t *testing.T) {
+
+	db := SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	servFuncionario := NewFuncionarioService(repository.NewFuncionarioRepository(db), db, *NewPosseService(repository.NewPosseRepository(db)))
+
+	idEmpresa := CreateEmpresa(t, db)
+	iduser := CreateUser(t, db, idEmpresa)
+	iddep := CreateDepartamento(t, db, idEmpresa)
+	IdFuncao := CreateFuncao(t, db, iddep, idEmpresa)
+	idtam := CreateTamanho(t, db, idEmpresa)
+	idprotec := CreateProtecao(t, db, idEmpresa)
+	idepi := CreateEpi(t, db, idprotec, idEmpresa)
+	idfuncionario := CreateFuncionario(t, db, iddep, IdFuncao, idEmpresa)
+
+	Idfornecedor := CreateFornecedor(t, db, idEmpresa)
+	idEntrada := CreateEntradaEpi(t, db, idfuncionario, idepi, idprotec, idtam, Idfornecedor, iduser, idEmpresa)
+
+	// o funcionario está com 10 unidades
+	idEntrega := CreateEntregaEpi(t, db, idfuncionario, iduser, idEmpresa)
+	_ = CreateEpiEntregues(t, db, idEntrega, idEntrada, idepi, idtam, idEmpresa)
+
+	baixa := func(quantidade int) model.BaixaPosseInserir {
+
+		return model.BaixaPosseInserir{
+			IdEpi:      int(idepi),
+			IdTamanho:  int(idtam),
+			Quantidade: quantidade,
+			Motivo:     "extraviado na obra",
+		}
+	}
+
+	totalBaixado := func() int {
+
+		var total int
+		err := db.QueryRow(ctx, "SELECT COALESCE(SUM(quantidade), 0) FROM baixa_posse WHERE IdFuncionario = $1 AND tenant_id = $2", idfuncionario, idEmpresa).Scan(&total)
+		require.NoError(t, err)
+		return total
+	}
+
+	ativo := func() bool {
+
+		var ativo bool
+		err := db.QueryRow(ctx, "SELECT ativo FROM funcionario WHERE id = $1 AND tenant_id = $2", idfuncionario, idEmpresa).Scan(&ativo)
+		require.NoError(t, err)
+		return ativo
+	}
+
+	t.Run("ERRO - funcionario com epi em posse não é desligado", func(t *testing.T) {
+
+		pendencias, err := servFuncionario.Desligar(ctx, int(idfuncionario), model.DesligamentoFuncionario{
+			Motivo: "pedido de demissão",
+		}, int(iduser), int32(idEmpresa))
+		require.ErrorIs(t, err, helper.ErrPosseDesligamento)
+
+		require.Len(t, pendencias, 1)
+		require.Equal(t, int(idepi), pendencias[0].IdEpi)
+		require.Equal(t, 10, pendencias[0].Quantidade)
+		require.True(t, ativo())
+	})
+
+	t.Run("ERRO - baixa parcial ainda deixa pendência e nada é gravado", func(t *testing.T) {
+
+		pendencias, err := servFuncionario.Desligar(ctx, int(idfuncionario), model.DesligamentoFuncionario{
+			Motivo: "pedido de demissão",
+			Baixas: []model.BaixaPosseInserir{baixa(4)},
+		}, int(iduser), int32(idEmpresa))
+		require.ErrorIs(t, err, helper.ErrPosseDesligamento)
+
+		require.Len(t, pendencias, 1)
+		require.Equal(t, 6, pendencias[0].Quantidade)
+		require.Equal(t, 0, totalBaixado(), "a baixa parcial deveria ter sido desfeita junto com o desligamento")
+		require.True(t, ativo())
+	})
+
+	t.Run("ERRO - baixa maior que a posse é recusada", func(t *testing.T) {
+
+		_, err := servFuncionario.Desligar(ctx, int(idfuncionario), model.DesligamentoFuncionario{
+			Baixas: []model.BaixaPosseInserir{baixa(11)},
+		}, int(iduser), int32(idEmpresa))
+		require.ErrorIs(t, err, helper.ErrDevolucaoSemPosse)
+		require.Equal(t, 0, totalBaixado())
+	})
+
+	t.Run("baixando toda a posse o funcionario é desligado", func(t *testing.T) {
+
+		pendencias, err := servFuncionario.Desligar(ctx, int(idfuncionario), model.DesligamentoFuncionario{
+			Motivo: "pedido de demissão",
+			Baixas: []model.BaixaPosseInserir{baixa(10)},
+		}, int(iduser), int32(idEmpresa))
+		require.NoError(t, err)
+		require.Empty(t, pendencias)
+
+		require.Equal(t, 10, totalBaixado())
+		require.False(t, ativo())
+
+		var desligado bool
+		var motivo string
+		err = db.QueryRow(ctx, "SELECT data_desligamento IS NOT NULL, motivo_desligamento FROM funcionario WHERE id = $1 AND tenant_id = $2", idfuncionario, idEmpresa).Scan(&desligado, &motivo)
+		require.NoError(t, err)
+		require.True(t, desligado)
+		require.Equal(t, "pedido de demissão", motivo)
+	})
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ManutencaoRepository interface {
+	BuscarParaConclusao(ctx context.Context, qtx *repository.Queries, arg repository.BuscarOrdemParaConclusaoParams) (repository.BuscarOrdemParaConclusaoRow, error)
+	Concluir(ctx context.Context, qtx *repository.Queries, arg repository.ConcluirOrdemManutencaoParams) error
+	Listar(ctx context.Context, arg repository.ListarOrdensManutencaoParams) ([]repository.ListarOrdensManutencaoRow, error)
+	Relatorio(ctx context.Context, tenantId int32) ([]repository.RelatorioManutencaoRow, error)
+}
+
+type ManutencaoService struct {
+	repo    ManutencaoRepository
+	db      *pgxpool.Pool
+	queries *repository.Queries
+}
+
+func NewManutencaoService(r ManutencaoRepository, db *pgxpool.Pool) *ManutencaoService {
+
+	return &ManutencaoService{
+		repo:    r,
+		db:      db,
+		queries: repository.New(db),
+	}
+}
+
+// Concluir encerra a ordem de serviço (higienização ou inspeção). Só a liberada volta para o estoque disponível
+func (m *ManutencaoService) Concluir(ctx context.Context, id int, conclusao model.ConcluirOrdemManutencao, idUsuario int, tenantId int32) error {
+
+	if id <= 0 {
+		return helper.ErrId
+	}
+
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+	qtx := m.queries.WithTx(tx)
+
+	ordem, err := m.repo.BuscarParaConclusao(ctx, qtx, repository.BuscarOrdemParaConclusaoParams{
+		ID:       int32(id),
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return helper.ErrNaoEncontrado
+		}
+		return err
+	}
+
+	if ordem.Status != model.OrdemAberta {
+		return helper.ErrOrdemEncerrada
+	}
+
+	err = m.repo.Concluir(ctx, qtx, repository.ConcluirOrdemManutencaoParams{
+		ID:                 ordem.ID,
+		Status:             conclusao.Resultado,
+		ServicoRealizado:   pgtype.Text{String: conclusao.ServicoRealizado, Valid: true},
+		Observacao:         pgtype.Text{String: conclusao.Observacao, Valid: conclusao.Observacao != ""},
+		Idusuarioconclusao: pgtype.Int4{Int32: int32(idUsuario), Valid: true},
+		TenantID:           tenantId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if conclusao.Resultado == model.OrdemLiberada {
+
+		_, err := qtx.DevolverItemAoEstoque(ctx, repository.DevolverItemAoEstoqueParams{
+			Idepi:           ordem.Idepi,
+			Idtamanho:       ordem.Idtamanho,
+			Quantidadeatual: ordem.Quantidade,
+			TenantID:        tenantId,
+		})
+		if err != nil {
+
+			if errors.Is(err, pgx.ErrNoRows) {
+				return helper.ErrSemLoteDevolucao
+			}
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+type FiltroManutencao struct {
+	Status     string `form:"status"`
+	Tipo       string `form:"tipo"`
+	EpiID      int32  `form:"epi_id"`
+	Pagina     int32  `form:"pagina"`
+	Quantidade int32  `form:"quantidade"`
+}
+
+type ManutencaoPaginada struct {
+	Ordens      []model.OrdemManutencaoDto `json:"ordens"`
+	Total       int64                      `json:"total"`
+	Pagina      int32                      `json:"pagina"`
+	PaginaFinal int32                      `json:"pagina_final"`
+}
+
+func (m *ManutencaoService) Listar(ctx context.Context, f FiltroManutencao, tenantId int32) (ManutencaoPaginada, error) {
+
+	limit := f.Quantidade
+	if limit <= 0 {
+		limit = 1
+	}
+	paginaAtual := f.Pagina
+	if paginaAtual <= 0 {
+		paginaAtual = 1
+	}
+
+	offset := max((paginaAtual-1)*limit, 0)
+
+	ordens, err := m.repo.Listar(ctx, repository.ListarOrdensManutencaoParams{
+		TenantID: tenantId,
+		Status:   pgtype.Text{String: f.Status, Valid: f.Status != ""},
+		Tipo:     pgtype.Text{String: f.Tipo, Valid: f.Tipo != ""},
+		IDEpi:    pgtype.Int4{Int32: f.EpiID, Valid: f.EpiID > 0},
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return ManutencaoPaginada{}, err
+	}
+
+	dto := make([]model.OrdemManutencaoDto, 0, len(ordens))
+
+	for _, o := range ordens {
+
+		ordem := model.OrdemManutencaoDto{
+			Id:         int(o.ID),
+			Tipo:       o.Tipo,
+			Status:     o.Status,
+			IdEpi:      int(o.EpiID),
+			Epi:        o.EpiNome,
+			CA:         o.Ca,
+			IdTamanho:  int(o.TamID),
+			Tamanho:    o.TamNome,
+			Quantidade: int(o.Quantidade),
+			AbertaEm:   configs.DataBr(o.AbertaEm.Time),
+		}
+
+		if o.Iddevolucao.Valid {
+			idDevolucao := int(o.Iddevolucao.Int32)
+			ordem.IdDevolucao = &idDevolucao
+		}
+		if o.ServicoRealizado.Valid {
+			ordem.ServicoRealizado = &o.ServicoRealizado.String
+		}
+		if o.Observacao.Valid {
+			ordem.Observacao = &o.Observacao.String
+		}
+		if o.UsuarioConclusao.Valid {
+			ordem.ConcluidaPor = &o.UsuarioConclusao.String
+		}
+		if o.ConcluidaEm.Valid {
+			concluida := configs.DataBr(o.ConcluidaEm.Time)
+			ordem.ConcluidaEm = &concluida
+		}
+
+		dto = append(dto, ordem)
+	}
+
+	var total int64
+	if len(ordens) > 0 {
+		total = ordens[0].TotalGeral
+	}
+
+	return ManutencaoPaginada{
+		Ordens:      dto,
+		Total:       total,
+		Pagina:      paginaAtual,
+		PaginaFinal: int32(math.Ceil(float64(total) / float64(limit))),
+	}, nil
+}
+
+// Relatorio traz o que está parado em higienização/manutenção agora, por epi/tamanho
+func (m *ManutencaoService) Relatorio(ctx context.Context, tenantId int32) ([]model.ItemEmManutencaoDto, error) {
+
+	itens, err := m.repo.Relatorio(ctx, tenantId)
+	if err != nil {
+		return []model.ItemEmManutencaoDto{}, err
+	}
+
+	dto := make([]model.ItemEmManutencaoDto, 0, len(itens))
+	for _, i := range itens {
+		dto = append(dto, model.ItemEmManutencaoDto{
+			IdEpi:            int(i.EpiID),
+			Epi:              i.EpiNome,
+			CA:               i.Ca,
+			IdTamanho:        int(i.TamID),
+			Tamanho:          i.TamNome,
+			Quantidade:       int(i.Quantidade),
+			Ordens:           int(i.Ordens),
+			AbertaMaisAntiga: configs.DataBr(i.AbertaMaisAntiga.Time),
+		})
+	}
+
+	return dto, nil
+}
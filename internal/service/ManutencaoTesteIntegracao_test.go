@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcluirOrdemManutencao(t *testing.T) {
+
+	db := SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	servEntrega := NewEntregaService(repository.NewEntregaRepository(db), db)
+	servDevolucao := NewDevolucaoService(repository.NewDevolucaoRepository(db), db, *servEntrega, *NewPosseService(repository.NewPosseRepository(db)))
+	servManutencao := NewManutencaoService(repository.NewManutencaoRepository(db), db)
+
+	idEmpresa := CreateEmpresa(t, db)
+	iduser := CreateUser(t, db, idEmpresa)
+	iddep := CreateDepartamento(t, db, idEmpresa)
+	IdFuncao := CreateFuncao(t, db, iddep, idEmpresa)
+	idtam := CreateTamanho(t, db, idEmpresa)
+	idprotec := CreateProtecao(t, db, idEmpresa)
+	idepi := CreateEpi(t, db, idprotec, idEmpresa)
+	idfuncionario := CreateFuncionario(t, db, iddep, IdFuncao, idEmpresa)
+
+	Idfornecedor := CreateFornecedor(t, db, idEmpresa)
+	idEntrada := CreateEntradaEpi(t, db, idfuncionario, idepi, idprotec, idtam, Idfornecedor, iduser, idEmpresa)
+
+	// o funcionario está com 10 unidades (o estoque não muda com a fixture)
+	idEntrega := CreateEntregaEpi(t, db, idfuncionario, iduser, idEmpresa)
+	_ = CreateEpiEntregues(t, db, idEntrega, idEntrada, idepi, idtam, idEmpresa)
+
+	// higienização: o item não volta direto, vai para o pool de manutenção
+	idMotivo := CreateMotivoDevolucao(t, db, "Higienização", idEmpresa)
+	_, err := db.Exec(ctx, "UPDATE motivo_devolucao SET volta_estoque = FALSE, higienizacao = TRUE WHERE id = $1", idMotivo)
+	require.NoError(t, err)
+
+	estoqueAtual := func() int {
+
+		var estoque int
+		err := db.QueryRow(ctx, "SELECT quantidadeAtual FROM entrada_epi WHERE id = $1 AND tenant_id = $2", idEntrada, idEmpresa).Scan(&estoque)
+		require.NoError(t, err)
+		return estoque
+	}
+
+	// devolve pelo motivo de higienização e devolve a ordem aberta e a devolução que a abriu
+	devolverParaHigienizacao := func(quantidade int) (int, int) {
+
+		err := servDevolucao.SalvarDevolucao(ctx, model.DevolucaoInserir{
+			IdFuncionario:       int(idfuncionario),
+			IdEpi:               int(idepi),
+			IdMotivo:            int(idMotivo),
+			DataDevolucao:       *configs.NewDataBrPtr(time.Now()),
+			IdTamanho:           int(idtam),
+			QuantidadeADevolver: quantidade,
+			AssinaturaDigital:   "assinatura_base64_teste",
+			IdUser:              int(iduser),
+		}, int32(idEmpresa))
+		require.NoError(t, err)
+
+		var idOrdem, idDevolucao int
+		err = db.QueryRow(ctx, `
+			SELECT id, IdDevolucao FROM ordem_manutencao
+			WHERE tenant_id = $1 AND status = 'aberta'
+			ORDER BY id DESC LIMIT 1`, idEmpresa).Scan(&idOrdem, &idDevolucao)
+		require.NoError(t, err, "a devolução para higienização deveria ter aberto uma ordem")
+		return idOrdem, idDevolucao
+	}
+
+	idOrdem, idDevolucao := devolverParaHigienizacao(2)
+	require.Equal(t, 100, estoqueAtual(), "o item em higienização não deveria voltar ao estoque antes da ordem ser concluída")
+
+	t.Run("ordem liberada devolve o item ao estoque", func(t *testing.T) {
+
+		err := servManutencao.Concluir(ctx, idOrdem, model.ConcluirOrdemManutencao{
+			Resultado:        model.OrdemLiberada,
+			ServicoRealizado: "lavagem e secagem",
+		}, int(iduser), int32(idEmpresa))
+		require.NoError(t, err)
+
+		var status string
+		err = db.QueryRow(ctx, "SELECT status FROM ordem_manutencao WHERE id = $1 AND tenant_id = $2", idOrdem, idEmpresa).Scan(&status)
+		require.NoError(t, err)
+		require.Equal(t, model.OrdemLiberada, status)
+		require.Equal(t, 102, estoqueAtual())
+	})
+
+	t.Run("ERRO - ordem concluída não é concluída de novo", func(t *testing.T) {
+
+		err := servManutencao.Concluir(ctx, idOrdem, model.ConcluirOrdemManutencao{
+			Resultado:        model.OrdemLiberada,
+			ServicoRealizado: "lavagem e secagem",
+		}, int(iduser), int32(idEmpresa))
+		require.ErrorIs(t, err, helper.ErrOrdemEncerrada)
+		require.Equal(t, 102, estoqueAtual(), "a segunda conclusão não deveria ter devolvido o item outra vez")
+	})
+
+	t.Run("ERRO - devolução com ordem concluída não pode ser cancelada", func(t *testing.T) {
+
+		err := servDevolucao.CancelarDevolucao(ctx, idDevolucao, int(iduser), int(idEmpresa))
+		require.ErrorIs(t, err, helper.ErrOrdemConcluida)
+
+		var cancelada bool
+		err = db.QueryRow(ctx, "SELECT cancelada_em IS NOT NULL FROM devolucao WHERE id = $1 AND tenant_id = $2", idDevolucao, idEmpresa).Scan(&cancelada)
+		require.NoError(t, err)
+		require.False(t, cancelada)
+	})
+
+	t.Run("ordem descartada tira o item de circulação", func(t *testing.T) {
+
+		idOrdemDescarte, _ := devolverParaHigienizacao(1)
+
+		err := servManutencao.Concluir(ctx, idOrdemDescarte, model.ConcluirOrdemManutencao{
+			Resultado:        model.OrdemDescartada,
+			ServicoRealizado: "tentativa de reparo da costura",
+		}, int(iduser), int32(idEmpresa))
+		require.NoError(t, err)
+		require.Equal(t, 102, estoqueAtual())
+	})
+}
@@ -2,18 +2,21 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5"
 )
 
 type MotivoDevolucaoRepository interface {
 	Adicionar(ctx context.Context, motivo repository.AddMotivoDevolucaoParams) error 
 	ListarMotivo(ctx context.Context, arg repository.BuscaMotivoDevolucaoParams) (repository.BuscaMotivoDevolucaoRow, error)
 	ListarMotivos(ctx context.Context, tenantId int32) ([]repository.BuscaTodosMotivosDevolucaoRow, error)
+	Atualizar(ctx context.Context, arg repository.UpdateMotivoDevolucaoParams) (int64, error)
 	CancelarMotivoDevolucao(ctx context.Context, arg repository.DeleteMotivoDevolucaoParams) (int64, error)
 }
 
@@ -30,9 +33,17 @@ func (m *MotivoDevolucaoService) Salvar(ctx context.Context, model model.MotivoD
 
 	model.Motivo = strings.TrimSpace(model.Motivo)
 
+	if err := validarDestinoMotivo(&model); err != nil {
+		return err
+	}
+
 	err := m.repo.Adicionar(ctx, repository.AddMotivoDevolucaoParams{
 		Motivo: model.Motivo,
 		TenantID: tenantId,
+		VoltaEstoque:  model.VoltaEstoque,
+		Descarte:      model.Descarte,
+		Higienizacao:  model.Higienizacao,
+		ExigeInspecao: model.ExigeInspecao,
 	})
 	if err != nil {
 
@@ -42,6 +53,25 @@ func (m *MotivoDevolucaoService) Salvar(ctx context.Context, model model.MotivoD
 	return nil
 }
 
+// validarDestinoMotivo aceita no máximo um destino; sem nenhum marcado o epi volta para o estoque
+func validarDestinoMotivo(model *model.MotivoDevolucao) error {
+
+	destinos := 0
+	for _, marcado := range []bool{model.VoltaEstoque, model.Descarte, model.Higienizacao} {
+		if marcado {
+			destinos++
+		}
+	}
+	if destinos > 1 {
+		return helper.ErrDestinoMotivo
+	}
+	if destinos == 0 {
+		model.VoltaEstoque = true // mesmo padrão da coluna no banco
+	}
+
+	return nil
+}
+
 func (m *MotivoDevolucaoService) ListarMotivo(ctx context.Context, id int, tenantid int32) (model.MotivoDevolucaoEpiDto, error) {
 
 	if id <= 0 {
@@ -55,6 +85,9 @@ func (m *MotivoDevolucaoService) ListarMotivo(ctx context.Context, id int, tenan
 	})
 	if err != nil {
 
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.MotivoDevolucaoEpiDto{}, helper.ErrNaoEncontrado
+		}
 		return model.MotivoDevolucaoEpiDto{}, err
 	}
 
@@ -62,6 +95,10 @@ func (m *MotivoDevolucaoService) ListarMotivo(ctx context.Context, id int, tenan
 
 		Id: int(motivo.ID),
 		Motivo: motivo.Motivo,
+		VoltaEstoque:  motivo.VoltaEstoque,
+		Descarte:      motivo.Descarte,
+		Higienizacao:  motivo.Higienizacao,
+		ExigeInspecao: motivo.ExigeInspecao,
 	}, nil
 }
 
@@ -80,6 +117,10 @@ func (m *MotivoDevolucaoService) ListarMotivos(ctx context.Context, tenantId int
 		M := model.MotivoDevolucaoEpiDto{
 			Id: int(mot.ID),
 			Motivo: mot.Motivo,
+			VoltaEstoque:  mot.VoltaEstoque,
+			Descarte:      mot.Descarte,
+			Higienizacao:  mot.Higienizacao,
+			ExigeInspecao: mot.ExigeInspecao,
 		}
 		dto = append(dto, M)
 	}
@@ -87,6 +128,43 @@ func (m *MotivoDevolucaoService) ListarMotivos(ctx context.Context, tenantId int
 	return dto, nil
 }
 
+// AtualizarMotivo troca o nome e o destino do motivo. As devoluções já feitas guardam o destino aplicado,
+// então a mudança só vale para as próximas
+func (m *MotivoDevolucaoService) AtualizarMotivo(ctx context.Context, id int, model model.MotivoDevolucao, tenantId int32) error {
+
+	if id <= 0 {
+
+		return helper.ErrId
+	}
+
+	model.Motivo = strings.TrimSpace(model.Motivo)
+
+	if err := validarDestinoMotivo(&model); err != nil {
+		return err
+	}
+
+	linha, err := m.repo.Atualizar(ctx, repository.UpdateMotivoDevolucaoParams{
+		ID:            int32(id),
+		TenantID:      tenantId,
+		Motivo:        model.Motivo,
+		VoltaEstoque:  model.VoltaEstoque,
+		Descarte:      model.Descarte,
+		Higienizacao:  model.Higienizacao,
+		ExigeInspecao: model.ExigeInspecao,
+	})
+	if err != nil {
+
+		return fmt.Errorf("erro ao atualizar o motivo, %w", err)
+	}
+
+	if linha == 0 {
+
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
 func (m *MotivoDevolucaoService) DeletarMotivo(ctx context.Context, id int, tenantId int32) error {
 	
 	if id <= 0 {
@@ -100,7 +178,7 @@ func (m *MotivoDevolucaoService) DeletarMotivo(ctx context.Context, id int, tena
 	})
 	if err != nil {
 
-		return  fmt.Errorf("erro ao deletar a funcao, %w", err)
+		return  fmt.Errorf("erro ao deletar o motivo, %w", err)
 	}
 
 
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtualizarMotivoDevolucao(t *testing.T) {
+
+	db := SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	servMotivo := NewMotivoDevolucaoRepositoryServe(repository.NewMotivoDevolucaoRepository(db))
+
+	idEmpresa := CreateEmpresa(t, db)
+	idMotivo := CreateMotivoDevolucao(t, db, "Dano", idEmpresa)
+
+	t.Run("troca o destino do motivo", func(t *testing.T) {
+
+		err := servMotivo.AtualizarMotivo(ctx, int(idMotivo), model.MotivoDevolucao{
+			Motivo:        "Dano sem conserto",
+			Descarte:      true,
+			ExigeInspecao: true,
+		}, int32(idEmpresa))
+		require.NoError(t, err)
+
+		motivo, err := servMotivo.ListarMotivo(ctx, int(idMotivo), int32(idEmpresa))
+		require.NoError(t, err)
+		require.Equal(t, "Dano sem conserto", motivo.Motivo)
+		require.False(t, motivo.VoltaEstoque)
+		require.True(t, motivo.Descarte)
+		require.True(t, motivo.ExigeInspecao)
+	})
+
+	t.Run("sem destino marcado volta para o estoque", func(t *testing.T) {
+
+		err := servMotivo.AtualizarMotivo(ctx, int(idMotivo), model.MotivoDevolucao{Motivo: "Dano"}, int32(idEmpresa))
+		require.NoError(t, err)
+
+		motivo, err := servMotivo.ListarMotivo(ctx, int(idMotivo), int32(idEmpresa))
+		require.NoError(t, err)
+		require.True(t, motivo.VoltaEstoque)
+		require.False(t, motivo.Descarte)
+	})
+
+	t.Run("mais de um destino é rejeitado", func(t *testing.T) {
+
+		err := servMotivo.AtualizarMotivo(ctx, int(idMotivo), model.MotivoDevolucao{
+			Motivo:       "Dano",
+			VoltaEstoque: true,
+			Higienizacao: true,
+		}, int32(idEmpresa))
+		require.ErrorIs(t, err, helper.ErrDestinoMotivo)
+	})
+
+	t.Run("motivo de outra empresa não é encontrado", func(t *testing.T) {
+
+		outraEmpresa := CreateEmpresa(t, db)
+
+		err := servMotivo.AtualizarMotivo(ctx, int(idMotivo), model.MotivoDevolucao{Motivo: "Dano"}, int32(outraEmpresa))
+		require.ErrorIs(t, err, helper.ErrNaoEncontrado)
+	})
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+)
+
+type PerfilTamanhoRepository interface {
+	Definir(ctx context.Context, arg repository.DefinirPerfilTamanhoParams) (int64, error)
+	Listar(ctx context.Context, arg repository.ListarPerfilTamanhoParams) ([]repository.ListarPerfilTamanhoRow, error)
+	Remover(ctx context.Context, arg repository.RemoverPerfilTamanhoParams) (int64, error)
+	Sugeridos(ctx context.Context, arg repository.TamanhosSugeridosParams) ([]repository.TamanhosSugeridosRow, error)
+}
+
+// PerfilTamanhoService cuida do tamanho que cada funcionario usa. O perfil é aprendido
+// sozinho a cada entrega (EntregaService.RegistrarEntrega); aqui ele é consultado e editado
+type PerfilTamanhoService struct {
+	repo PerfilTamanhoRepository
+}
+
+func NewPerfilTamanhoService(r PerfilTamanhoRepository) *PerfilTamanhoService {
+	return &PerfilTamanhoService{repo: r}
+}
+
+func (p *PerfilTamanhoService) Listar(ctx context.Context, idFuncionario int, tenantId int32) ([]model.PerfilTamanhoDto, error) {
+
+	if idFuncionario <= 0 {
+		return []model.PerfilTamanhoDto{}, helper.ErrId
+	}
+
+	perfil, err := p.repo.Listar(ctx, repository.ListarPerfilTamanhoParams{
+		IDFuncionario: int32(idFuncionario),
+		TenantID:      tenantId,
+	})
+	if err != nil {
+
+		return []model.PerfilTamanhoDto{}, fmt.Errorf("erro ao listar perfil de tamanhos, %w", err)
+	}
+
+	dto := make([]model.PerfilTamanhoDto, 0, len(perfil))
+	for _, linha := range perfil {
+
+		item := model.PerfilTamanhoDto{
+			ID:           int(linha.ID),
+			Tamanho:      model.TamanhoDto{ID: int(linha.Idtamanho), Tamanho: linha.Tamanho},
+			Origem:       linha.Origem,
+			AtualizadoEm: configs.DataBr(linha.AtualizadoEm.Time),
+		}
+
+		if linha.Idepi.Valid {
+			item.Epi = &model.EpiResumoDto{ID: int(linha.Idepi.Int32), Nome: linha.EpiNome.String}
+		}
+
+		if linha.Idtipoprotecao.Valid {
+			item.TipoProtecao = &model.TipoProtecaoDto{ID: int64(linha.Idtipoprotecao.Int32), Nome: linha.ProtecaoNome.String}
+		}
+
+		dto = append(dto, item)
+	}
+
+	return dto, nil
+}
+
+// Definir grava o tamanho como manual: as próximas entregas não trocam mais ele
+func (p *PerfilTamanhoService) Definir(ctx context.Context, idFuncionario int, model model.PerfilTamanhoInserir, tenantId int32) error {
+
+	if idFuncionario <= 0 {
+		return helper.ErrId
+	}
+
+	if (model.IdEpi == nil) == (model.IdTipoProtecao == nil) {
+		return helper.ErrPerfilTamanhoAlvo
+	}
+
+	linha, err := p.repo.Definir(ctx, repository.DefinirPerfilTamanhoParams{
+		IDEpi:          intPtrParaInt4(model.IdEpi),
+		IDTipoProtecao: intPtrParaInt4(model.IdTipoProtecao),
+		IDTamanho:      int32(model.IdTamanho),
+		IDFuncionario:  int32(idFuncionario),
+		TenantID:       tenantId,
+	})
+	if err != nil {
+
+		return fmt.Errorf("erro ao salvar perfil de tamanho, %w", err)
+	}
+
+	if linha == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+// Remover apaga uma linha do perfil; o epi (ou tipo) volta a ser aprendido na próxima entrega
+func (p *PerfilTamanhoService) Remover(ctx context.Context, idFuncionario, id int, tenantId int32) error {
+
+	if idFuncionario <= 0 || id <= 0 {
+		return helper.ErrId
+	}
+
+	linha, err := p.repo.Remover(ctx, repository.RemoverPerfilTamanhoParams{
+		ID:            int32(id),
+		IDFuncionario: int32(idFuncionario),
+		TenantID:      tenantId,
+	})
+	if err != nil {
+
+		return fmt.Errorf("erro ao remover perfil de tamanho, %w", err)
+	}
+
+	if linha == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+// Sugeridos devolve o tamanho padrão de cada epi para a tela de entrega; epi sem tamanho no perfil não volta
+func (p *PerfilTamanhoService) Sugeridos(ctx context.Context, idFuncionario int, idsEpis []int, tenantId int32) ([]model.TamanhoSugeridoDto, error) {
+
+	if idFuncionario <= 0 {
+		return []model.TamanhoSugeridoDto{}, helper.ErrId
+	}
+
+	ids := make([]int32, 0, len(idsEpis))
+	for _, id := range idsEpis {
+		ids = append(ids, int32(id))
+	}
+
+	tamanhos, err := p.repo.Sugeridos(ctx, repository.TamanhosSugeridosParams{
+		IDFuncionario: int32(idFuncionario),
+		IdsEpis:       ids,
+		TenantID:      tenantId,
+	})
+	if err != nil {
+
+		return []model.TamanhoSugeridoDto{}, fmt.Errorf("erro ao buscar tamanhos sugeridos, %w", err)
+	}
+
+	dto := make([]model.TamanhoSugeridoDto, 0, len(tamanhos))
+	for _, t := range tamanhos {
+		dto = append(dto, model.TamanhoSugeridoDto{
+			IdEpi:   int(t.IDEpi),
+			Tamanho: model.TamanhoDto{ID: int(t.Idtamanho), Tamanho: t.Tamanho},
+		})
+	}
+
+	return dto, nil
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/auth"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PIN é curto, então depois de algumas tentativas erradas o acesso fica travado por um tempo
+const (
+	maxTentativasPin = 5
+	tempoBloqueioPin = 15 * time.Minute
+)
+
+type PortalFuncionarioRepository interface {
+	BuscarParaLogin(ctx context.Context, arg repository.BuscarFuncionarioParaLoginParams) (repository.BuscarFuncionarioParaLoginRow, error)
+	RegistrarFalhaPin(ctx context.Context, arg repository.RegistrarFalhaPinParams) (int32, error)
+	BloquearPin(ctx context.Context, arg repository.BloquearPinParams) error
+	ZerarTentativasPin(ctx context.Context, arg repository.ZerarTentativasPinParams) error
+	DefinirPin(ctx context.Context, arg repository.DefinirPinFuncionarioParams) (int64, error)
+	BuscarPerfil(ctx context.Context, arg repository.BuscaFuncionarioPorIdParams) (repository.BuscaFuncionarioPorIdRow, error)
+	AssinaturasPendentes(ctx context.Context, arg repository.ListarAssinaturasPendentesParams) ([]repository.ListarAssinaturasPendentesRow, error)
+	ConfirmarCiencia(ctx context.Context, arg repository.ConfirmarCienciaEntregaParams) (int64, error)
+}
+
+// PortalFuncionarioService atende o próprio funcionario: tudo aqui é filtrado pelo id que vem do token,
+// e o pedido de troca entra no mesmo fluxo de solicitação (aprovação -> atendimento -> entrega)
+type PortalFuncionarioService struct {
+	repo        PortalFuncionarioRepository
+	entrega     EntregaService
+	requisito   RequisitoFuncaoService
+	solicitacao SolicitacaoService
+	posse       PosseService
+}
+
+func NewPortalFuncionarioService(r PortalFuncionarioRepository, entrega EntregaService, requisito RequisitoFuncaoService, solicitacao SolicitacaoService, posse PosseService) *PortalFuncionarioService {
+
+	return &PortalFuncionarioService{
+		repo:        r,
+		entrega:     entrega,
+		requisito:   requisito,
+		solicitacao: solicitacao,
+		posse:       posse,
+	}
+}
+
+func (p *PortalFuncionarioService) DefinirPin(ctx context.Context, idFuncionario int, pin string, tenantId int32) error {
+
+	if idFuncionario <= 0 {
+		return helper.ErrId
+	}
+
+	hash, err := auth.HashPassword(strings.TrimSpace(pin))
+	if err != nil {
+		return err
+	}
+
+	linha, err := p.repo.DefinirPin(ctx, repository.DefinirPinFuncionarioParams{
+		ID:       int32(idFuncionario),
+		PinHash:  pgtype.Text{String: string(hash), Valid: true},
+		TenantID: tenantId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if linha == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+func (p *PortalFuncionarioService) Login(ctx context.Context, matricula, pin string, tenantId int32) (string, model.PerfilPortalDto, error) {
+
+	funcionario, err := p.repo.BuscarParaLogin(ctx, repository.BuscarFuncionarioParaLoginParams{
+		Matricula: strings.TrimSpace(matricula),
+		TenantID:  tenantId,
+	})
+	if err != nil {
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", model.PerfilPortalDto{}, helper.ErrCredenciaisPortal
+		}
+
+		return "", model.PerfilPortalDto{}, err
+	}
+
+	// funcionario sem PIN ainda não foi liberado para o portal
+	if !funcionario.PinHash.Valid {
+		return "", model.PerfilPortalDto{}, helper.ErrCredenciaisPortal
+	}
+
+	if funcionario.PinBloqueadoAte.Valid && time.Now().Before(funcionario.PinBloqueadoAte.Time) {
+		return "", model.PerfilPortalDto{}, helper.ErrPinBloqueado
+	}
+
+	if _, err := auth.HashCompare([]byte(funcionario.PinHash.String), []byte(pin)); err != nil {
+
+		tentativas, err := p.repo.RegistrarFalhaPin(ctx, repository.RegistrarFalhaPinParams{
+			ID:       funcionario.ID,
+			TenantID: tenantId,
+		})
+		if err != nil {
+			return "", model.PerfilPortalDto{}, err
+		}
+
+		if tentativas >= maxTentativasPin {
+
+			err := p.repo.BloquearPin(ctx, repository.BloquearPinParams{
+				ID:              funcionario.ID,
+				PinBloqueadoAte: pgtype.Timestamp{Time: time.Now().Add(tempoBloqueioPin), Valid: true},
+				TenantID:        tenantId,
+			})
+			if err != nil {
+				return "", model.PerfilPortalDto{}, err
+			}
+
+			return "", model.PerfilPortalDto{}, helper.ErrPinBloqueado
+		}
+
+		return "", model.PerfilPortalDto{}, helper.ErrCredenciaisPortal
+	}
+
+	if funcionario.TentativasPin > 0 || funcionario.PinBloqueadoAte.Valid {
+
+		err := p.repo.ZerarTentativasPin(ctx, repository.ZerarTentativasPinParams{
+			ID:       funcionario.ID,
+			TenantID: tenantId,
+		})
+		if err != nil {
+			return "", model.PerfilPortalDto{}, err
+		}
+	}
+
+	token, err := auth.GerarJWTFuncionario(funcionario.ID, tenantId, funcionario.PinVersao)
+	if err != nil {
+		return "", model.PerfilPortalDto{}, errors.New("erro ao gerar token de acesso")
+	}
+
+	perfil, err := p.Perfil(ctx, funcionario.ID, tenantId)
+	if err != nil {
+		return "", model.PerfilPortalDto{}, err
+	}
+
+	return token, perfil, nil
+}
+
+func (p *PortalFuncionarioService) Perfil(ctx context.Context, idFuncionario int32, tenantId int32) (model.PerfilPortalDto, error) {
+
+	funcionario, err := p.repo.BuscarPerfil(ctx, repository.BuscaFuncionarioPorIdParams{
+		ID:       idFuncionario,
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.PerfilPortalDto{}, helper.ErrNaoEncontrado
+		}
+
+		return model.PerfilPortalDto{}, err
+	}
+
+	return model.PerfilPortalDto{
+		Id:           int(funcionario.ID),
+		Nome:         funcionario.Nome,
+		Matricula:    funcionario.Matricula,
+		Funcao:       funcionario.FuncaoNome,
+		Departamento: funcionario.DepartamentoNome,
+	}, nil
+}
+
+// EpisEmPosse usa a mesma conta de posse do sistema (entregue menos devolvido e baixado);
+// as entregas vêm da mais antiga para a mais nova, então a última é a entrega mais recente que sobrou
+func (p *PortalFuncionarioService) EpisEmPosse(ctx context.Context, idFuncionario int32, tenantId int32) ([]model.EpiEmPosseDto, error) {
+
+	posse, err := p.posse.Listar(ctx, int(idFuncionario), tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := make([]model.EpiEmPosseDto, 0, len(posse))
+	for _, epi := range posse {
+
+		item := model.EpiEmPosseDto{
+			IdEpi:      epi.IdEpi,
+			Epi:        epi.Epi,
+			CA:         epi.CA,
+			IdTamanho:  epi.IdTamanho,
+			Tamanho:    epi.Tamanho,
+			Quantidade: epi.Quantidade,
+		}
+		if len(epi.Entregas) > 0 {
+			ultima := epi.Entregas[len(epi.Entregas)-1].DataEntrega
+			item.UltimaEntrega = &ultima
+		}
+
+		dto = append(dto, item)
+	}
+
+	return dto, nil
+}
+
+func (p *PortalFuncionarioService) Entregas(ctx context.Context, idFuncionario int32, pagina, quantidade int32, tenantId int32) (EntregaPaginada, error) {
+
+	return p.entrega.ListaEntregas(ctx, FiltroEntregas{
+		FuncionarioId: idFuncionario,
+		Pagina:        pagina,
+		Quantidade:    quantidade,
+	}, tenantId)
+}
+
+func (p *PortalFuncionarioService) AssinaturasPendentes(ctx context.Context, idFuncionario int32, tenantId int32) ([]model.AssinaturaPendenteDto, error) {
+
+	linhas, err := p.repo.AssinaturasPendentes(ctx, repository.ListarAssinaturasPendentesParams{
+		Idfuncionario: idFuncionario,
+		TenantID:      tenantId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// as linhas vêm ordenadas por entrega, então basta agrupar as vizinhas
+	pendentes := make([]model.AssinaturaPendenteDto, 0)
+	for _, l := range linhas {
+
+		if len(pendentes) == 0 || pendentes[len(pendentes)-1].IdEntrega != int(l.EntregaID) {
+			pendentes = append(pendentes, model.AssinaturaPendenteDto{
+				IdEntrega:   int(l.EntregaID),
+				DataEntrega: configs.DataBr(l.DataEntrega.Time),
+			})
+		}
+
+		atual := &pendentes[len(pendentes)-1]
+		atual.Itens = append(atual.Itens, model.ItemAssinaturaPendente{
+			Epi:        l.EpiNome,
+			Tamanho:    l.TamNome,
+			Quantidade: int(l.Quantidade),
+		})
+	}
+
+	return pendentes, nil
+}
+
+func (p *PortalFuncionarioService) ConfirmarCiencia(ctx context.Context, idEntrega int, idFuncionario int32, tenantId int32) error {
+
+	if idEntrega <= 0 {
+		return helper.ErrId
+	}
+
+	linha, err := p.repo.ConfirmarCiencia(ctx, repository.ConfirmarCienciaEntregaParams{
+		ID:            int32(idEntrega),
+		Idfuncionario: idFuncionario,
+		TenantID:      tenantId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if linha == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+// ProximasTrocas reaproveita a sugestão de entrega da matriz da função: mostra o que falta e quando vence cada troca
+func (p *PortalFuncionarioService) ProximasTrocas(ctx context.Context, idFuncionario int32, tenantId int32) (model.SugestaoEntregaDto, error) {
+
+	return p.requisito.SugestaoEntrega(ctx, int(idFuncionario), tenantId)
+}
+
+func (p *PortalFuncionarioService) SolicitarTroca(ctx context.Context, idFuncionario int32, input model.SolicitacaoPortalInserir, tenantId int32) (int, error) {
+
+	return p.solicitacao.SalvarDoPortal(ctx, model.SolicitacaoInserir{
+		IdFuncionario: int(idFuncionario),
+		Justificativa: input.Justificativa,
+		Itens:         input.Itens,
+	}, tenantId)
+}
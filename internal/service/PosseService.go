@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type PosseRepository interface {
+	BuscarFuncionario(ctx context.Context, arg repository.BuscaFuncionarioPorIdParams) (repository.BuscaFuncionarioPorIdRow, error)
+	ItensEntregues(ctx context.Context, arg repository.ListarItensEntreguesFuncionarioParams) ([]repository.ListarItensEntreguesFuncionarioRow, error)
+	Devolvido(ctx context.Context, arg repository.ListarDevolvidoFuncionarioParams) ([]repository.ListarDevolvidoFuncionarioRow, error)
+}
+
+type PosseService struct {
+	repo PosseRepository
+}
+
+func NewPosseService(r PosseRepository) *PosseService {
+
+	return &PosseService{repo: r}
+}
+
+// Listar traz o que o funcionario tem em mãos agora: entregue menos devolvido (e baixado), por epi/tamanho.
+// A devolução não aponta a entrega de origem, então ela abate das entregas mais antigas primeiro
+func (p *PosseService) Listar(ctx context.Context, idFuncionario int, tenantId int32) ([]model.PosseEpiDto, error) {
+
+	if idFuncionario <= 0 {
+		return []model.PosseEpiDto{}, helper.ErrId
+	}
+
+	_, err := p.repo.BuscarFuncionario(ctx, repository.BuscaFuncionarioPorIdParams{
+		ID:       int32(idFuncionario),
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []model.PosseEpiDto{}, helper.ErrNaoEncontrado
+		}
+		return []model.PosseEpiDto{}, err
+	}
+
+	itens, err := p.repo.ItensEntregues(ctx, repository.ListarItensEntreguesFuncionarioParams{
+		IDFuncionario: int32(idFuncionario),
+		TenantID:      tenantId,
+	})
+	if err != nil {
+		return []model.PosseEpiDto{}, err
+	}
+
+	devolvidos, err := p.repo.Devolvido(ctx, repository.ListarDevolvidoFuncionarioParams{
+		IDFuncionario: int32(idFuncionario),
+		TenantID:      tenantId,
+	})
+	if err != nil {
+		return []model.PosseEpiDto{}, err
+	}
+
+	return calcularPosse(itens, devolvidos), nil
+}
+
+// Pendencias é o Listar dentro da transação do desligamento, depois de o funcionario estar travado
+func (p *PosseService) Pendencias(ctx context.Context, qtx *repository.Queries, idFuncionario, tenantId int32) ([]model.PosseEpiDto, error) {
+
+	itens, err := qtx.ListarItensEntreguesFuncionario(ctx, repository.ListarItensEntreguesFuncionarioParams{
+		IDFuncionario: idFuncionario,
+		TenantID:      tenantId,
+	})
+	if err != nil {
+		return []model.PosseEpiDto{}, err
+	}
+
+	devolvidos, err := qtx.ListarDevolvidoFuncionario(ctx, repository.ListarDevolvidoFuncionarioParams{
+		IDFuncionario: idFuncionario,
+		TenantID:      tenantId,
+	})
+	if err != nil {
+		return []model.PosseEpiDto{}, err
+	}
+
+	return calcularPosse(itens, devolvidos), nil
+}
+
+// calcularPosse abate o que saiu da posse (devoluções e baixas) das entregas mais antigas primeiro
+func calcularPosse(itens []repository.ListarItensEntreguesFuncionarioRow, devolvidos []repository.ListarDevolvidoFuncionarioRow) []model.PosseEpiDto {
+
+	aAbater := make(map[[2]int32]int32, len(devolvidos))
+	for _, dv := range devolvidos {
+		aAbater[[2]int32{dv.Idepi, dv.Idtamanho}] = dv.Quantidade
+	}
+
+	posse := make([]model.PosseEpiDto, 0)
+	indice := make(map[[2]int32]int)
+
+	for _, item := range itens {
+
+		chave := [2]int32{item.EpiID, item.TamID}
+
+		restante := item.Quantidade
+		if abater := aAbater[chave]; abater > 0 {
+			usado := min(abater, restante)
+			restante -= usado
+			aAbater[chave] = abater - usado
+		}
+		if restante == 0 {
+			continue
+		}
+
+		i, existe := indice[chave]
+		if !existe {
+			posse = append(posse, model.PosseEpiDto{
+				IdEpi:     int(item.EpiID),
+				Epi:       item.EpiNome,
+				CA:        item.Ca,
+				IdTamanho: int(item.TamID),
+				Tamanho:   item.TamNome,
+				Entregas:  []model.ItemPosseDto{},
+			})
+			i = len(posse) - 1
+			indice[chave] = i
+		}
+
+		posse[i].Quantidade += int(restante)
+		posse[i].Entregas = append(posse[i].Entregas, model.ItemPosseDto{
+			IdEntrega:    int(item.Identrega),
+			DataEntrega:  configs.DataBr(item.DataEntrega.Time),
+			IdEntrada:    int(item.Identrada),
+			Lote:         item.Lote,
+			ValidadeLote: configs.DataBr(item.ValidadeLote.Time),
+			Quantidade:   int(restante),
+		})
+	}
+
+	return posse
+}
+
+// ValidarDevolucao confere, dentro da transação da devolução, se o funcionario tem em posse o que está devolvendo.
+// O funcionario fica travado até o commit para duas devoluções simultâneas não usarem o mesmo saldo
+func (p *PosseService) ValidarDevolucao(ctx context.Context, qtx *repository.Queries, idFuncionario, idEpi, idTamanho int32, quantidade int, tenantId int32) error {
+
+	err := qtx.TravarFuncionarioPosse(ctx, repository.TravarFuncionarioPosseParams{
+		ID:       idFuncionario,
+		TenantID: tenantId,
+	})
+	if err != nil {
+		return err
+	}
+
+	emPosse, err := qtx.QuantidadeEmPosse(ctx, repository.QuantidadeEmPosseParams{
+		IDFuncionario: idFuncionario,
+		TenantID:      tenantId,
+		IDEpi:         idEpi,
+		IDTamanho:     idTamanho,
+	})
+	if err != nil {
+		return err
+	}
+
+	if int(emPosse) < quantidade {
+		return fmt.Errorf("%w: em posse %d, devolvendo %d", helper.ErrDevolucaoSemPosse, emPosse, quantidade)
+	}
+
+	return nil
+}
+
+// RegistrarBaixa tira da posse um epi que o funcionario não vai devolver (extraviado, danificado sem retorno...).
+// Não volta nada para o estoque, só deixa registrado quem baixou e por quê
+func (p *PosseService) RegistrarBaixa(ctx context.Context, qtx *repository.Queries, idFuncionario int32, baixa model.BaixaPosseInserir, idUsuario int, tenantId int32) error {
+
+	err := p.ValidarDevolucao(ctx, qtx, idFuncionario, int32(baixa.IdEpi), int32(baixa.IdTamanho), baixa.Quantidade, tenantId)
+	if err != nil {
+		return err
+	}
+
+	err = qtx.AddBaixaPosse(ctx, repository.AddBaixaPosseParams{
+		TenantID:      tenantId,
+		Idfuncionario: idFuncionario,
+		Idepi:         int32(baixa.IdEpi),
+		Idtamanho:     int32(baixa.IdTamanho),
+		Quantidade:    int32(baixa.Quantidade),
+		Motivo:        strings.TrimSpace(baixa.Motivo),
+		Idusuario:     pgtype.Int4{Int32: int32(idUsuario), Valid: idUsuario > 0},
+	})
+	if err != nil {
+		return helper.TraduzErroPostgres(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type RequisitoFuncaoRepository interface {
+	Adicionar(ctx context.Context, arg repository.AddRequisitoFuncaoParams) (int32, error)
+	ListarPorFuncao(ctx context.Context, arg repository.BuscarRequisitosPorFuncaoParams) ([]repository.BuscarRequisitosPorFuncaoRow, error)
+	Atualizar(ctx context.Context, arg repository.UpdateRequisitoFuncaoParams) (int64, error)
+	Cancelar(ctx context.Context, arg repository.DeletarRequisitoFuncaoParams) (int64, error)
+	SugestaoEntrega(ctx context.Context, arg repository.BuscarSugestaoEntregaParams) ([]repository.BuscarSugestaoEntregaRow, error)
+	RelatorioConformidade(ctx context.Context, arg repository.BuscarRelatorioConformidadeParams) ([]repository.BuscarRelatorioConformidadeRow, error)
+}
+
+type RequisitoFuncaoService struct {
+	repo RequisitoFuncaoRepository
+}
+
+func NewRequisitoFuncaoService(r RequisitoFuncaoRepository) *RequisitoFuncaoService {
+	return &RequisitoFuncaoService{repo: r}
+}
+
+func (r *RequisitoFuncaoService) SalvarRequisito(ctx context.Context, idFuncao int, model model.RequisitoFuncaoInserir, tenantId int32) (int, error) {
+
+	if idFuncao <= 0 {
+		return 0, helper.ErrId
+	}
+
+	// o requisito aponta para um epi especifico ou para um tipo de proteção, nunca os dois
+	if (model.IdEpi == nil) == (model.IdTipoProtecao == nil) {
+		return 0, helper.ErrRequisitoAlvo
+	}
+
+	id, err := r.repo.Adicionar(ctx, repository.AddRequisitoFuncaoParams{
+		TenantID:          tenantId,
+		Idfuncao:          int32(idFuncao),
+		Idepi:             intPtrParaInt4(model.IdEpi),
+		Idtipoprotecao:    intPtrParaInt4(model.IdTipoProtecao),
+		Quantidade:        int32(model.Quantidade),
+		PeriodicidadeDias: intPtrParaInt4(model.PeriodicidadeDias),
+	})
+	if err != nil {
+
+		return 0, fmt.Errorf("erro ao salvar requisito da funcao, %w", err)
+	}
+
+	return int(id), nil
+}
+
+func (r *RequisitoFuncaoService) ListarRequisitos(ctx context.Context, idFuncao int, tenantId int32) ([]model.RequisitoFuncaoDto, error) {
+
+	if idFuncao <= 0 {
+		return []model.RequisitoFuncaoDto{}, helper.ErrId
+	}
+
+	requisitos, err := r.repo.ListarPorFuncao(ctx, repository.BuscarRequisitosPorFuncaoParams{
+		Idfuncao: int32(idFuncao),
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		return []model.RequisitoFuncaoDto{}, fmt.Errorf("erro ao listar requisitos da funcao, %w", err)
+	}
+
+	dto := make([]model.RequisitoFuncaoDto, 0, len(requisitos))
+	for _, req := range requisitos {
+
+		item := model.RequisitoFuncaoDto{
+			ID:                int(req.ID),
+			IdFuncao:          int(req.Idfuncao),
+			Quantidade:        int(req.Quantidade),
+			PeriodicidadeDias: int4ParaIntPtr(req.PeriodicidadeDias),
+		}
+
+		if req.Idepi.Valid {
+			item.Epi = &model.EpiResumoDto{
+				ID:   int(req.Idepi.Int32),
+				Nome: req.EpiNome.String,
+				CA:   req.EpiCa.String,
+			}
+		}
+
+		if req.Idtipoprotecao.Valid {
+			item.TipoProtecao = &model.TipoProtecaoDto{
+				ID:   int64(req.Idtipoprotecao.Int32),
+				Nome: req.ProtecaoNome.String,
+			}
+		}
+
+		dto = append(dto, item)
+	}
+
+	return dto, nil
+}
+
+func (r *RequisitoFuncaoService) AtualizarRequisito(ctx context.Context, id int, model model.RequisitoFuncaoAtualizar, tenantId int32) error {
+
+	if id <= 0 {
+		return helper.ErrId
+	}
+
+	linha, err := r.repo.Atualizar(ctx, repository.UpdateRequisitoFuncaoParams{
+		ID:                int32(id),
+		Quantidade:        int32(model.Quantidade),
+		PeriodicidadeDias: intPtrParaInt4(model.PeriodicidadeDias),
+		TenantID:          tenantId,
+	})
+	if err != nil {
+
+		return fmt.Errorf("erro tecnico ao atualizar requisito: %w", err)
+	}
+
+	if linha == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+func (r *RequisitoFuncaoService) DeletarRequisito(ctx context.Context, id int, tenantId int32) error {
+
+	if id <= 0 {
+		return helper.ErrId
+	}
+
+	linha, err := r.repo.Cancelar(ctx, repository.DeletarRequisitoFuncaoParams{
+		ID:       int32(id),
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		return fmt.Errorf("erro ao deletar requisito, %w", err)
+	}
+
+	if linha == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+// SugestaoEntrega compara a matriz da função do funcionario com o que ele tem em posse
+// e devolve o que falta entregar (ou trocar, quando o intervalo do requisito venceu)
+func (r *RequisitoFuncaoService) SugestaoEntrega(ctx context.Context, idFuncionario int, tenantId int32) (model.SugestaoEntregaDto, error) {
+
+	if idFuncionario <= 0 {
+		return model.SugestaoEntregaDto{}, helper.ErrId
+	}
+
+	linhas, err := r.repo.SugestaoEntrega(ctx, repository.BuscarSugestaoEntregaParams{
+		IDFuncionario: int32(idFuncionario),
+		TenantID:      tenantId,
+	})
+	if err != nil {
+
+		return model.SugestaoEntregaDto{}, fmt.Errorf("erro ao montar sugestao de entrega, %w", err)
+	}
+
+	sugestao := model.SugestaoEntregaDto{
+		IdFuncionario: idFuncionario,
+		Itens:         make([]model.ItemSugestaoEntrega, 0, len(linhas)),
+	}
+
+	for _, l := range linhas {
+
+		emPosse := int(l.QuantidadeEmPosse)
+
+		item := model.ItemSugestaoEntrega{
+			IdRequisito:        int(l.ID),
+			QuantidadeExigida:  int(l.Quantidade),
+			QuantidadeEmPosse:  emPosse,
+			QuantidadeFaltante: max(int(l.Quantidade)-emPosse, 0),
+		}
+
+		if l.Idepi.Valid {
+			item.Epi = &model.EpiResumoDto{ID: int(l.Idepi.Int32), Nome: l.EpiNome.String}
+		}
+
+		if l.Idtipoprotecao.Valid {
+			item.TipoProtecao = &model.TipoProtecaoDto{ID: int64(l.Idtipoprotecao.Int32), Nome: l.ProtecaoNome.String}
+		}
+
+		if l.UltimaEntrega.Valid {
+			item.UltimaEntrega = configs.NewDataBrPtr(l.UltimaEntrega.Time)
+
+			if l.PeriodicidadeDias.Valid {
+				item.ProximaTroca = configs.NewDataBrPtr(l.UltimaEntrega.Time.AddDate(0, 0, int(l.PeriodicidadeDias.Int32)))
+			}
+		}
+
+		sugestao.Itens = append(sugestao.Itens, item)
+	}
+
+	return sugestao, nil
+}
+
+// RelatorioConformidade cruza cada funcionario ativo com a matriz da sua função e aponta
+// o que está faltando ou vencido, agrupando por departamento e, em separado, por categoria da NR-6.
+// Funcionarios cuja função não tem requisitos cadastrados não entram na conta.
+func (r *RequisitoFuncaoService) RelatorioConformidade(ctx context.Context, idDepartamento int, tenantId int32) (model.RelatorioConformidadeDto, error) {
+
+	linhas, err := r.repo.RelatorioConformidade(ctx, repository.BuscarRelatorioConformidadeParams{
+		TenantID:       tenantId,
+		IDDepartamento: pgtype.Int4{Int32: int32(idDepartamento), Valid: idDepartamento > 0},
+	})
+	if err != nil {
+
+		return model.RelatorioConformidadeDto{}, fmt.Errorf("erro ao gerar relatorio de conformidade, %w", err)
+	}
+
+	hoje := time.Now().Truncate(24 * time.Hour)
+	relatorio := model.RelatorioConformidadeDto{Departamentos: []model.ConformidadeDepartamentoDto{}}
+
+	// as linhas vem ordenadas por departamento e funcionario, então basta acompanhar a quebra
+	var dep *model.ConformidadeDepartamentoDto
+	var funcionario *model.ConformidadeFuncionarioDto
+
+	// chave 0 junta os requisitos cujo tipo de proteção ainda não tem categoria
+	categorias := make(map[int32]*model.ConformidadeCategoriaDto)
+
+	for _, l := range linhas {
+
+		if dep == nil || dep.Departamento.ID != int(l.DepartamentoID) {
+			relatorio.Departamentos = append(relatorio.Departamentos, model.ConformidadeDepartamentoDto{
+				Departamento: model.DepartamentoDto{ID: int(l.DepartamentoID), Departamento: l.DepartamentoNome},
+				Funcionarios: []model.ConformidadeFuncionarioDto{},
+			})
+			dep = &relatorio.Departamentos[len(relatorio.Departamentos)-1]
+			funcionario = nil
+		}
+
+		if funcionario == nil || funcionario.ID != int(l.FuncionarioID) {
+			dep.Funcionarios = append(dep.Funcionarios, model.ConformidadeFuncionarioDto{
+				ID:         int(l.FuncionarioID),
+				Nome:       l.FuncionarioNome,
+				Matricula:  l.Matricula,
+				Funcao:     l.FuncaoNome,
+				Conforme:   true,
+				Pendencias: []model.PendenciaEpiDto{},
+			})
+			funcionario = &dep.Funcionarios[len(dep.Funcionarios)-1]
+		}
+
+		categoria := categorias[l.CategoriaID.Int32]
+		if categoria == nil {
+			categoria = &model.ConformidadeCategoriaDto{
+				Categoria: categoriaProtecaoDto(l.CategoriaID, l.CategoriaCodigo, l.CategoriaNome),
+			}
+			categorias[l.CategoriaID.Int32] = categoria
+		}
+		categoria.TotalRequisitos++
+
+		emPosse := int(l.QuantidadeEmPosse)
+		if emPosse >= int(l.Quantidade) {
+			categoria.RequisitosAtendidos++
+			continue
+		}
+
+		pendencia := model.PendenciaEpiDto{
+			IdRequisito:       int(l.RequisitoID),
+			QuantidadeExigida: int(l.Quantidade),
+			QuantidadeEmPosse: emPosse,
+			Situacao:          model.SituacaoFaltante,
+			Categoria:         categoria.Categoria,
+		}
+
+		if l.Idepi.Valid {
+			pendencia.Epi = &model.EpiResumoDto{ID: int(l.Idepi.Int32), Nome: l.EpiNome.String}
+		}
+
+		if l.Idtipoprotecao.Valid {
+			pendencia.TipoProtecao = &model.TipoProtecaoDto{ID: int64(l.Idtipoprotecao.Int32), Nome: l.ProtecaoNome.String}
+		}
+
+		if l.UltimaEntrega.Valid {
+			pendencia.UltimaEntrega = configs.NewDataBrPtr(l.UltimaEntrega.Time)
+
+			// já recebeu, mas o intervalo de troca passou
+			if l.PeriodicidadeDias.Valid && !l.UltimaEntrega.Time.AddDate(0, 0, int(l.PeriodicidadeDias.Int32)).After(hoje) {
+				pendencia.Situacao = model.SituacaoVencido
+			}
+		}
+
+		if pendencia.Situacao == model.SituacaoVencido {
+			categoria.Vencidos++
+		} else {
+			categoria.Faltantes++
+		}
+
+		funcionario.Conforme = false
+		funcionario.Pendencias = append(funcionario.Pendencias, pendencia)
+	}
+
+	for i := range relatorio.Departamentos {
+
+		d := &relatorio.Departamentos[i]
+		d.TotalFuncionarios = len(d.Funcionarios)
+		for _, f := range d.Funcionarios {
+			if f.Conforme {
+				d.FuncionariosConformes++
+			}
+		}
+		d.PercentualConformidade = percentual(d.FuncionariosConformes, d.TotalFuncionarios)
+
+		relatorio.TotalFuncionarios += d.TotalFuncionarios
+		relatorio.FuncionariosConformes += d.FuncionariosConformes
+	}
+
+	relatorio.PercentualConformidade = percentual(relatorio.FuncionariosConformes, relatorio.TotalFuncionarios)
+
+	relatorio.Categorias = make([]model.ConformidadeCategoriaDto, 0, len(categorias))
+	for _, c := range categorias {
+		c.PercentualConformidade = percentual(c.RequisitosAtendidos, c.TotalRequisitos)
+		relatorio.Categorias = append(relatorio.Categorias, *c)
+	}
+	sort.Slice(relatorio.Categorias, func(i, j int) bool {
+		return categoriaAntes(relatorio.Categorias[i].Categoria, relatorio.Categorias[j].Categoria)
+	})
+
+	return relatorio, nil
+}
+
+func percentual(parte, total int) float64 {
+
+	if total == 0 {
+		return 100
+	}
+
+	return math.Round(float64(parte)/float64(total)*10000) / 100
+}
+
+func intPtrParaInt4(v *int) pgtype.Int4 {
+
+	if v == nil {
+		return pgtype.Int4{}
+	}
+
+	return pgtype.Int4{Int32: int32(*v), Valid: true}
+}
+
+func int4ParaIntPtr(v pgtype.Int4) *int {
+
+	if !v.Valid {
+		return nil
+	}
+
+	i := int(v.Int32)
+	return &i
+}
+
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSugestaoEntrega(t *testing.T) {
+
+	db := SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	servEntrega := NewEntregaService(repository.NewEntregaRepository(db), db)
+	servDevolucao := NewDevolucaoService(repository.NewDevolucaoRepository(db), db, *servEntrega, *NewPosseService(repository.NewPosseRepository(db)))
+	servRequisito := NewRequisitoFuncaoService(repository.NewRequisitoFuncaoRepository(db))
+
+	idEmpresa := CreateEmpresa(t, db)
+	iduser := CreateUser(t, db, idEmpresa)
+	iddep := CreateDepartamento(t, db, idEmpresa)
+	IdFuncao := CreateFuncao(t, db, iddep, idEmpresa)
+	idtam := CreateTamanho(t, db, idEmpresa)
+	idprotec := CreateProtecao(t, db, idEmpresa)
+	idepi := CreateEpi(t, db, idprotec, idEmpresa)
+	idfuncionario := CreateFuncionario(t, db, iddep, IdFuncao, idEmpresa)
+
+	Idfornecedor := CreateFornecedor(t, db, idEmpresa)
+	idEntrada := CreateEntradaEpi(t, db, idfuncionario, idepi, idprotec, idtam, Idfornecedor, iduser, idEmpresa)
+
+	// a função exige 10 unidades do epi, trocadas a cada 60 dias
+	_, err := db.Exec(ctx, `
+		INSERT INTO requisito_funcao (tenant_id, IdFuncao, IdEpi, quantidade, periodicidade_dias)
+		VALUES ($1, $2, $3, 10, 60)`, idEmpresa, IdFuncao, idepi)
+	require.NoError(t, err)
+
+	// as 10 unidades foram entregues há 90 dias, fora do intervalo de troca
+	idEntrega := CreateEntregaEpi(t, db, idfuncionario, iduser, idEmpresa)
+	_ = CreateEpiEntregues(t, db, idEntrega, idEntrada, idepi, idtam, idEmpresa)
+	_, err = db.Exec(ctx, "UPDATE entrega_epi SET data_entrega = CURRENT_DATE - 90 WHERE id = $1", idEntrega)
+	require.NoError(t, err)
+
+	idMotivo := CreateMotivoDevolucao(t, db, "Desgaste Natural", idEmpresa)
+
+	sugestao := func() model.ItemSugestaoEntrega {
+
+		dto, err := servRequisito.SugestaoEntrega(ctx, int(idfuncionario), int32(idEmpresa))
+		require.NoError(t, err)
+		require.Len(t, dto.Itens, 1)
+		return dto.Itens[0]
+	}
+
+	t.Run("entrega vencida não conta como posse", func(t *testing.T) {
+
+		item := sugestao()
+		require.Equal(t, 0, item.QuantidadeEmPosse)
+		require.Equal(t, 10, item.QuantidadeFaltante)
+	})
+
+	t.Run("depois da troca o epi novo conta e a devolução abate a entrega antiga", func(t *testing.T) {
+
+		idEpi := int(idepi)
+		idTam := int(idtam)
+		quantidade := 10
+
+		err := servDevolucao.SalvarDevolucao(ctx, model.DevolucaoInserir{
+			Troca:               true,
+			IdEpiNovo:           &idEpi,
+			IdTamanhoNovo:       &idTam,
+			NovaQuantidade:      &quantidade,
+			IdFuncionario:       int(idfuncionario),
+			IdEpi:               idEpi,
+			IdMotivo:            int(idMotivo),
+			DataDevolucao:       *configs.NewDataBrPtr(time.Now()),
+			IdTamanho:           idTam,
+			QuantidadeADevolver: 10,
+			AssinaturaDigital:   "assinatura_base64_teste",
+			IdUser:              int(iduser),
+		}, int32(idEmpresa))
+		require.NoError(t, err)
+
+		// a devolução de hoje sai das unidades de 90 dias atrás, não das que acabaram de ser entregues
+		item := sugestao()
+		require.Equal(t, 10, item.QuantidadeEmPosse)
+		require.Equal(t, 0, item.QuantidadeFaltante)
+	})
+}
+
+func TestRelatorioConformidade(t *testing.T) {
+
+	db := SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	servEntrega := NewEntregaService(repository.NewEntregaRepository(db), db)
+	servDevolucao := NewDevolucaoService(repository.NewDevolucaoRepository(db), db, *servEntrega, *NewPosseService(repository.NewPosseRepository(db)))
+	servRequisito := NewRequisitoFuncaoService(repository.NewRequisitoFuncaoRepository(db))
+
+	idEmpresa := CreateEmpresa(t, db)
+	iduser := CreateUser(t, db, idEmpresa)
+	iddep := CreateDepartamento(t, db, idEmpresa)
+	IdFuncao := CreateFuncao(t, db, iddep, idEmpresa)
+	idtam := CreateTamanho(t, db, idEmpresa)
+	idprotec := CreateProtecao(t, db, idEmpresa)
+	idepi := CreateEpi(t, db, idprotec, idEmpresa)
+	idfuncionario := CreateFuncionario(t, db, iddep, IdFuncao, idEmpresa)
+
+	Idfornecedor := CreateFornecedor(t, db, idEmpresa)
+	idEntrada := CreateEntradaEpi(t, db, idfuncionario, idepi, idprotec, idtam, Idfornecedor, iduser, idEmpresa)
+
+	_, err := db.Exec(ctx, `
+		INSERT INTO requisito_funcao (tenant_id, IdFuncao, IdEpi, quantidade, periodicidade_dias)
+		VALUES ($1, $2, $3, 10, 60)`, idEmpresa, IdFuncao, idepi)
+	require.NoError(t, err)
+
+	idEntrega := CreateEntregaEpi(t, db, idfuncionario, iduser, idEmpresa)
+	_ = CreateEpiEntregues(t, db, idEntrega, idEntrada, idepi, idtam, idEmpresa)
+	_, err = db.Exec(ctx, "UPDATE entrega_epi SET data_entrega = CURRENT_DATE - 90 WHERE id = $1", idEntrega)
+	require.NoError(t, err)
+
+	idMotivo := CreateMotivoDevolucao(t, db, "Desgaste Natural", idEmpresa)
+
+	funcionario := func() model.ConformidadeFuncionarioDto {
+
+		relatorio, err := servRequisito.RelatorioConformidade(ctx, int(iddep), int32(idEmpresa))
+		require.NoError(t, err)
+		require.Len(t, relatorio.Departamentos, 1)
+		require.Len(t, relatorio.Departamentos[0].Funcionarios, 1)
+		return relatorio.Departamentos[0].Funcionarios[0]
+	}
+
+	t.Run("entrega fora do intervalo de troca aparece como vencida", func(t *testing.T) {
+
+		f := funcionario()
+		require.False(t, f.Conforme)
+		require.Len(t, f.Pendencias, 1)
+		require.Equal(t, model.SituacaoVencido, f.Pendencias[0].Situacao)
+	})
+
+	t.Run("depois da troca o funcionario fica conforme", func(t *testing.T) {
+
+		idEpi := int(idepi)
+		idTam := int(idtam)
+		quantidade := 10
+
+		err := servDevolucao.SalvarDevolucao(ctx, model.DevolucaoInserir{
+			Troca:               true,
+			IdEpiNovo:           &idEpi,
+			IdTamanhoNovo:       &idTam,
+			NovaQuantidade:      &quantidade,
+			IdFuncionario:       int(idfuncionario),
+			IdEpi:               idEpi,
+			IdMotivo:            int(idMotivo),
+			DataDevolucao:       *configs.NewDataBrPtr(time.Now()),
+			IdTamanho:           idTam,
+			QuantidadeADevolver: 10,
+			AssinaturaDigital:   "assinatura_base64_teste",
+			IdUser:              int(iduser),
+		}, int32(idEmpresa))
+		require.NoError(t, err)
+
+		f := funcionario()
+		require.True(t, f.Conforme, "a troca não deveria deixar o funcionario como faltante")
+		require.Empty(t, f.Pendencias)
+	})
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReservaRepository interface {
+	Adicionar(ctx context.Context, qtx *repository.Queries, arg repository.AddReservaParams) (int32, error)
+	Cancelar(ctx context.Context, arg repository.CancelarReservaParams) (int64, error)
+	Listar(ctx context.Context, arg repository.ListarReservasParams) ([]repository.ListarReservasRow, error)
+	Saldo(ctx context.Context, arg repository.SaldoDisponivelParams) (repository.SaldoDisponivelRow, error)
+	QuantidadeReservadaSemLote(ctx context.Context, qtx *repository.Queries, arg repository.QuantidadeReservadaSemLoteParams) (int32, error)
+	ListarDaSolicitacao(ctx context.Context, qtx *repository.Queries, arg repository.ListarReservasSolicitacaoParams) ([]repository.ListarReservasSolicitacaoRow, error)
+}
+
+type ReservaService struct {
+	repo    ReservaRepository
+	db      *pgxpool.Pool
+	queries *repository.Queries
+}
+
+func NewReservaService(r ReservaRepository, db *pgxpool.Pool) *ReservaService {
+
+	return &ReservaService{
+		repo:    r,
+		db:      db,
+		queries: repository.New(db),
+	}
+}
+
+func (r *ReservaService) Salvar(ctx context.Context, model model.ReservaInserir, idUsuario int, tenantId int32) (int, error) {
+
+	hoje := time.Now().Truncate(24 * time.Hour)
+	if model.ExpiraEm.Time().Before(hoje) {
+		return 0, helper.ErrReservaExpirada
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.queries.WithTx(tx)
+
+	id, err := r.RegistrarReserva(ctx, qtx, repository.AddReservaParams{
+		TenantID:   tenantId,
+		Idepi:      int32(model.IdEpi),
+		Idtamanho:  int32(model.IdTamanho),
+		Identrada:  intPtrParaInt4(model.IdEntrada),
+		Quantidade: int32(model.Quantidade),
+		// a reserva vale o dia inteiro informado
+		ExpiraEm:   pgtype.Timestamp{Time: model.ExpiraEm.Time().AddDate(0, 0, 1), Valid: true},
+		Observacao: pgtype.Text{String: strings.TrimSpace(model.Observacao), Valid: strings.TrimSpace(model.Observacao) != ""},
+		Idusuario:  pgtype.Int4{Int32: int32(idUsuario), Valid: idUsuario > 0},
+	}, tenantId)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// RegistrarReserva confere o saldo livre (travando os lotes) e grava a reserva na transação recebida
+func (r *ReservaService) RegistrarReserva(ctx context.Context, qtx *repository.Queries, args repository.AddReservaParams, tenantId int32) (int32, error) {
+
+	lotes, err := qtx.ListarLotesParaConsumo(ctx, repository.ListarLotesParaConsumoParams{
+		TenantID:  tenantId,
+		Idepi:     args.Idepi,
+		Idtamanho: args.Idtamanho,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	reservadoSemLote, err := r.repo.QuantidadeReservadaSemLote(ctx, qtx, repository.QuantidadeReservadaSemLoteParams{
+		TenantID:  tenantId,
+		Idepi:     args.Idepi,
+		Idtamanho: args.Idtamanho,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	disponivel := -reservadoSemLote
+	var livreNoLote int32
+	for _, lote := range lotes {
+
+		disponivel += max(lote.QuantidadeLivre, 0)
+		if args.Identrada.Valid && lote.ID == args.Identrada.Int32 {
+			livreNoLote = max(lote.QuantidadeLivre, 0)
+		}
+	}
+
+	if args.Identrada.Valid && livreNoLote < args.Quantidade {
+		return 0, fmt.Errorf("%w: lote %d (disponivel %d, solicitado %d)",
+			helper.ErrEstoqueInsuficiente, args.Identrada.Int32, livreNoLote, args.Quantidade)
+	}
+
+	if disponivel < args.Quantidade {
+		return 0, fmt.Errorf("%w: epi %d tamanho %d (disponivel %d, solicitado %d)",
+			helper.ErrEstoqueInsuficiente, args.Idepi, args.Idtamanho, max(disponivel, 0), args.Quantidade)
+	}
+
+	id, err := r.repo.Adicionar(ctx, qtx, args)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao salvar reserva, %w", err)
+	}
+
+	return id, nil
+}
+
+// ReservasDaSolicitacao devolve as reservas ainda abertas de uma solicitação, por epi/tamanho
+func (r *ReservaService) ReservasDaSolicitacao(ctx context.Context, qtx *repository.Queries, idSolicitacao int32, tenantId int32) (map[[2]int32]int, error) {
+
+	reservas, err := r.repo.ListarDaSolicitacao(ctx, qtx, repository.ListarReservasSolicitacaoParams{
+		Idsolicitacao: pgtype.Int4{Int32: idSolicitacao, Valid: true},
+		TenantID:      tenantId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	porItem := make(map[[2]int32]int, len(reservas))
+	for _, reserva := range reservas {
+		porItem[[2]int32{reserva.Idepi, reserva.Idtamanho}] = int(reserva.ID)
+	}
+
+	return porItem, nil
+}
+
+func (r *ReservaService) Cancelar(ctx context.Context, id, idUsuario int, tenantId int32) error {
+
+	if id <= 0 {
+		return helper.ErrId
+	}
+
+	linha, err := r.repo.Cancelar(ctx, repository.CancelarReservaParams{
+		ID:                    int32(id),
+		IDUsuarioCancelamento: pgtype.Int4{Int32: int32(idUsuario), Valid: idUsuario > 0},
+		TenantID:              tenantId,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao cancelar reserva, %w", err)
+	}
+
+	if linha == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+func (r *ReservaService) Saldo(ctx context.Context, idEpi, idTamanho int, tenantId int32) (model.SaldoEstoqueDto, error) {
+
+	if idEpi <= 0 || idTamanho <= 0 {
+		return model.SaldoEstoqueDto{}, helper.ErrId
+	}
+
+	saldo, err := r.repo.Saldo(ctx, repository.SaldoDisponivelParams{
+		TenantID:  tenantId,
+		Idepi:     int32(idEpi),
+		Idtamanho: int32(idTamanho),
+	})
+	if err != nil {
+		return model.SaldoEstoqueDto{}, err
+	}
+
+	return model.SaldoEstoqueDto{
+		IdEpi:                idEpi,
+		IdTamanho:            idTamanho,
+		QuantidadeAtual:      int(saldo.QuantidadeAtual),
+		QuantidadeReservada:  int(saldo.QuantidadeReservada),
+		QuantidadeDisponivel: max(int(saldo.QuantidadeAtual-saldo.QuantidadeReservada), 0),
+	}, nil
+}
+
+type FiltroReserva struct {
+	EpiID         int32 `form:"epi_id"`
+	TamanhoID     int32 `form:"tamanho_id"`
+	SolicitacaoID int32 `form:"solicitacao_id"`
+	Pagina        int32 `form:"pagina"`
+	Quantidade    int32 `form:"quantidade"`
+}
+
+type ReservaPaginada struct {
+	Reservas    []model.ReservaDto `json:"reservas"`
+	Total       int64              `json:"total"`
+	Pagina      int32              `json:"pagina"`
+	PaginaFinal int32              `json:"pagina_final"`
+}
+
+func (r *ReservaService) Listar(ctx context.Context, f FiltroReserva, tenantId int32) (ReservaPaginada, error) {
+
+	limit := f.Quantidade
+	if limit <= 0 {
+		limit = 1
+	}
+	paginaAtual := f.Pagina
+	if paginaAtual <= 0 {
+		paginaAtual = 1
+	}
+
+	offset := max((paginaAtual-1)*limit, 0)
+
+	reservas, err := r.repo.Listar(ctx, repository.ListarReservasParams{
+		TenantID:      tenantId,
+		IDEpi:         pgtype.Int4{Int32: f.EpiID, Valid: f.EpiID > 0},
+		IDTamanho:     pgtype.Int4{Int32: f.TamanhoID, Valid: f.TamanhoID > 0},
+		IDSolicitacao: pgtype.Int4{Int32: f.SolicitacaoID, Valid: f.SolicitacaoID > 0},
+		Limit:         limit,
+		Offset:        offset,
+	})
+	if err != nil {
+		return ReservaPaginada{}, err
+	}
+
+	dto := make([]model.ReservaDto, 0, len(reservas))
+	for _, reserva := range reservas {
+
+		dto = append(dto, model.ReservaDto{
+			Id:            int(reserva.ID),
+			IdEpi:         int(reserva.Idepi),
+			Epi:           reserva.EpiNome,
+			IdTamanho:     int(reserva.Idtamanho),
+			Tamanho:       reserva.TamanhoNome,
+			IdEntrada:     int4ParaIntPtr(reserva.Identrada),
+			Quantidade:    int(reserva.Quantidade),
+			ExpiraEm:      configs.DataBr(reserva.ExpiraEm.Time),
+			IdSolicitacao: int4ParaIntPtr(reserva.Idsolicitacao),
+			Observacao:    reserva.Observacao.String,
+			CriadaEm:      configs.DataBr(reserva.CriadaEm.Time),
+		})
+	}
+
+	var total int64
+	if len(reservas) > 0 {
+		total = reservas[0].TotalGeral
+	}
+
+	return ReservaPaginada{
+		Reservas:    dto,
+		Total:       total,
+		Pagina:      paginaAtual,
+		PaginaFinal: int32(math.Ceil(float64(total) / float64(limit))),
+	}, nil
+}
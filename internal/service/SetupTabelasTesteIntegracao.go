@@ -302,8 +302,404 @@ func criarTabelasPostgres(t *testing.T, pool *pgxpool.Pool) {
 	ADD CONSTRAINT fk_entrada_fornecedor 
 	FOREIGN KEY (Idfornecedor) REFERENCES fornecedores(id);
 
-	
-	`
+	-- reservas: a baixa de estoque da entrega desconta o que está preso
+	CREATE TABLE reserva_estoque (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdEpi INT NOT NULL,
+		IdTamanho INT NOT NULL,
+		IdEntrada INT NULL,
+		quantidade INT NOT NULL,
+		expira_em TIMESTAMP NOT NULL,
+		IdSolicitacao INT NULL,
+		observacao VARCHAR(255) NULL,
+		IdUsuario INT NULL,
+		criada_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		IdEntrega INT NULL,
+		consumida_em TIMESTAMP NULL,
+		cancelada_em TIMESTAMP NULL,
+		id_usuario_cancelamento INT NULL,
+		FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+		FOREIGN KEY (IdEpi) REFERENCES epi(id),
+		FOREIGN KEY (IdTamanho) REFERENCES tamanho(id),
+		FOREIGN KEY (IdEntrada) REFERENCES entrada_epi(id),
+		FOREIGN KEY (IdEntrega) REFERENCES entrega_epi(id),
+		CONSTRAINT chk_reserva_quantidade CHECK (quantidade > 0)
+	);
+
+	-- destino do epi devolvido configurado no motivo
+	ALTER TABLE motivo_devolucao ADD COLUMN volta_estoque BOOLEAN NOT NULL DEFAULT TRUE;
+	ALTER TABLE motivo_devolucao ADD COLUMN descarte BOOLEAN NOT NULL DEFAULT FALSE;
+	ALTER TABLE motivo_devolucao ADD COLUMN higienizacao BOOLEAN NOT NULL DEFAULT FALSE;
+	ALTER TABLE motivo_devolucao ADD COLUMN exige_inspecao BOOLEAN NOT NULL DEFAULT FALSE;
+
+	-- documento de devolução com vários itens
+	CREATE TABLE devolucao_documento (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdFuncionario INT NOT NULL,
+		data_devolucao DATE NOT NULL,
+		assinatura_digital TEXT NOT NULL,
+		token_validacao TEXT NULL,
+		IdEntregaTroca INT NULL,
+		IdUsuario INT NULL,
+		criado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		cancelada_em TIMESTAMP NULL,
+		id_usuario_cancelamento INT NULL,
+		FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+		FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id),
+		FOREIGN KEY (IdEntregaTroca) REFERENCES entrega_epi(id)
+	);
+	ALTER TABLE devolucao ADD COLUMN IdDocumento INT NULL REFERENCES devolucao_documento(id);
+	ALTER TABLE devolucao ADD COLUMN destino VARCHAR(20) NULL;
+	ALTER TABLE devolucao ADD COLUMN IdEntradaEstoque INT NULL REFERENCES entrada_epi(id);
+
+	-- pool de higienização/manutenção
+	CREATE TABLE ordem_manutencao (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdEpi INT NOT NULL,
+		IdTamanho INT NOT NULL,
+		quantidade INT NOT NULL,
+		IdDevolucao INT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'aberta',
+		servico_realizado VARCHAR(255) NULL,
+		observacao VARCHAR(255) NULL,
+		aberta_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		concluida_em TIMESTAMP NULL,
+		IdUsuarioConclusao INT NULL,
+		FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+		FOREIGN KEY (IdDevolucao) REFERENCES devolucao(id)
+	);
+
+	-- busca de funcionario sem acento
+	CREATE EXTENSION IF NOT EXISTS unaccent;
+
+	-- admissão/desligamento
+	ALTER TABLE funcionario ADD COLUMN data_admissao DATE NULL DEFAULT CURRENT_DATE;
+	ALTER TABLE funcionario ADD COLUMN data_desligamento DATE NULL;
+	ALTER TABLE funcionario ADD COLUMN motivo_desligamento VARCHAR(255) NULL;
+	ALTER TABLE funcionario ADD COLUMN IdUsuarioDesligamento INT NULL;
+
+	CREATE TABLE baixa_posse (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdFuncionario INT NOT NULL,
+		IdEpi INT NOT NULL,
+		IdTamanho INT NOT NULL,
+		quantidade INT NOT NULL CHECK (quantidade > 0),
+		motivo VARCHAR(255) NOT NULL,
+		data_baixa TIMESTAMP NOT NULL DEFAULT NOW(),
+		IdUsuario INT NULL,
+		FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+		FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id)
+	);
+
+	-- histórico de cargo (entregas antigas aparecem no cargo da época)
+	CREATE TABLE funcionario_cargo_historico (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdFuncionario INT NOT NULL,
+		IdDepartamento INT NOT NULL,
+		IdFuncao INT NOT NULL,
+		data_inicio DATE NOT NULL,
+		data_fim DATE NULL,
+		criado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id)
+	);
+
+	-- dados pessoais do funcionario (CPF único por empresa)
+	ALTER TABLE funcionario ADD COLUMN cpf VARCHAR(11) NULL;
+	ALTER TABLE funcionario ADD COLUMN data_nascimento DATE NULL;
+	ALTER TABLE funcionario ADD COLUMN email VARCHAR(150) NULL;
+	ALTER TABLE funcionario ADD COLUMN telefone VARCHAR(20) NULL;
+	CREATE UNIQUE INDEX uq_funcionario_cpf_tenant ON funcionario (tenant_id, cpf) WHERE cpf IS NOT NULL;
+
+	CREATE TABLE acesso_dados_pessoais (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdFuncionario INT NOT NULL,
+		IdUsuario INT NOT NULL,
+		acessado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id)
+	);
+
+	-- dados da função para o S-2240 do eSocial
+	CREATE TABLE funcao_esocial (
+		IdFuncao INT PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		cod_agente_nocivo VARCHAR(10) NOT NULL,
+		descricao_atividade VARCHAR(999) NOT NULL,
+		atualizado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		FOREIGN KEY (IdFuncao) REFERENCES funcao(id)
+	);
+
+	-- treinamentos de uso de epi (NR-6) e a regra da empresa na entrega
+	CREATE TABLE treinamento (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		titulo VARCHAR(150) NOT NULL,
+		IdEpi INT NULL,
+		IdTipoProtecao INT NULL,
+		data_realizacao DATE NOT NULL,
+		instrutor VARCHAR(150) NOT NULL,
+		data_validade DATE NULL,
+		ativo BOOLEAN NOT NULL DEFAULT TRUE,
+		criado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		deletado_em TIMESTAMP NULL
+	);
+
+	CREATE TABLE treinamento_participante (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdTreinamento INT NOT NULL,
+		IdFuncionario INT NOT NULL,
+		FOREIGN KEY (IdTreinamento) REFERENCES treinamento(id),
+		FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id),
+		UNIQUE (IdTreinamento, IdFuncionario)
+	);
+
+	ALTER TABLE empresas ADD COLUMN regra_treinamento_entrega VARCHAR(10) NOT NULL DEFAULT 'desligada';
+	ALTER TABLE entrega_epi ADD COLUMN pendencia_treinamento BOOLEAN NOT NULL DEFAULT FALSE;
+
+	CREATE TABLE perfil_tamanho (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdFuncionario INT NOT NULL,
+		IdEpi INT NULL,
+		IdTipoProtecao INT NULL,
+		IdTamanho INT NOT NULL,
+		origem VARCHAR(10) NOT NULL DEFAULT 'entrega',
+		atualizado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+		FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id),
+		FOREIGN KEY (IdEpi) REFERENCES epi(id),
+		FOREIGN KEY (IdTipoProtecao) REFERENCES tipo_protecao(id),
+		FOREIGN KEY (IdTamanho) REFERENCES tamanho(id),
+		CONSTRAINT chk_perfil_tamanho_alvo CHECK ((IdEpi IS NULL) <> (IdTipoProtecao IS NULL)),
+		CONSTRAINT chk_perfil_tamanho_origem CHECK (origem IN ('entrega', 'manual')),
+		CONSTRAINT uq_perfil_tamanho UNIQUE NULLS NOT DISTINCT (IdFuncionario, IdEpi, IdTipoProtecao)
+	);
+
+	CREATE TABLE importacao_caepi (
+		id SERIAL PRIMARY KEY,
+		arquivo VARCHAR(255) NOT NULL,
+		total_registros INT NOT NULL DEFAULT 0,
+		novos INT NOT NULL DEFAULT 0,
+		alterados INT NOT NULL DEFAULT 0,
+		importado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		sinalizada_em TIMESTAMP NULL -- quando o job já marcou os epis dos tenants afetados
+	);
+
+	CREATE TABLE catalogo_ca (
+		ca VARCHAR(20) PRIMARY KEY,
+		situacao VARCHAR(30) NOT NULL,
+		validade DATE NULL,
+		cnpj_fabricante VARCHAR(14) NULL,
+		fabricante VARCHAR(255) NOT NULL,
+		equipamento VARCHAR(255) NOT NULL,
+		descricao TEXT NOT NULL DEFAULT '',
+		situacao_anterior VARCHAR(30) NULL,
+		validade_anterior DATE NULL,
+		IdImportacao INT NOT NULL, -- última importação que trouxe o CA
+		IdImportacaoAlteracao INT NULL, -- última importação em que a situação ou a validade mudou
+		FOREIGN KEY (IdImportacao) REFERENCES importacao_caepi(id),
+		FOREIGN KEY (IdImportacaoAlteracao) REFERENCES importacao_caepi(id)
+	);
+
+	CREATE INDEX idx_catalogo_ca_alteracao ON catalogo_ca (IdImportacaoAlteracao);
+
+	CREATE UNLOGGED TABLE catalogo_ca_carga (
+		IdImportacao INT NOT NULL,
+		ca VARCHAR(20) NOT NULL,
+		situacao VARCHAR(30) NOT NULL,
+		validade DATE NULL,
+		cnpj_fabricante VARCHAR(14) NULL,
+		fabricante VARCHAR(255) NOT NULL,
+		equipamento VARCHAR(255) NOT NULL,
+		descricao TEXT NOT NULL
+	);
+
+	CREATE TABLE alerta_ca (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdEpi INT NOT NULL,
+		IdImportacao INT NOT NULL,
+		tipo VARCHAR(10) NOT NULL,
+		situacao VARCHAR(30) NOT NULL,
+		validade_anterior DATE NULL, -- validade que o epi tinha no cadastro do tenant
+		validade_nova DATE NULL,
+		criado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		resolvido_em TIMESTAMP NULL,
+		FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+		FOREIGN KEY (IdEpi) REFERENCES epi(id),
+		FOREIGN KEY (IdImportacao) REFERENCES importacao_caepi(id),
+		CONSTRAINT chk_alerta_ca_tipo CHECK (tipo IN ('cancelado', 'renovado')),
+		CONSTRAINT uq_alerta_ca UNIQUE (IdEpi, IdImportacao)
+	);
+
+	CREATE INDEX idx_alerta_ca_tenant ON alerta_ca (tenant_id) WHERE resolvido_em IS NULL;
+
+	CREATE TABLE categoria_protecao (
+		id SERIAL PRIMARY KEY,
+		codigo CHAR(1) NOT NULL UNIQUE,
+		nome VARCHAR(100) NOT NULL
+	);
+
+	INSERT INTO categoria_protecao (codigo, nome)
+	VALUES ('A', 'Proteção da cabeça'),
+		('B', 'Proteção dos olhos e face'),
+		('C', 'Proteção auditiva'),
+		('D', 'Proteção respiratória'),
+		('E', 'Proteção do tronco'),
+		('F', 'Proteção dos membros superiores'),
+		('G', 'Proteção dos membros inferiores'),
+		('H', 'Proteção do corpo inteiro'),
+		('I', 'Proteção contra quedas com diferença de nível');
+
+	ALTER TABLE tipo_protecao ADD COLUMN IdCategoria INT NULL REFERENCES categoria_protecao(id);
+
+	CREATE TABLE anexo_epi (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdEpi INT NOT NULL,
+		tipo VARCHAR(20) NOT NULL,
+		nome_arquivo VARCHAR(255) NOT NULL,
+		mime VARCHAR(100) NOT NULL,
+		tamanho BIGINT NOT NULL,
+		chave VARCHAR(255) NOT NULL UNIQUE,
+		principal BOOLEAN NOT NULL DEFAULT FALSE,
+		IdUsuario INT NULL,
+		criado_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+		FOREIGN KEY (IdEpi) REFERENCES epi(id),
+		FOREIGN KEY (IdUsuario) REFERENCES usuarios(id),
+		CONSTRAINT chk_anexo_epi_tipo CHECK (tipo IN ('foto', 'certificado_ca', 'manual', 'ficha_tecnica')),
+		CONSTRAINT chk_anexo_epi_principal CHECK (NOT principal OR tipo = 'foto')
+	);
+
+	CREATE INDEX idx_anexo_epi ON anexo_epi (IdEpi);
+	CREATE UNIQUE INDEX uq_anexo_epi_principal ON anexo_epi (IdEpi) WHERE principal;
+
+	ALTER TABLE ordem_manutencao ADD COLUMN tipo VARCHAR(20) NOT NULL DEFAULT 'higienizacao';
+
+	CREATE VIEW saida_posse AS
+	SELECT d.tenant_id, d.IdFuncionario, d.IdEpi, d.IdTamanho, d.quantidadeAdevolver AS quantidade, d.data_devolucao AS data_saida
+	FROM devolucao d
+	WHERE d.cancelada_em IS NULL
+	UNION ALL
+	SELECT b.tenant_id, b.IdFuncionario, b.IdEpi, b.IdTamanho, b.quantidade, b.data_baixa::date AS data_saida
+	FROM baixa_posse b;
+
+	-- matriz de epis obrigatórios por função (a conta de posse por requisito depende dela)
+	CREATE TABLE requisito_funcao (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdFuncao INT NOT NULL,
+		IdEpi INT NULL,
+		IdTipoProtecao INT NULL,
+		quantidade INT NOT NULL DEFAULT 1,
+		periodicidade_dias INT NULL,
+		ativo BOOLEAN NOT NULL DEFAULT TRUE,
+		deletado_em TIMESTAMP NULL,
+		FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+		FOREIGN KEY (IdFuncao) REFERENCES funcao(id),
+		FOREIGN KEY (IdEpi) REFERENCES epi(id),
+		FOREIGN KEY (IdTipoProtecao) REFERENCES tipo_protecao(id)
+	);
+
+	CREATE VIEW posse_item_entregue AS
+	SELECT 
+		i.id, ee.tenant_id, ee.IdFuncionario, i.IdEpi, e.IdTipoProtecao, i.IdTamanho, ee.data_entrega, i.quantidade,
+		LEAST(i.quantidade, GREATEST(0,
+			SUM(i.quantidade) OVER (
+				PARTITION BY ee.tenant_id, ee.IdFuncionario, i.IdEpi, i.IdTamanho
+				ORDER BY ee.data_entrega, i.id
+			) - COALESCE(s.quantidade, 0)
+		))::int AS em_posse
+	FROM epis_entregues i
+	INNER JOIN entrega_epi ee ON i.IdEntrega = ee.id
+	INNER JOIN epi e ON i.IdEpi = e.id
+	LEFT JOIN (
+		SELECT tenant_id, IdFuncionario, IdEpi, IdTamanho, SUM(quantidade) AS quantidade
+		FROM saida_posse
+		GROUP BY tenant_id, IdFuncionario, IdEpi, IdTamanho
+	) s ON s.tenant_id = ee.tenant_id 
+		AND s.IdFuncionario = ee.IdFuncionario 
+		AND s.IdEpi = i.IdEpi 
+		AND s.IdTamanho = i.IdTamanho
+	WHERE ee.cancelada_em IS NULL
+	  AND i.ativo = TRUE;
+
+	CREATE OR REPLACE FUNCTION posse_requisito(p_funcionario_id INT, p_tenant_id INT, p_requisito_id INT)
+	RETURNS TABLE (quantidade_em_posse INT, ultima_entrega DATE) AS $$
+		SELECT 
+			COALESCE(SUM(p.em_posse) FILTER (
+				WHERE r.periodicidade_dias IS NULL OR p.data_entrega > CURRENT_DATE - r.periodicidade_dias
+			), 0)::int,
+			MAX(p.data_entrega)::date
+		FROM requisito_funcao r
+		LEFT JOIN posse_item_entregue p ON p.IdFuncionario = p_funcionario_id
+			AND p.tenant_id = r.tenant_id
+			AND (r.IdEpi IS NULL OR p.IdEpi = r.IdEpi)
+			AND (r.IdTipoProtecao IS NULL OR p.IdTipoProtecao = r.IdTipoProtecao)
+		WHERE r.id = p_requisito_id
+		  AND r.tenant_id = p_tenant_id; -- SEGURANÇA
+	$$ LANGUAGE sql STABLE;
+
+	-- solicitação de epi (a reserva de uma solicitação só serve para o funcionario que pediu)
+	CREATE TABLE solicitacao_epi (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdFuncionario INT NOT NULL,
+		IdUsuarioSolicitante INT NULL,
+		justificativa VARCHAR(255) NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pendente',
+		IdUsuarioAnalise INT NULL,
+		motivo_rejeicao VARCHAR(255) NULL,
+		analisada_em TIMESTAMP NULL,
+		IdEntrega INT NULL,
+		atendida_em TIMESTAMP NULL,
+		criada_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		origem VARCHAR(20) NOT NULL DEFAULT 'sistema',
+		FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+		FOREIGN KEY (IdFuncionario) REFERENCES funcionario(id),
+		FOREIGN KEY (IdEntrega) REFERENCES entrega_epi(id),
+		CONSTRAINT chk_solicitacao_status CHECK (status IN ('pendente', 'aprovada', 'rejeitada', 'atendida')),
+		CONSTRAINT chk_solicitacao_origem CHECK (origem IN ('sistema', 'portal'))
+	);
+
+	CREATE TABLE item_solicitacao (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		IdSolicitacao INT NOT NULL,
+		IdEpi INT NOT NULL,
+		IdTamanho INT NOT NULL,
+		quantidade INT NOT NULL,
+		FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+		FOREIGN KEY (IdSolicitacao) REFERENCES solicitacao_epi(id),
+		FOREIGN KEY (IdEpi) REFERENCES epi(id),
+		FOREIGN KEY (IdTamanho) REFERENCES tamanho(id),
+		CONSTRAINT chk_item_solicitacao_quantidade CHECK (quantidade > 0)
+	);
+
+	-- operações offline dos tablets, identificadas pelo UUID gerado no aparelho
+	CREATE TABLE sync_operacao (
+		id SERIAL PRIMARY KEY,
+		tenant_id INT NOT NULL,
+		uuid UUID NOT NULL,
+		tipo VARCHAR(20) NOT NULL,
+		status VARCHAR(20) NOT NULL,
+		id_registro INT NULL,
+		erro TEXT NULL,
+		IdUsuario INT NULL,
+		recebida_em TIMESTAMP NOT NULL DEFAULT NOW(),
+		FOREIGN KEY (tenant_id) REFERENCES empresas(id),
+		CONSTRAINT chk_sync_operacao_tipo CHECK (tipo IN ('entrega', 'devolucao')),
+		CONSTRAINT chk_sync_operacao_status CHECK (status IN ('processando', 'aplicada', 'conflito')),
+		UNIQUE (tenant_id, uuid)
+	);
+`
 
 	_, err := pool.Exec(context.Background(), schema)
 	if err != nil {
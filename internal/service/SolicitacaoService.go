@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SolicitacaoRepository interface {
+	Adicionar(ctx context.Context, qtx *repository.Queries, arg repository.AddSolicitacaoParams) (int32, error)
+	AdicionarItem(ctx context.Context, qtx *repository.Queries, arg repository.AddItemSolicitacaoParams) error
+	Listar(ctx context.Context, arg repository.ListarSolicitacoesParams) ([]repository.ListarSolicitacoesRow, error)
+	ListarItens(ctx context.Context, arg repository.ListarItensDasSolicitacoesParams) ([]repository.ListarItensDasSolicitacoesRow, error)
+	BuscarParaAnalise(ctx context.Context, qtx *repository.Queries, arg repository.BuscarSolicitacaoParaAnaliseParams) (repository.BuscarSolicitacaoParaAnaliseRow, error)
+	ItensDaSolicitacao(ctx context.Context, qtx *repository.Queries, arg repository.ListarItensSolicitacaoParams) ([]repository.ListarItensSolicitacaoRow, error)
+	Aprovar(ctx context.Context, qtx *repository.Queries, arg repository.AprovarSolicitacaoParams) (int64, error)
+	Rejeitar(ctx context.Context, qtx *repository.Queries, arg repository.RejeitarSolicitacaoParams) (int64, error)
+	Atender(ctx context.Context, qtx *repository.Queries, arg repository.AtenderSolicitacaoParams) (int64, error)
+}
+
+type SolicitacaoService struct {
+	repo        SolicitacaoRepository
+	db          *pgxpool.Pool
+	queries     *repository.Queries
+	repoEntrega EntregaService
+	reserva     ReservaService
+}
+
+// validadeReservaSolicitacao é quanto tempo o estoque fica preso para uma solicitação aprovada
+const validadeReservaSolicitacao = 7 * 24 * time.Hour
+
+func NewSolicitacaoService(s SolicitacaoRepository, db *pgxpool.Pool, repoEntrega EntregaService, reserva ReservaService) *SolicitacaoService {
+
+	return &SolicitacaoService{
+		repo:        s,
+		db:          db,
+		queries:     repository.New(db),
+		repoEntrega: repoEntrega,
+		reserva:     reserva,
+	}
+}
+
+func (s *SolicitacaoService) Salvar(ctx context.Context, solicitacao model.SolicitacaoInserir, idUsuario int, tenantId int32) (int, error) {
+
+	return s.salvar(ctx, solicitacao, pgtype.Int4{Int32: int32(idUsuario), Valid: idUsuario > 0}, model.OrigemSistema, tenantId)
+}
+
+// SalvarDoPortal registra o pedido feito pelo próprio funcionario, que segue o mesmo fluxo de aprovação
+func (s *SolicitacaoService) SalvarDoPortal(ctx context.Context, solicitacao model.SolicitacaoInserir, tenantId int32) (int, error) {
+
+	return s.salvar(ctx, solicitacao, pgtype.Int4{}, model.OrigemPortal, tenantId)
+}
+
+func (s *SolicitacaoService) salvar(ctx context.Context, model model.SolicitacaoInserir, idUsuario pgtype.Int4, origem string, tenantId int32) (int, error) {
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	id, err := s.repo.Adicionar(ctx, qtx, repository.AddSolicitacaoParams{
+		TenantID:             tenantId,
+		Idfuncionario:        int32(model.IdFuncionario),
+		Idusuariosolicitante: idUsuario,
+		Justificativa:        strings.TrimSpace(model.Justificativa),
+		Origem:               origem,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao salvar solicitacao, %w", err)
+	}
+
+	for _, item := range model.Itens {
+
+		err := s.repo.AdicionarItem(ctx, qtx, repository.AddItemSolicitacaoParams{
+			TenantID:      tenantId,
+			Idsolicitacao: id,
+			Idepi:         int32(item.ID_epi),
+			Idtamanho:     int32(item.ID_tamanho),
+			Quantidade:    int32(item.Quantidade),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("erro ao salvar item da solicitacao, %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// Aprovar reserva o estoque da solicitação: ela só passa se o saldo livre dos lotes válidos,
+// descontadas as reservas em aberto, cobre todos os itens. Cada epi/tamanho vira uma reserva
+func (s *SolicitacaoService) Aprovar(ctx context.Context, id, idUsuario int, tenantId int32) error {
+
+	if id <= 0 {
+		return helper.ErrId
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	itens, err := s.itensParaAnalise(ctx, qtx, id, model.SolicitacaoPendente, tenantId)
+	if err != nil {
+		return err
+	}
+
+	type chaveItem struct{ epi, tamanho int32 }
+	pedido := make(map[chaveItem]int32)
+	ordem := make([]chaveItem, 0, len(itens))
+	for _, item := range itens {
+
+		k := chaveItem{item.Idepi, item.Idtamanho}
+		if _, ok := pedido[k]; !ok {
+			ordem = append(ordem, k)
+		}
+		pedido[k] += item.Quantidade
+	}
+
+	expiraEm := pgtype.Timestamp{Time: time.Now().Add(validadeReservaSolicitacao), Valid: true}
+	for _, k := range ordem {
+
+		_, err := s.reserva.RegistrarReserva(ctx, qtx, repository.AddReservaParams{
+			TenantID:      tenantId,
+			Idepi:         k.epi,
+			Idtamanho:     k.tamanho,
+			Quantidade:    pedido[k],
+			ExpiraEm:      expiraEm,
+			Idsolicitacao: pgtype.Int4{Int32: int32(id), Valid: true},
+			Idusuario:     pgtype.Int4{Int32: int32(idUsuario), Valid: idUsuario > 0},
+		}, tenantId)
+		if err != nil {
+			return err
+		}
+	}
+
+	linha, err := s.repo.Aprovar(ctx, qtx, repository.AprovarSolicitacaoParams{
+		ID:               int32(id),
+		Idusuarioanalise: pgtype.Int4{Int32: int32(idUsuario), Valid: idUsuario > 0},
+		TenantID:         tenantId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if linha == 0 {
+		return helper.ErrStatusSolicitacao
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *SolicitacaoService) Rejeitar(ctx context.Context, id, idUsuario int, motivo string, tenantId int32) error {
+
+	if id <= 0 {
+		return helper.ErrId
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	if _, err := s.itensParaAnalise(ctx, qtx, id, model.SolicitacaoPendente, tenantId); err != nil {
+		return err
+	}
+
+	linha, err := s.repo.Rejeitar(ctx, qtx, repository.RejeitarSolicitacaoParams{
+		ID:               int32(id),
+		Idusuarioanalise: pgtype.Int4{Int32: int32(idUsuario), Valid: idUsuario > 0},
+		MotivoRejeicao:   pgtype.Text{String: strings.TrimSpace(motivo), Valid: true},
+		TenantID:         tenantId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if linha == 0 {
+		return helper.ErrStatusSolicitacao
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Atender transforma a solicitação aprovada em uma entrega, na mesma transação,
+// usando o mesmo fluxo de baixa de estoque do cadastro de entregas
+func (s *SolicitacaoService) Atender(ctx context.Context, id, idUsuario int, dados model.AtenderSolicitacao, tenantId int32) (int, error) {
+
+	if id <= 0 {
+		return 0, helper.ErrId
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	solicitacao, err := s.repo.BuscarParaAnalise(ctx, qtx, repository.BuscarSolicitacaoParaAnaliseParams{
+		ID:       int32(id),
+		TenantID: tenantId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, helper.ErrNaoEncontrado
+		}
+		return 0, err
+	}
+
+	if solicitacao.Status != model.SolicitacaoAprovada {
+		return 0, helper.ErrStatusSolicitacao
+	}
+
+	itens, err := s.repo.ItensDaSolicitacao(ctx, qtx, repository.ListarItensSolicitacaoParams{
+		Idsolicitacao: int32(id),
+		TenantID:      tenantId,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	entrega := model.EntregaParaInserir{
+		ID_funcionario:     int64(solicitacao.Idfuncionario),
+		Id_user:            idUsuario,
+		Data_entrega:       dados.Data_entrega,
+		Assinatura_Digital: dados.Assinatura_Digital,
+		Itens:              make([]model.ItemParaInserir, 0, len(itens)),
+	}
+
+	reservas, err := s.reserva.ReservasDaSolicitacao(ctx, qtx, int32(id), tenantId)
+	if err != nil {
+		return 0, err
+	}
+
+	// a reserva foi feita por epi/tamanho, então os itens repetidos viram um só
+	posicao := make(map[[2]int32]int, len(itens))
+	for _, item := range itens {
+
+		k := [2]int32{item.Idepi, item.Idtamanho}
+		if i, ok := posicao[k]; ok {
+			entrega.Itens[i].Quantidade += int(item.Quantidade)
+			continue
+		}
+
+		novo := model.ItemParaInserir{
+			ID_epi:     int64(item.Idepi),
+			ID_tamanho: int64(item.Idtamanho),
+			Quantidade: int(item.Quantidade),
+		}
+		if idReserva, ok := reservas[k]; ok {
+			novo.IdReserva = &idReserva
+		}
+
+		posicao[k] = len(entrega.Itens)
+		entrega.Itens = append(entrega.Itens, novo)
+	}
+
+	idEntrega, err := s.repoEntrega.RegistrarEntrega(ctx, qtx, entrega, tenantId)
+	if err != nil {
+		return 0, err
+	}
+
+	linha, err := s.repo.Atender(ctx, qtx, repository.AtenderSolicitacaoParams{
+		ID:        int32(id),
+		Identrega: pgtype.Int4{Int32: idEntrega, Valid: true},
+		TenantID:  tenantId,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if linha == 0 {
+		return 0, helper.ErrStatusSolicitacao
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int(idEntrega), nil
+}
+
+// itensParaAnalise trava a solicitação, confere o status esperado e devolve os itens
+func (s *SolicitacaoService) itensParaAnalise(ctx context.Context, qtx *repository.Queries, id int, status string, tenantId int32) ([]repository.ListarItensSolicitacaoRow, error) {
+
+	solicitacao, err := s.repo.BuscarParaAnalise(ctx, qtx, repository.BuscarSolicitacaoParaAnaliseParams{
+		ID:       int32(id),
+		TenantID: tenantId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, helper.ErrNaoEncontrado
+		}
+		return nil, err
+	}
+
+	if solicitacao.Status != status {
+		return nil, helper.ErrStatusSolicitacao
+	}
+
+	return s.repo.ItensDaSolicitacao(ctx, qtx, repository.ListarItensSolicitacaoParams{
+		Idsolicitacao: int32(id),
+		TenantID:      tenantId,
+	})
+}
+
+type FiltroSolicitacao struct {
+	Status        string `form:"status"`
+	FuncionarioID int32  `form:"funcionario_id"`
+	SolicitacaoID int32  `form:"solicitacao_id"`
+	Pagina        int32  `form:"pagina"`
+	Quantidade    int32  `form:"quantidade"`
+}
+
+type SolicitacaoPaginada struct {
+	Solicitacoes []model.SolicitacaoDto `json:"solicitacoes"`
+	Total        int64                  `json:"total"`
+	Pagina       int32                  `json:"pagina"`
+	PaginaFinal  int32                  `json:"pagina_final"`
+}
+
+func (s *SolicitacaoService) Listar(ctx context.Context, f FiltroSolicitacao, tenantId int32) (SolicitacaoPaginada, error) {
+
+	limit := f.Quantidade
+	if limit <= 0 {
+		limit = 1
+	}
+	paginaAtual := f.Pagina
+	if paginaAtual <= 0 {
+		paginaAtual = 1
+	}
+
+	offset := max((paginaAtual-1)*limit, 0)
+
+	solicitacoes, err := s.repo.Listar(ctx, repository.ListarSolicitacoesParams{
+		TenantID:      tenantId,
+		Status:        pgtype.Text{String: f.Status, Valid: f.Status != ""},
+		IDFuncionario: pgtype.Int4{Int32: f.FuncionarioID, Valid: f.FuncionarioID > 0},
+		IDSolicitacao: pgtype.Int4{Int32: f.SolicitacaoID, Valid: f.SolicitacaoID > 0},
+		Limit:         limit,
+		Offset:        offset,
+	})
+	if err != nil {
+		return SolicitacaoPaginada{}, err
+	}
+
+	ids := make([]int32, 0, len(solicitacoes))
+	for _, sol := range solicitacoes {
+		ids = append(ids, sol.ID)
+	}
+
+	itens, err := s.repo.ListarItens(ctx, repository.ListarItensDasSolicitacoesParams{
+		TenantID:       tenantId,
+		IdsSolicitacao: ids,
+	})
+	if err != nil {
+		return SolicitacaoPaginada{}, err
+	}
+
+	itensMap := make(map[int32][]model.ItemSolicitacaoDto)
+	for _, i := range itens {
+		itensMap[i.Idsolicitacao] = append(itensMap[i.Idsolicitacao], model.ItemSolicitacaoDto{
+			Id:         int(i.ID),
+			IdEpi:      int(i.Idepi),
+			Epi:        i.EpiNome,
+			IdTamanho:  int(i.Idtamanho),
+			Tamanho:    i.TamanhoNome,
+			Quantidade: int(i.Quantidade),
+		})
+	}
+
+	dto := make([]model.SolicitacaoDto, 0, len(solicitacoes))
+	for _, sol := range solicitacoes {
+
+		item := model.SolicitacaoDto{
+			Id:            int(sol.ID),
+			Status:        sol.Status,
+			Origem:        sol.Origem,
+			Justificativa: sol.Justificativa,
+			Funcionario: model.Funcionario_Dto{
+				ID:        int(sol.FuncionarioID),
+				Nome:      sol.FuncionarioNome,
+				Matricula: sol.Matricula,
+			},
+			IdEntrega: int4ParaIntPtr(sol.Identrega),
+			CriadaEm:  configs.DataBr(sol.CriadaEm.Time),
+			Itens:     itensMap[sol.ID],
+		}
+
+		if sol.MotivoRejeicao.Valid {
+			item.MotivoRejeicao = &sol.MotivoRejeicao.String
+		}
+		if sol.Idusuariosolicitante.Valid {
+			item.Solicitante = &model.RecuperaUserEntrada{
+				Id:   int(sol.Idusuariosolicitante.Int32),
+				Nome: sol.SolicitanteNome.String,
+			}
+		}
+		if sol.Idusuarioanalise.Valid {
+			item.Analista = &model.RecuperaUserEntrada{
+				Id:   int(sol.Idusuarioanalise.Int32),
+				Nome: sol.AnalistaNome.String,
+			}
+		}
+		if sol.AnalisadaEm.Valid {
+			item.AnalisadaEm = configs.NewDataBrPtr(sol.AnalisadaEm.Time)
+		}
+		if sol.AtendidaEm.Valid {
+			item.AtendidaEm = configs.NewDataBrPtr(sol.AtendidaEm.Time)
+		}
+
+		dto = append(dto, item)
+	}
+
+	var total int64
+	if len(solicitacoes) > 0 {
+		total = solicitacoes[0].TotalGeral
+	}
+
+	return SolicitacaoPaginada{
+		Solicitacoes: dto,
+		Total:        total,
+		Pagina:       paginaAtual,
+		PaginaFinal:  int32(math.Ceil(float64(total) / float64(limit))),
+	}, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolicitacaoFuncionarioDesligado(t *testing.T) {
+
+	db := SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	servEntrega := NewEntregaService(repository.NewEntregaRepository(db), db)
+	servReserva := NewReservaService(repository.NewReservaRepository(db), db)
+	servSolicitacao := NewSolicitacaoService(repository.NewSolicitacaoRepository(db), db, *servEntrega, *servReserva)
+
+	idEmpresa := CreateEmpresa(t, db)
+	iddep := CreateDepartamento(t, db, idEmpresa)
+	IdFuncao := CreateFuncao(t, db, iddep, idEmpresa)
+	idtam := CreateTamanho(t, db, idEmpresa)
+	idprotec := CreateProtecao(t, db, idEmpresa)
+	idepi := CreateEpi(t, db, idprotec, idEmpresa)
+	idfuncionario := CreateFuncionario(t, db, iddep, IdFuncao, idEmpresa)
+
+	pedido := model.SolicitacaoInserir{
+		IdFuncionario: int(idfuncionario),
+		Justificativa: "luva rasgada",
+		Itens:         []model.ItemParaInserir{{ID_epi: idepi, ID_tamanho: idtam, Quantidade: 1}},
+	}
+
+	t.Run("funcionario ativo pede pelo portal", func(t *testing.T) {
+
+		id, err := servSolicitacao.SalvarDoPortal(ctx, pedido, int32(idEmpresa))
+		require.NoError(t, err)
+		require.Positive(t, id)
+	})
+
+	t.Run("funcionario desligado não pede mais", func(t *testing.T) {
+
+		_, err := db.Exec(ctx, "UPDATE funcionario SET ativo = FALSE WHERE id = $1", idfuncionario)
+		require.NoError(t, err)
+
+		_, err = servSolicitacao.SalvarDoPortal(ctx, pedido, int32(idEmpresa))
+		require.ErrorIs(t, err, helper.ErrFuncionarioInativo)
+
+		var total int
+		err = db.QueryRow(ctx, "SELECT COUNT(*) FROM solicitacao_epi WHERE IdFuncionario = $1", idfuncionario).Scan(&total)
+		require.NoError(t, err)
+		require.Equal(t, 1, total, "a solicitação recusada não deveria ter sido gravada")
+	})
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SyncRepository interface {
+	ReservarOperacao(ctx context.Context, qtx *repository.Queries, arg repository.ReservarOperacaoSyncParams) (int32, error)
+	ConcluirOperacao(ctx context.Context, qtx *repository.Queries, arg repository.ConcluirOperacaoSyncParams) error
+	RegistrarConflito(ctx context.Context, arg repository.RegistrarConflitoSyncParams) error
+	BuscarOperacao(ctx context.Context, arg repository.BuscarOperacaoSyncParams) (repository.BuscarOperacaoSyncRow, error)
+	CatalogoEpis(ctx context.Context, tenantId int32) ([]repository.CatalogoEpisSyncRow, error)
+	CatalogoTamanhosEpis(ctx context.Context, tenantId int32) ([]repository.BuscarTodosTamanhosAgrupadosRow, error)
+	CatalogoTamanhos(ctx context.Context, tenantId int32) ([]repository.BuscarTodosTamanhosRow, error)
+	CatalogoFuncionarios(ctx context.Context, tenantId int32) ([]repository.CatalogoFuncionariosSyncRow, error)
+	CatalogoSaldoLotes(ctx context.Context, tenantId int32) ([]repository.CatalogoSaldoLotesSyncRow, error)
+}
+
+// SyncService atende os tablets do almoxarifado que trabalham offline: entrega os catalogos
+// com um token de versão e aplica as operações enviadas depois, uma transação por operação
+type SyncService struct {
+	repo      SyncRepository
+	db        *pgxpool.Pool
+	queries   *repository.Queries
+	entrega   EntregaService
+	devolucao DevolucaoService
+}
+
+func NewSyncService(r SyncRepository, db *pgxpool.Pool, entrega EntregaService, devolucao DevolucaoService) *SyncService {
+
+	return &SyncService{
+		repo:      r,
+		db:        db,
+		queries:   repository.New(db),
+		entrega:   entrega,
+		devolucao: devolucao,
+	}
+}
+
+// tokenCatalogo é o hash do conteudo: muda sempre que qualquer item do catalogo muda
+func tokenCatalogo(itens any) (string, error) {
+
+	dados, err := json.Marshal(itens)
+	if err != nil {
+		return "", err
+	}
+
+	soma := sha256.Sum256(dados)
+	return hex.EncodeToString(soma[:16]), nil
+}
+
+func montarCatalogo(itens any, tokenCliente string) (model.CatalogoSync, error) {
+
+	token, err := tokenCatalogo(itens)
+	if err != nil {
+		return model.CatalogoSync{}, err
+	}
+
+	if token == tokenCliente {
+		return model.CatalogoSync{Token: token}, nil
+	}
+
+	return model.CatalogoSync{Token: token, Alterado: true, Itens: itens}, nil
+}
+
+func (s *SyncService) Catalogos(ctx context.Context, f model.FiltroCatalogoSync, tenantId int32) (model.CatalogosSyncDto, error) {
+
+	var catalogos model.CatalogosSyncDto
+
+	epis, err := s.catalogoEpis(ctx, tenantId)
+	if err != nil {
+		return model.CatalogosSyncDto{}, err
+	}
+	if catalogos.Epis, err = montarCatalogo(epis, f.TokenEpis); err != nil {
+		return model.CatalogosSyncDto{}, err
+	}
+
+	tamanhos, err := s.repo.CatalogoTamanhos(ctx, tenantId)
+	if err != nil {
+		return model.CatalogosSyncDto{}, err
+	}
+	tamanhosDto := make([]model.TamanhoDto, 0, len(tamanhos))
+	for _, t := range tamanhos {
+		tamanhosDto = append(tamanhosDto, model.TamanhoDto{ID: int(t.ID), Tamanho: t.Tamanho})
+	}
+	if catalogos.Tamanhos, err = montarCatalogo(tamanhosDto, f.TokenTamanhos); err != nil {
+		return model.CatalogosSyncDto{}, err
+	}
+
+	funcionarios, err := s.repo.CatalogoFuncionarios(ctx, tenantId)
+	if err != nil {
+		return model.CatalogosSyncDto{}, err
+	}
+	funcionariosDto := make([]model.FuncionarioSyncDto, 0, len(funcionarios))
+	for _, fn := range funcionarios {
+		funcionariosDto = append(funcionariosDto, model.FuncionarioSyncDto{
+			Id:             int(fn.ID),
+			Nome:           fn.Nome,
+			Matricula:      fn.Matricula,
+			IdFuncao:       int(fn.Idfuncao),
+			IdDepartamento: int(fn.Iddepartamento),
+		})
+	}
+	if catalogos.Funcionarios, err = montarCatalogo(funcionariosDto, f.TokenFuncionarios); err != nil {
+		return model.CatalogosSyncDto{}, err
+	}
+
+	lotes, err := s.repo.CatalogoSaldoLotes(ctx, tenantId)
+	if err != nil {
+		return model.CatalogosSyncDto{}, err
+	}
+	lotesDto := make([]model.SaldoLoteSyncDto, 0, len(lotes))
+	for _, l := range lotes {
+		lotesDto = append(lotesDto, model.SaldoLoteSyncDto{
+			IdEntrada:    int(l.ID),
+			IdEpi:        int(l.Idepi),
+			IdTamanho:    int(l.Idtamanho),
+			Lote:         l.Lote,
+			DataValidade: configs.DataBr(l.DataValidade.Time),
+			Quantidade:   int(max(l.QuantidadeLivre, 0)),
+		})
+	}
+	if catalogos.Estoque, err = montarCatalogo(lotesDto, f.TokenEstoque); err != nil {
+		return model.CatalogosSyncDto{}, err
+	}
+
+	return catalogos, nil
+}
+
+func (s *SyncService) catalogoEpis(ctx context.Context, tenantId int32) ([]model.EpiSyncDto, error) {
+
+	epis, err := s.repo.CatalogoEpis(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	tamanhos, err := s.repo.CatalogoTamanhosEpis(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	// a query de tamanhos não tem ORDER BY, e o token depende da ordem
+	sort.Slice(tamanhos, func(i, j int) bool {
+		if tamanhos[i].Idepi != tamanhos[j].Idepi {
+			return tamanhos[i].Idepi < tamanhos[j].Idepi
+		}
+		return tamanhos[i].ID < tamanhos[j].ID
+	})
+
+	tamanhosMap := make(map[int32][]model.TamanhoDto)
+	for _, t := range tamanhos {
+		tamanhosMap[t.Idepi] = append(tamanhosMap[t.Idepi], model.TamanhoDto{
+			ID:      int(t.ID),
+			Tamanho: t.Tamanho,
+		})
+	}
+
+	dto := make([]model.EpiSyncDto, 0, len(epis))
+	for _, e := range epis {
+
+		item := model.EpiSyncDto{
+			Id:             int(e.ID),
+			Nome:           e.Nome,
+			Fabricante:     e.Fabricante,
+			CA:             e.Ca,
+			DataValidadeCa: configs.DataBr(e.ValidadeCa.Time),
+			IdTipoProtecao: int(e.Idtipoprotecao),
+			Tamanhos:       tamanhosMap[e.ID],
+		}
+		if item.Tamanhos == nil {
+			item.Tamanhos = []model.TamanhoDto{}
+		}
+
+		dto = append(dto, item)
+	}
+
+	return dto, nil
+}
+
+// Enviar aplica as operações na ordem recebida. Cada uma tem sua transação,
+// então um conflito numa operação não desfaz as outras
+func (s *SyncService) Enviar(ctx context.Context, operacoes []model.OperacaoSync, idUsuario int, tenantId int32) model.ResultadoSync {
+
+	resultado := model.ResultadoSync{
+		Operacoes: make([]model.ResultadoOperacaoSync, 0, len(operacoes)),
+	}
+
+	for _, op := range operacoes {
+
+		r := s.aplicar(ctx, op, idUsuario, tenantId)
+
+		switch r.Status {
+		case model.SyncAplicada:
+			resultado.Aplicadas++
+		case model.SyncDuplicada:
+			resultado.Duplicadas++
+		case model.SyncConflito:
+			resultado.Conflitos++
+		default:
+			resultado.Erros++
+		}
+
+		resultado.Operacoes = append(resultado.Operacoes, r)
+	}
+
+	return resultado
+}
+
+func (s *SyncService) aplicar(ctx context.Context, op model.OperacaoSync, idUsuario int, tenantId int32) model.ResultadoOperacaoSync {
+
+	resultado := model.ResultadoOperacaoSync{Uuid: op.Uuid}
+
+	var uuid pgtype.UUID
+	if err := uuid.Scan(op.Uuid); err != nil {
+		resultado.Status = model.SyncConflito
+		resultado.Erro = "uuid invalido"
+		return resultado
+	}
+
+	usuario := pgtype.Int4{Int32: int32(idUsuario), Valid: idUsuario > 0}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		resultado.Status = model.SyncErro
+		resultado.Erro = err.Error()
+		return resultado
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.queries.WithTx(tx)
+
+	idOperacao, err := s.repo.ReservarOperacao(ctx, qtx, repository.ReservarOperacaoSyncParams{
+		TenantID:  tenantId,
+		Uuid:      uuid,
+		Tipo:      op.Tipo,
+		Idusuario: usuario,
+	})
+	if err != nil {
+
+		if !errors.Is(err, pgx.ErrNoRows) {
+			resultado.Status = model.SyncErro
+			resultado.Erro = err.Error()
+			return resultado
+		}
+
+		// UUID já aplicado: devolve o registro criado na primeira vez
+		tx.Rollback(ctx)
+		anterior, err := s.repo.BuscarOperacao(ctx, repository.BuscarOperacaoSyncParams{
+			Uuid:     uuid,
+			TenantID: tenantId,
+		})
+		if err != nil {
+			resultado.Status = model.SyncErro
+			resultado.Erro = err.Error()
+			return resultado
+		}
+
+		resultado.Status = model.SyncDuplicada
+		resultado.IdRegistro = int4ParaIntPtr(anterior.IDRegistro)
+		return resultado
+	}
+
+	var idRegistro int32
+	switch op.Tipo {
+	case model.SyncEntrega:
+		idRegistro, err = s.entrega.RegistrarEntrega(ctx, qtx, *op.Entrega, tenantId)
+	case model.SyncDevolucao:
+		idRegistro, err = s.devolucao.RegistrarDevolucao(ctx, qtx, *op.Devolucao, tenantId)
+	}
+	if err != nil {
+
+		// desfaz antes de gravar o conflito: a linha reservada ainda prende o UUID nesta transação
+		tx.Rollback(ctx)
+
+		if !conflitoSync(err) {
+			resultado.Status = model.SyncErro
+			resultado.Erro = err.Error()
+			return resultado
+		}
+
+		resultado.Status = model.SyncConflito
+		resultado.Erro = err.Error()
+
+		errConflito := s.repo.RegistrarConflito(ctx, repository.RegistrarConflitoSyncParams{
+			TenantID:  tenantId,
+			Uuid:      uuid,
+			Tipo:      op.Tipo,
+			Erro:      pgtype.Text{String: err.Error(), Valid: true},
+			Idusuario: usuario,
+		})
+		if errConflito != nil {
+			resultado.Status = model.SyncErro
+			resultado.Erro = errConflito.Error()
+		}
+
+		return resultado
+	}
+
+	err = s.repo.ConcluirOperacao(ctx, qtx, repository.ConcluirOperacaoSyncParams{
+		ID:         idOperacao,
+		IDRegistro: pgtype.Int4{Int32: idRegistro, Valid: true},
+		TenantID:   tenantId,
+	})
+	if err != nil {
+		resultado.Status = model.SyncErro
+		resultado.Erro = err.Error()
+		return resultado
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		resultado.Status = model.SyncErro
+		resultado.Erro = err.Error()
+		return resultado
+	}
+
+	id := int(idRegistro)
+	resultado.Status = model.SyncAplicada
+	resultado.IdRegistro = &id
+	return resultado
+}
+
+// conflitoSync separa recusa de regra de negócio (que o tablet precisa resolver)
+// de falha do servidor (que basta reenviar)
+func conflitoSync(err error) bool {
+
+	return errors.Is(err, helper.ErrEstoqueInsuficiente) ||
+		errors.Is(err, helper.ErrNaoEncontrado) ||
+		errors.Is(err, helper.ErrConflitoIntegridade) ||
+		errors.Is(err, helper.ErrReservaInvalida) ||
+		errors.Is(err, helper.ErrCampoObrigatorio) ||
+		errors.Is(err, helper.ErrDevolucaoSemPosse) ||
+		errors.Is(err, helper.ErrSemTreinamento) ||
+		errors.Is(err, helper.ErrSemLoteDevolucao)
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnviarSync(t *testing.T) {
+
+	db := SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	servEntrega := NewEntregaService(repository.NewEntregaRepository(db), db)
+	servDevolucao := NewDevolucaoService(repository.NewDevolucaoRepository(db), db, *servEntrega, *NewPosseService(repository.NewPosseRepository(db)))
+	servSync := NewSyncService(repository.NewSyncRepository(db), db, *servEntrega, *servDevolucao)
+
+	idEmpresa := CreateEmpresa(t, db)
+	iduser := CreateUser(t, db, idEmpresa)
+	iddep := CreateDepartamento(t, db, idEmpresa)
+	IdFuncao := CreateFuncao(t, db, iddep, idEmpresa)
+	idtam := CreateTamanho(t, db, idEmpresa)
+	idprotec := CreateProtecao(t, db, idEmpresa)
+	idepi := CreateEpi(t, db, idprotec, idEmpresa)
+	idfuncionario := CreateFuncionario(t, db, iddep, IdFuncao, idEmpresa)
+
+	Idfornecedor := CreateFornecedor(t, db, idEmpresa)
+	idEntrada := CreateEntradaEpi(t, db, idfuncionario, idepi, idprotec, idtam, Idfornecedor, iduser, idEmpresa)
+
+	// operação como o tablet manda: o UUID é gerado no aparelho no momento da entrega
+	operacaoEntrega := func(uuid string, quantidade int) model.OperacaoSync {
+
+		return model.OperacaoSync{
+			Uuid: uuid,
+			Tipo: model.SyncEntrega,
+			Entrega: &model.EntregaParaInserir{
+				ID_funcionario:     idfuncionario,
+				Id_user:            int(iduser),
+				Data_entrega:       *configs.NewDataBrPtr(time.Now()),
+				Assinatura_Digital: "assinatura_base64_teste",
+				Itens: []model.ItemParaInserir{
+					{ID_epi: idepi, ID_tamanho: idtam, Quantidade: quantidade},
+				},
+			},
+		}
+	}
+
+	estoqueAtual := func() int32 {
+
+		var estoque int32
+		err := db.QueryRow(ctx, "SELECT quantidadeAtual FROM entrada_epi WHERE id = $1 AND tenant_id = $2", idEntrada, idEmpresa).Scan(&estoque)
+		require.NoError(t, err)
+		return estoque
+	}
+
+	uuidEntrega := "7f9c2ba4-e88f-4d1a-9b3c-1a2b3c4d5e6f"
+	var idEntrega int
+
+	t.Run("operação nova é aplicada", func(t *testing.T) {
+
+		resultado := servSync.Enviar(ctx, []model.OperacaoSync{operacaoEntrega(uuidEntrega, 10)}, int(iduser), int32(idEmpresa))
+
+		require.Equal(t, 1, resultado.Aplicadas)
+		require.Len(t, resultado.Operacoes, 1)
+		require.Equal(t, model.SyncAplicada, resultado.Operacoes[0].Status)
+		require.NotNil(t, resultado.Operacoes[0].IdRegistro)
+
+		idEntrega = *resultado.Operacoes[0].IdRegistro
+		require.Equal(t, int32(90), estoqueAtual())
+	})
+
+	t.Run("mesmo UUID reenviado não entrega de novo e devolve o registro da primeira vez", func(t *testing.T) {
+
+		resultado := servSync.Enviar(ctx, []model.OperacaoSync{operacaoEntrega(uuidEntrega, 10)}, int(iduser), int32(idEmpresa))
+
+		require.Equal(t, 0, resultado.Aplicadas)
+		require.Equal(t, 1, resultado.Duplicadas)
+		require.Equal(t, model.SyncDuplicada, resultado.Operacoes[0].Status)
+		require.NotNil(t, resultado.Operacoes[0].IdRegistro)
+		require.Equal(t, idEntrega, *resultado.Operacoes[0].IdRegistro)
+
+		var count int
+		err := db.QueryRow(ctx, "SELECT count(*) FROM entrega_epi WHERE IdFuncionario = $1 AND tenant_id = $2", idfuncionario, idEmpresa).Scan(&count)
+		require.NoError(t, err)
+		require.Equal(t, 1, count, "o reenvio não deveria ter criado outra entrega")
+		require.Equal(t, int32(90), estoqueAtual())
+	})
+
+	t.Run("sem estoque vira conflito e o mesmo UUID corrigido pode ser reenviado", func(t *testing.T) {
+
+		uuidConflito := "0b1d3f5a-7c9e-4b2d-8f6a-1c3e5a7b9d0f"
+
+		resultado := servSync.Enviar(ctx, []model.OperacaoSync{operacaoEntrega(uuidConflito, 300)}, int(iduser), int32(idEmpresa))
+
+		require.Equal(t, 1, resultado.Conflitos)
+		require.Equal(t, model.SyncConflito, resultado.Operacoes[0].Status)
+		require.NotEmpty(t, resultado.Operacoes[0].Erro)
+		require.Nil(t, resultado.Operacoes[0].IdRegistro)
+		require.Equal(t, int32(90), estoqueAtual(), "o conflito não deveria ter mexido no estoque")
+
+		var status string
+		err := db.QueryRow(ctx, "SELECT status FROM sync_operacao WHERE uuid = $1 AND tenant_id = $2", uuidConflito, idEmpresa).Scan(&status)
+		require.NoError(t, err)
+		require.Equal(t, "conflito", status)
+
+		// o almoxarife corrige a quantidade no tablet e reenvia com o mesmo UUID
+		resultado = servSync.Enviar(ctx, []model.OperacaoSync{operacaoEntrega(uuidConflito, 5)}, int(iduser), int32(idEmpresa))
+
+		require.Equal(t, 1, resultado.Aplicadas)
+		require.Equal(t, model.SyncAplicada, resultado.Operacoes[0].Status)
+		require.Equal(t, int32(85), estoqueAtual())
+
+		err = db.QueryRow(ctx, "SELECT status FROM sync_operacao WHERE uuid = $1 AND tenant_id = $2", uuidConflito, idEmpresa).Scan(&status)
+		require.NoError(t, err)
+		require.Equal(t, "aplicada", status)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 
@@ -20,6 +21,8 @@ type ProtecaoRepository interface {
 	ListarProtecao(ctx context.Context, arg repository.BuscarProtecaoParams) (repository.BuscarProtecaoRow, error)
 	ListarProtecoes(ctx context.Context, tenantId int32) ([]repository.BuscarTodasProtecoesRow, error)
 	CancelarProtecao(ctx context.Context, arg repository.DeletarProtecaoParams) (int64, error)
+	DefinirCategoria(ctx context.Context, arg repository.DefinirCategoriaProtecaoParams) (int64, error)
+	ListarCategorias(ctx context.Context) ([]repository.CategoriaProtecao, error)
 }
 
 type ProtecaoService struct {
@@ -39,6 +42,7 @@ func (p *ProtecaoService) SalvarProtecao(ctx context.Context, model model.TipoPr
 	err:= p.repo.Adicionar(ctx, repository.AddProtecaoParams{
 		Nome: model.Nome,
 		TenantID: tenantId,
+		Idcategoria: pgtype.Int4{Int32: int32(model.IdCategoria), Valid: true},
 	})
 	if err != nil {
 		return  err
@@ -67,6 +71,7 @@ func (p *ProtecaoService) ListarProtecao(ctx context.Context, id int, tenatId in
 	return model.TipoProtecaoDto{
 		ID: int64(protecao.ID),
 		Nome: protecao.Nome,
+		Categoria: categoriaProtecaoDto(protecao.Idcategoria, protecao.CategoriaCodigo, protecao.CategoriaNome),
 	}, nil
 }
 
@@ -85,6 +90,7 @@ func (p *ProtecaoService) ListarProtecoes(ctx context.Context, tenantId int32) (
 		pro := model.TipoProtecaoDto{
 			ID: int64(prot.ID),
 			Nome: prot.Nome,
+			Categoria: categoriaProtecaoDto(prot.Idcategoria, prot.CategoriaCodigo, prot.CategoriaNome),
 		}
 		protecDto = append(protecDto, pro)
 
@@ -118,4 +124,96 @@ func (p *ProtecaoService) DeletarProtecao(ctx context.Context, id int, tenantId
 
 	return nil
 
-}
\ No newline at end of file
+}
+
+// DefinirCategoria coloca o tipo de proteção (subcategoria da empresa) embaixo de uma categoria da NR-6
+func (p *ProtecaoService) DefinirCategoria(ctx context.Context, id int, categoria model.DefinirCategoriaProtecao, tenantId int32) error {
+
+	if id <= 0 {
+		return helper.ErrId
+	}
+
+	linhas, err := p.repo.DefinirCategoria(ctx, repository.DefinirCategoriaProtecaoParams{
+		ID:          int32(id),
+		Idcategoria: pgtype.Int4{Int32: int32(categoria.IdCategoria), Valid: true},
+		TenantID:    tenantId,
+	})
+	if err != nil {
+
+		return fmt.Errorf("erro ao definir categoria da protecao, %w", err)
+	}
+
+	if linhas == 0 {
+
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+// Classificacao monta a árvore: as categorias da NR-6 (todas, mesmo sem nada embaixo) com os
+// tipos de proteção da empresa como subcategorias
+func (p *ProtecaoService) Classificacao(ctx context.Context, tenantId int32) (model.ClassificacaoProtecaoDto, error) {
+
+	categorias, err := p.repo.ListarCategorias(ctx)
+	if err != nil {
+
+		return model.ClassificacaoProtecaoDto{}, fmt.Errorf("erro ao listar categorias de protecao, %w", err)
+	}
+
+	protecoes, err := p.repo.ListarProtecoes(ctx, tenantId)
+	if err != nil {
+
+		return model.ClassificacaoProtecaoDto{}, err
+	}
+
+	subcategorias := make(map[int32][]model.TipoProtecaoDto)
+	classificacao := model.ClassificacaoProtecaoDto{
+		Categorias:   make([]model.CategoriaComSubcategoriasDto, 0, len(categorias)),
+		SemCategoria: []model.TipoProtecaoDto{},
+	}
+
+	for _, prot := range protecoes {
+
+		dto := model.TipoProtecaoDto{ID: int64(prot.ID), Nome: prot.Nome}
+		if !prot.Idcategoria.Valid {
+			classificacao.SemCategoria = append(classificacao.SemCategoria, dto)
+			continue
+		}
+		subcategorias[prot.Idcategoria.Int32] = append(subcategorias[prot.Idcategoria.Int32], dto)
+	}
+
+	for _, c := range categorias {
+
+		item := model.CategoriaComSubcategoriasDto{
+			Categoria:     model.CategoriaProtecaoDto{ID: int(c.ID), Codigo: c.Codigo, Nome: c.Nome},
+			Subcategorias: subcategorias[c.ID],
+		}
+		if item.Subcategorias == nil {
+			item.Subcategorias = []model.TipoProtecaoDto{}
+		}
+		classificacao.Categorias = append(classificacao.Categorias, item)
+	}
+
+	return classificacao, nil
+}
+
+// categoriaProtecaoDto: nil para tipo de proteção ainda sem categoria
+func categoriaProtecaoDto(id pgtype.Int4, codigo, nome pgtype.Text) *model.CategoriaProtecaoDto {
+
+	if !id.Valid {
+		return nil
+	}
+
+	return &model.CategoriaProtecaoDto{ID: int(id.Int32), Codigo: codigo.String, Nome: nome.String}
+}
+
+// categoriaAntes ordena pela letra do Anexo I, com os tipos sem categoria no fim
+func categoriaAntes(a, b *model.CategoriaProtecaoDto) bool {
+
+	if a == nil || b == nil {
+		return b == nil && a != nil
+	}
+
+	return a.Codigo < b.Codigo
+}
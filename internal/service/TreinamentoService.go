@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// regra do tenant para entregas de epi sem treinamento válido
+const (
+	regraTreinamentoDesligada = "desligada"
+	regraTreinamentoAvisar    = "avisar"
+	regraTreinamentoBloquear  = "bloquear"
+)
+
+type TreinamentoRepository interface {
+	Adicionar(ctx context.Context, qtx *repository.Queries, arg repository.AddTreinamentoParams) (int32, error)
+	AdicionarParticipante(ctx context.Context, qtx *repository.Queries, arg repository.AddParticipanteTreinamentoParams) (int64, error)
+	Listar(ctx context.Context, arg repository.ListarTreinamentosParams) ([]repository.ListarTreinamentosRow, error)
+	Buscar(ctx context.Context, arg repository.BuscarTreinamentoParams) (repository.BuscarTreinamentoRow, error)
+	Participantes(ctx context.Context, arg repository.ListarParticipantesTreinamentoParams) ([]repository.ListarParticipantesTreinamentoRow, error)
+	Deletar(ctx context.Context, arg repository.DeletarTreinamentoParams) (int64, error)
+	DefinirRegra(ctx context.Context, arg repository.DefinirRegraTreinamentoParams) (int64, error)
+}
+
+type TreinamentoService struct {
+	repo    TreinamentoRepository
+	db      *pgxpool.Pool
+	queries *repository.Queries
+}
+
+func NewTreinamentoService(r TreinamentoRepository, pool *pgxpool.Pool) *TreinamentoService {
+	return &TreinamentoService{repo: r, db: pool, queries: repository.New(pool)}
+}
+
+// Salvar grava o treinamento e a lista de presença juntos; se algum participante não existir nada é gravado
+func (t *TreinamentoService) Salvar(ctx context.Context, model model.TreinamentoInserir, tenantId int32) (int, error) {
+
+	if (model.IdEpi == nil) == (model.IdTipoProtecao == nil) {
+		return 0, helper.ErrTreinamentoAlvo
+	}
+
+	if !model.DataValidade.IsZero() && model.DataValidade.Time().Before(model.DataRealizacao.Time()) {
+		return 0, helper.ErrValidadeTreinamento
+	}
+
+	tx, err := t.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := t.queries.WithTx(tx)
+
+	id, err := t.repo.Adicionar(ctx, qtx, repository.AddTreinamentoParams{
+		TenantID:       tenantId,
+		Titulo:         model.Titulo,
+		Idepi:          intPtrParaInt4(model.IdEpi),
+		Idtipoprotecao: intPtrParaInt4(model.IdTipoProtecao),
+		DataRealizacao: pgtype.Date{Time: model.DataRealizacao.Time(), Valid: true},
+		Instrutor:      model.Instrutor,
+		DataValidade:   pgtype.Date{Time: model.DataValidade.Time(), Valid: !model.DataValidade.IsZero()},
+	})
+	if err != nil {
+
+		return 0, fmt.Errorf("erro ao salvar treinamento, %w", err)
+	}
+
+	var vistos []int
+	for _, idFuncionario := range model.Participantes {
+
+		if slices.Contains(vistos, idFuncionario) {
+			continue
+		}
+		vistos = append(vistos, idFuncionario)
+
+		linha, err := t.repo.AdicionarParticipante(ctx, qtx, repository.AddParticipanteTreinamentoParams{
+			IDTreinamento: id,
+			IDFuncionario: int32(idFuncionario),
+			TenantID:      tenantId,
+		})
+		if err != nil {
+
+			return 0, fmt.Errorf("erro ao salvar participante do treinamento, %w", err)
+		}
+
+		if linha == 0 {
+			return 0, fmt.Errorf("%w: funcionario ID %d", helper.ErrNaoEncontrado, idFuncionario)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// Listar aceita filtro por funcionario participante e por epi (o treinamento do tipo de proteção do epi também entra)
+func (t *TreinamentoService) Listar(ctx context.Context, idFuncionario, idEpi *int, tenantId int32) ([]model.TreinamentoDto, error) {
+
+	treinamentos, err := t.repo.Listar(ctx, repository.ListarTreinamentosParams{
+		TenantID:      tenantId,
+		IDFuncionario: intPtrParaInt4(idFuncionario),
+		IDEpi:         intPtrParaInt4(idEpi),
+	})
+	if err != nil {
+
+		return []model.TreinamentoDto{}, fmt.Errorf("erro ao listar treinamentos, %w", err)
+	}
+
+	dto := make([]model.TreinamentoDto, 0, len(treinamentos))
+	for _, tr := range treinamentos {
+
+		item := montarTreinamentoDto(repository.BuscarTreinamentoRow{
+			ID:             tr.ID,
+			Titulo:         tr.Titulo,
+			DataRealizacao: tr.DataRealizacao,
+			Instrutor:      tr.Instrutor,
+			DataValidade:   tr.DataValidade,
+			EpiID:          tr.EpiID,
+			EpiNome:        tr.EpiNome,
+			TpID:           tr.TpID,
+			TpNome:         tr.TpNome,
+		})
+		item.TotalParticipantes = int(tr.TotalParticipantes)
+
+		dto = append(dto, item)
+	}
+
+	return dto, nil
+}
+
+func (t *TreinamentoService) Buscar(ctx context.Context, id int, tenantId int32) (model.TreinamentoDto, error) {
+
+	if id <= 0 {
+		return model.TreinamentoDto{}, helper.ErrId
+	}
+
+	tr, err := t.repo.Buscar(ctx, repository.BuscarTreinamentoParams{
+		ID:       int32(id),
+		TenantID: tenantId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.TreinamentoDto{}, helper.ErrNaoEncontrado
+		}
+
+		return model.TreinamentoDto{}, fmt.Errorf("erro ao buscar treinamento, %w", err)
+	}
+
+	participantes, err := t.repo.Participantes(ctx, repository.ListarParticipantesTreinamentoParams{
+		Idtreinamento: int32(id),
+		TenantID:      tenantId,
+	})
+	if err != nil {
+
+		return model.TreinamentoDto{}, fmt.Errorf("erro ao buscar participantes do treinamento, %w", err)
+	}
+
+	dto := montarTreinamentoDto(tr)
+	dto.TotalParticipantes = len(participantes)
+	dto.Participantes = make([]model.ParticipanteTreinamentoDto, 0, len(participantes))
+	for _, p := range participantes {
+		dto.Participantes = append(dto.Participantes, model.ParticipanteTreinamentoDto{
+			ID:        int(p.ID),
+			Nome:      p.Nome,
+			Matricula: p.Matricula,
+		})
+	}
+
+	return dto, nil
+}
+
+func (t *TreinamentoService) Deletar(ctx context.Context, id int, tenantId int32) error {
+
+	if id <= 0 {
+		return helper.ErrId
+	}
+
+	linha, err := t.repo.Deletar(ctx, repository.DeletarTreinamentoParams{
+		ID:       int32(id),
+		TenantID: tenantId,
+	})
+	if err != nil {
+
+		return fmt.Errorf("erro ao deletar treinamento, %w", err)
+	}
+
+	if linha == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+// DefinirRegra liga a checagem de treinamento no RegistrarEntrega: "avisar" grava a entrega marcada
+// com pendência, "bloquear" recusa a entrega
+func (t *TreinamentoService) DefinirRegra(ctx context.Context, regra model.RegraTreinamentoEntrega, tenantId int32) error {
+
+	linha, err := t.repo.DefinirRegra(ctx, repository.DefinirRegraTreinamentoParams{
+		ID:                      tenantId,
+		RegraTreinamentoEntrega: regra.Regra,
+	})
+	if err != nil {
+
+		return fmt.Errorf("erro ao salvar a regra de treinamento, %w", err)
+	}
+
+	if linha == 0 {
+		return helper.ErrNaoEncontrado
+	}
+
+	return nil
+}
+
+func montarTreinamentoDto(tr repository.BuscarTreinamentoRow) model.TreinamentoDto {
+
+	item := model.TreinamentoDto{
+		ID:             int(tr.ID),
+		Titulo:         tr.Titulo,
+		DataRealizacao: configs.DataBr(tr.DataRealizacao.Time),
+		Instrutor:      tr.Instrutor,
+	}
+
+	if tr.DataValidade.Valid {
+		item.DataValidade = configs.NewDataBrPtr(tr.DataValidade.Time)
+	}
+
+	if tr.EpiID.Valid {
+		item.Epi = &model.EpiResumoDto{ID: int(tr.EpiID.Int32), Nome: tr.EpiNome.String}
+	}
+
+	if tr.TpID.Valid {
+		item.TipoProtecao = &model.TipoProtecaoDto{ID: int64(tr.TpID.Int32), Nome: tr.TpNome.String}
+	}
+
+	return item
+}
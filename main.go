@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/configs"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/helper"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/routers"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/internal/service"
 	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/middleware"
 	"github.com/go-playground/validator/v10"
 
@@ -58,8 +62,17 @@ func main() {
 		if err != nil {
 			log.Fatal("Erro ao registrar validador de CNPJ")
 		}
+
+		err = v.RegisterValidation("cpf", helper.ValidateCPF)
+		if err != nil {
+			log.Fatal("Erro ao registrar validador de CPF")
+		}
 	}
 
+	// job do CAEPI: sinaliza os epis das importações feitas pelo comando importar-caepi
+	caepi := service.NewCaepiService(repository.NewCaepiRepository(db), db)
+	go caepi.SinalizarPeriodicamente(context.Background(), time.Hour)
+
 	container := routers.NewContainer(db)
 
 	routers.ConfigurarRotas(router, container, db)
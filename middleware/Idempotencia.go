@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const HeaderIdempotencia = "Idempotency-Key"
+
+// respostaGravada copia tudo que o handler escreve, para guardar junto com a chave
+type respostaGravada struct {
+	gin.ResponseWriter
+	corpo *bytes.Buffer
+}
+
+func (r *respostaGravada) Write(b []byte) (int, error) {
+	r.corpo.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *respostaGravada) WriteString(s string) (int, error) {
+	r.corpo.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// Idempotencia faz um retry com o mesmo Idempotency-Key devolver a resposta original
+// em vez de executar a criação de novo (ex: tablet reenviando a entrega com wi-fi ruim).
+// Sem o header a requisição segue normal. Só respostas 2xx ficam guardadas, erro libera a chave
+// para o cliente corrigir e tentar de novo
+func Idempotencia(querie *repository.Queries) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		chave := c.GetHeader(HeaderIdempotencia)
+		if chave == "" {
+			c.Next()
+			return
+		}
+
+		if len(chave) > 100 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key deve ter no maximo 100 caracteres"})
+			return
+		}
+
+		tenantID, ok := GetTenantID(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Erro interno de tenant"})
+			return
+		}
+
+		corpo, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "não foi possivel ler o corpo da requisição"})
+			return
+		}
+		// devolve o corpo para o handler conseguir fazer o bind
+		c.Request.Body = io.NopCloser(bytes.NewReader(corpo))
+
+		h := sha256.New()
+		h.Write([]byte(c.Request.Method + " " + c.FullPath() + "\n"))
+		h.Write(corpo)
+		hash := hex.EncodeToString(h.Sum(nil))
+
+		ctx := c.Request.Context()
+
+		id, err := querie.ReservarChaveIdempotente(ctx, repository.ReservarChaveIdempotenteParams{
+			TenantID:       tenantID,
+			Chave:          chave,
+			HashRequisicao: hash,
+		})
+		if err != nil {
+
+			if !errors.Is(err, pgx.ErrNoRows) {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Erro interno ao validar Idempotency-Key"})
+				return
+			}
+
+			// chave já usada: devolve o que foi gravado
+			anterior, err := querie.BuscarChaveIdempotente(ctx, repository.BuscarChaveIdempotenteParams{
+				Chave:    chave,
+				TenantID: tenantID,
+			})
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Erro interno ao validar Idempotency-Key"})
+				return
+			}
+
+			if anterior.HashRequisicao != hash {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key já usada com outra requisição"})
+				return
+			}
+
+			if !anterior.StatusResposta.Valid {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "requisição com esta Idempotency-Key ainda em processamento"})
+				return
+			}
+
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(int(anterior.StatusResposta.Int32), "application/json; charset=utf-8", []byte(anterior.CorpoResposta.String))
+			c.Abort()
+			return
+		}
+
+		gravador := &respostaGravada{ResponseWriter: c.Writer, corpo: &bytes.Buffer{}}
+		c.Writer = gravador
+
+		c.Next()
+
+		// contexto sem cancelamento: se o cliente caiu, a chave ainda precisa ser gravada
+		ctxGravar := context.WithoutCancel(ctx)
+
+		status := c.Writer.Status()
+		if status < 200 || status >= 300 {
+
+			_ = querie.LiberarChaveIdempotente(ctxGravar, repository.LiberarChaveIdempotenteParams{
+				ID:       id,
+				TenantID: tenantID,
+			})
+			return
+		}
+
+		_ = querie.ConcluirChaveIdempotente(ctxGravar, repository.ConcluirChaveIdempotenteParams{
+			ID:             id,
+			StatusResposta: pgtype.Int4{Int32: int32(status), Valid: true},
+			CorpoResposta:  pgtype.Text{String: gravador.corpo.String(), Valid: true},
+			TenantID:       tenantID,
+		})
+	}
+}
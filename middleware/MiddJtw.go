@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/auth"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	
@@ -55,6 +56,13 @@ func AutenticacaoJWT() gin.HandlerFunc {
 
 		if claims, ok := token.Claims.(jwt.MapClaims); ok {
 
+			// token do portal do funcionario não abre as rotas do sistema
+			if tipo, _ := claims["tipo"].(string); tipo == auth.TipoFuncionario {
+
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token invalido ou expirado"})
+				return
+			}
+
 			// "sub"  guarda o ID do usuário
 			if userId, ok := claims["sub"].(float64); ok {
 
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/auth"
+	"github.com/davi-fernandesx/sistema-de-gestao-de-epi/database/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+const FuncionarioId = "funcionarioId"
+
+// AutenticacaoFuncionario protege o portal do funcionario: só aceita o token emitido no login
+// por matrícula + PIN, e só na mesma empresa (tenant) em que ele foi gerado.
+// A cada requisição confere no banco se o funcionario segue ativo e se o PIN não foi trocado depois do login
+func AutenticacaoFuncionario(querie *repository.Queries) gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		const portador = "Bearer "
+		header := ctx.GetHeader("Authorization")
+
+		if !strings.HasPrefix(header, portador) {
+
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token nao encontrado ou em formato invalido"})
+			return
+		}
+
+		token, err := jwt.Parse(header[len(portador):], func(t *jwt.Token) (interface{}, error) {
+
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("método de assinatura inesperado: %v", t.Header["alg"])
+			}
+
+			return []byte(os.Getenv("JWT_SECRET")), nil
+		})
+		if err != nil || !token.Valid {
+
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token invalido ou expirado"})
+			return
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token invalido ou expirado"})
+			return
+		}
+
+		tipo, _ := claims["tipo"].(string)
+		id, okId := claims["sub"].(float64)
+		tenantToken, okTenant := claims["tenant"].(float64)
+		pinToken, okPin := claims["pin"].(float64)
+		if tipo != auth.TipoFuncionario || !okId || !okTenant || !okPin {
+
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token invalido ou expirado"})
+			return
+		}
+
+		tenantId, ok := GetTenantID(ctx)
+		if !ok || int32(tenantToken) != tenantId {
+
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token invalido para esta empresa"})
+			return
+		}
+
+		pinVersao, err := querie.BuscarSessaoPortal(ctx.Request.Context(), repository.BuscarSessaoPortalParams{
+			ID:       int32(id),
+			TenantID: tenantId,
+		})
+		if err != nil {
+
+			if errors.Is(err, pgx.ErrNoRows) {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "acesso ao portal encerrado, procure o responsavel pelos epis"})
+				return
+			}
+
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Erro interno ao validar o acesso"})
+			return
+		}
+
+		if int32(pinToken) != pinVersao {
+
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "o PIN foi alterado, faça login novamente"})
+			return
+		}
+
+		ctx.Set(FuncionarioId, int32(id))
+
+		ctx.Next()
+	}
+}
+
+func GetFuncionarioID(c *gin.Context) (int32, bool) {
+
+	val, existe := c.Get(FuncionarioId)
+	if !existe {
+		return 0, false
+	}
+
+	id, ok := val.(int32)
+	return id, ok
+}
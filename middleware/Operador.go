@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderOperador leva o token do operador do sistema, separado do JWT dos usuarios das empresas
+const HeaderOperador = "X-Operador-Token"
+
+// AutenticacaoOperador libera rotas que mexem em dados de todas as empresas (como o catálogo do CAEPI)
+// só para quem tem o OPERADOR_TOKEN. Sem a variável definida as rotas ficam desligadas
+func AutenticacaoOperador() gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		esperado := os.Getenv("OPERADOR_TOKEN")
+		if esperado == "" {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "rota desativada neste servidor"})
+			return
+		}
+
+		recebido := ctx.GetHeader(HeaderOperador)
+		if subtle.ConstantTimeCompare([]byte(recebido), []byte(esperado)) != 1 {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token de operador invalido"})
+			return
+		}
+
+		ctx.Next()
+	}
+}